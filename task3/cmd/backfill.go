@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"task3/internal/backfill"
+	"task3/internal/catalog"
+	"task3/internal/fetcher"
+	"task3/internal/storage"
+)
+
+const cbrDynamicURL = "http://www.cbr.ru/scripts/XML_dynamic.asp"
+
+// runBackfill — подкоманда backfill: загрузка истории курсов ЦБ в -store.
+func runBackfill(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	from := fs.String("from", backfill.FirstDate.Format("2006-01-02"), "First day to load, YYYY-MM-DD")
+	to := fs.String("to", time.Now().Format("2006-01-02"), "Last day to load, YYYY-MM-DD")
+	storePath := fs.String("store", "", "Local rates history file (required)")
+	checkpointPath := fs.String("checkpoint", "", "Progress file to resume an interrupted run (default: -store with .checkpoint suffix)")
+	chunkDays := fs.Int("chunk", 365, "Days per chunk; progress is saved after each chunk")
+	retries := fs.Int("retries", 3, "Retries of a request when CBR is unavailable")
+	lang := fs.String("lang", "ru", "Currency names language: ru or en")
+	dailyURL := fs.String("daily-url", cbrDailyURL, "URL of CBR XML_daily")
+	dynamicURL := fs.String("dynamic-url", cbrDynamicURL, "URL of CBR XML_dynamic")
+	catURL := fs.String("catalog-url", cbrValFull, "URL of CBR currency catalog")
+	catPath := fs.String("catalog-cache", defaultCatalogPath(), "Path to cached currency catalog")
+	fs.Parse(args)
+
+	if *storePath == "" {
+		return usageErrorf("-store is required")
+	}
+	fromDate, err := time.Parse("2006-01-02", *from)
+	if err != nil {
+		return usageErrorf("invalid -from: %w", err)
+	}
+	toDate, err := time.Parse("2006-01-02", *to)
+	if err != nil {
+		return usageErrorf("invalid -to: %w", err)
+	}
+	if *checkpointPath == "" {
+		*checkpointPath = *storePath + ".checkpoint"
+	}
+
+	// Прерывание оставляет контрольную точку — следующий запуск продолжит
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cat, err := catalog.NewCache(fetcher.NewCatalogClient(*catURL), *catPath, catalogTTL).Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	url := *dailyURL
+	if *lang == "en" && url == cbrDailyURL {
+		url = cbrDailyEng
+	}
+
+	store, err := storage.Open(*storePath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	b := backfill.New(store, fetcher.NewClient(url),
+		backfill.WithDynamic(fetcher.NewDynamicClient(*dynamicURL), cat.Items(), *lang == "en"),
+		backfill.WithCheckpoint(*checkpointPath),
+		backfill.WithChunkDays(*chunkDays),
+		backfill.WithRetries(*retries, time.Second),
+		backfill.WithProgress(printProgress))
+	return b.Run(ctx, fromDate, toDate)
+}
+
+func printProgress(p backfill.Progress) {
+	mode := "daily"
+	if p.Dynamic {
+		mode = "dynamic"
+	}
+	log.Printf("backfill: %s — %s (%s) chunk %d/%d, %d/%d days, %.0f%%, ETA %s",
+		p.From.Format("2006-01-02"), p.To.Format("2006-01-02"), mode,
+		p.Chunk, p.Chunks, p.Days, p.TotalDays, 100*float64(p.Days)/float64(p.TotalDays),
+		p.ETA.Round(time.Second))
+}
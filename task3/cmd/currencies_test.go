@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"task3/internal/catalog"
+)
+
+func TestCatalogCodes(t *testing.T) {
+	data, err := os.ReadFile("../internal/catalog/testdata/XML_valFull.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cat, err := catalog.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// BYN деноминирован: курсы BYR под другим кодом ЦБ в ряд не попадают
+	codes, err := catalogCodes(cat, "usd,BYN", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(codes, ","); got != "R01235,R01090B" {
+		t.Errorf("Unexpected codes: %s", got)
+	}
+
+	codes, err = catalogCodes(cat, "BYN", true)
+	if err != nil || strings.Join(codes, ",") != "BYN" {
+		t.Errorf("Unexpected ISO codes: %v, %v", codes, err)
+	}
+
+	if _, err := catalogCodes(cat, "XXX", false); err == nil {
+		t.Error("Expected error for unknown currency")
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"task3/internal/app"
+	"task3/internal/fetcher"
+	"task3/internal/parser"
+)
+
+// Коды завершения; exitUsage совпадает с кодом пакета flag при ошибке разбора.
+const (
+	exitOK          = 0
+	exitFailure     = 1
+	exitUsage       = 2
+	exitUnavailable = 3
+	exitHTTPStatus  = 4
+	exitParse       = 5
+	exitNoData      = 6
+)
+
+// usageError — ошибка в аргументах командной строки.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+
+func (e *usageError) Unwrap() error { return e.err }
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{err: fmt.Errorf(format, args...)}
+}
+
+// fail печатает err и завершает процесс с кодом из exitCode.
+func fail(err error) {
+	log.Print(err)
+	os.Exit(exitCode(err))
+}
+
+func exitCode(err error) int {
+	var (
+		usage     *usageError
+		noData    *app.NoDataError
+		parseErr  *parser.ParseError
+		statusErr *fetcher.HTTPStatusError
+	)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &usage):
+		return exitUsage
+	case errors.As(err, &noData):
+		return exitNoData
+	case errors.As(err, &parseErr):
+		return exitParse
+	case errors.Is(err, fetcher.ErrUpstreamUnavailable), errors.Is(err, context.DeadlineExceeded):
+		return exitUnavailable
+	case errors.As(err, &statusErr):
+		return exitHTTPStatus
+	default:
+		return exitFailure
+	}
+}
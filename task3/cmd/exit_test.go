@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task3/internal/app"
+	"task3/internal/fetcher"
+	"task3/internal/parser"
+	"task3/internal/soap"
+)
+
+// soapStatus — ошибка SOAP-клиента на ответ сервера с кодом status.
+func soapStatus(t *testing.T, status int) error {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	var resp struct{}
+	err := soap.NewClient(server.URL).Call(context.Background(), "KeyRate", struct{}{}, &resp)
+	if err == nil {
+		t.Fatalf("Expected error for status %d", status)
+	}
+	return err
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"nil", nil, exitOK},
+		{"usage", usageErrorf("unknown source %q", "fed"), exitUsage},
+		{"wrapped usage", fmt.Errorf("forecast: %w", &usageError{err: errors.New("bad -lang")}), exitUsage},
+		{"no data", fmt.Errorf("run: %w", &app.NoDataError{From: time.Now(), To: time.Now()}), exitNoData},
+		{"parse", fmt.Errorf("failed to parse: %w", &parser.ParseError{Field: "Value", Err: errors.New("bad")}), exitParse},
+		{"5xx", fmt.Errorf("failed to fetch: %w", &fetcher.HTTPStatusError{StatusCode: 503}), exitUnavailable},
+		{"network", fmt.Errorf("%w: dial tcp", fetcher.ErrUpstreamUnavailable), exitUnavailable},
+		{"timeout", fmt.Errorf("failed to fetch: %w", context.DeadlineExceeded), exitUnavailable},
+		{"4xx", fmt.Errorf("failed to fetch: %w", &fetcher.HTTPStatusError{StatusCode: 404}), exitHTTPStatus},
+		{"soap 5xx", fmt.Errorf("key rate: %w", soapStatus(t, http.StatusBadGateway)), exitUnavailable},
+		{"soap 4xx", fmt.Errorf("key rate: %w", soapStatus(t, http.StatusNotFound)), exitHTTPStatus},
+		{"other", errors.New("boom"), exitFailure},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.code {
+			t.Errorf("%s: expected exit code %d, got %d", tt.name, tt.code, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"task3/internal/app"
+	"task3/internal/fetcher"
+	"task3/internal/forecast"
+	"task3/internal/i18n"
+	"task3/internal/reporter"
+	"task3/internal/storage"
+)
+
+// runForecast — подкоманда forecast: прогноз курса валюты ЦБ на несколько
+// рабочих дней по загруженной истории.
+func runForecast(args []string) error {
+	fs := flag.NewFlagSet("forecast", flag.ExitOnError)
+	currency := fs.String("currency", "", "Currency to forecast: ISO code (usd, 840) or CBR ID (R01235), required")
+	days := fs.Int("days", 180, "Days of history to fit the models on")
+	horizon := fs.Int("horizon", 10, "Business days to forecast")
+	models := fs.String("models", "linear,holt", "Comma-separated models: linear, holt, arima (ARIMA(1,1,0))")
+	level := fs.Float64("level", 0.95, "Prediction interval level, 0..1")
+	holdout := fs.Int("backtest", 0, "Also forecast the last N rates from the rest of the history and report MAE and MAPE")
+	format := fs.String("format", "console", "Output format: console, csv or json")
+	lang := fs.String("lang", "ru", "Report language: ru or en")
+	storePath := fs.String("store", "", "Local rates history file: stored days are not fetched again")
+	dailyURL := fs.String("daily-url", cbrDailyURL, "URL of CBR XML_daily")
+	fs.Parse(args)
+
+	if *currency == "" || strings.Contains(*currency, ",") {
+		return usageErrorf("-currency must name one currency")
+	}
+	if *horizon < 1 {
+		return usageErrorf("-horizon must be positive, got %d", *horizon)
+	}
+	if *level <= 0 || *level >= 1 {
+		return usageErrorf("-level must be between 0 and 1, got %v", *level)
+	}
+	if *holdout < 0 {
+		return usageErrorf("-backtest must not be negative, got %d", *holdout)
+	}
+	methods, err := forecast.ParseMethods(*models)
+	if err != nil {
+		return &usageError{err: err}
+	}
+	locale, err := i18n.Get(*lang)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	codes, err := resolveCurrencies(ctx, *currency, false)
+	if err != nil {
+		return err
+	}
+
+	// В сводке будет только эта валюта — код для отчёта не нужен, а числовой
+	// код (840) в сводке и не найти
+	rep, err := reporter.NewForecastReporter(*format, reporter.ForecastConfig{
+		Methods: methods,
+		Horizon: *horizon,
+		Level:   *level,
+		Holdout: *holdout,
+	}, reporter.WithLocale(locale))
+	if err != nil {
+		return err
+	}
+
+	url := *dailyURL
+	if *lang == "en" && url == cbrDailyURL {
+		url = cbrDailyEng
+	}
+	opts := []app.Option{app.WithCurrencies(codes...)}
+	if *storePath != "" {
+		store, err := storage.Open(*storePath)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		opts = append(opts, app.WithStore(store))
+	}
+	return app.NewApp(fetcher.NewClient(url), rep, opts...).Run(ctx, *days, time.Now())
+}
@@ -3,32 +3,506 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"task3/internal/anomaly"
 	"task3/internal/app"
+	"task3/internal/catalog"
+	"task3/internal/dailyinfo"
 	"task3/internal/fetcher"
+	"task3/internal/gaps"
+	"task3/internal/i18n"
+	"task3/internal/model"
+	"task3/internal/parser"
 	"task3/internal/reporter"
+	"task3/internal/storage"
+	"task3/internal/term"
 	"time"
 )
 
+// fetchTimeout — общий лимит на загрузку курсов и показателей.
+const fetchTimeout = 10 * time.Second
+
+const (
+	cbrDailyURL  = "http://www.cbr.ru/scripts/XML_daily.asp"
+	cbrDailyEng  = "http://www.cbr.ru/scripts/XML_daily_eng.asp"
+	ecbHist90URL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+	ecbHistURL   = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+	cbrMetalsURL = "http://www.cbr.ru/scripts/xml_metall.asp"
+	cbrValFull   = "http://www.cbr.ru/scripts/XML_valFull.asp"
+	catalogTTL   = 24 * time.Hour
+	dailyInfoURL = "https://www.cbr.ru/DailyInfoWebServ/DailyInfo.asmx"
+)
+
 var (
-	apiUrl      = flag.String("api-url", "http://www.cbr.ru/scripts/XML_daily_eng.asp", "URL of Central Bank API")
-	daysToFetch = flag.Int("days", 90, "Number of days to fetch")
+	apiUrl        = flag.String("api-url", "", "URL of rates API (default depends on -source)")
+	daysToFetch   = flag.Int("days", 90, "Number of days to fetch")
+	period        = flag.String("period", "", "Report period YYYY-MM-DD..YYYY-MM-DD instead of the last -days days")
+	compareWith   = flag.String("compare", "", "Compare the period with another one: previous (same length right before), year (same dates a year ago) or YYYY-MM-DD..YYYY-MM-DD")
+	source        = flag.String("source", "cbr", "Rates source: cbr or ecb")
+	baseCode      = flag.String("base", "EUR", "Base currency for -source=ecb")
+	instrument    = flag.String("instrument", "currencies", "Instrument: currencies or metals")
+	currencies    = flag.String("currency", "", "Comma-separated currencies to include: ISO codes (usd, 840) or CBR IDs (R01235)")
+	catalogURL    = flag.String("catalog-url", cbrValFull, "URL of CBR currency catalog")
+	catalogPath   = flag.String("catalog-cache", defaultCatalogPath(), "Path to cached currency catalog")
+	indicators    = flag.String("indicators", "", "Comma-separated CBR indicators to print next to the statistics: keyrate")
+	dailyURL      = flag.String("dailyinfo-url", dailyInfoURL, "URL of CBR DailyInfo SOAP service")
+	lenient       = flag.Bool("lenient", false, "Skip invalid Valute entries instead of failing the whole day")
+	lang          = flag.String("lang", "ru", "Report language: ru or en")
+	tmplPath      = flag.String("template", "", "Render the report with a text/template or html/template file, or a built-in one: console.tmpl, console.html")
+	format        = flag.String("format", "console", "Report format: console, terminal (tables, sparklines and charts), markdown, html, xlsx or json")
+	sortBy        = flag.String("sort", "code", "Sort the -format=terminal, markdown and xlsx tables by change, volatility or code")
+	columns       = flag.String("columns", "", "Comma-separated -format=markdown table columns: code, name, first, last, change, min, max, avg, volatility, count, filled")
+	charts        = flag.String("chart", "", "Comma-separated currencies to draw line charts for in -format=terminal")
+	outPath       = flag.String("o", "", "Write the report to a file instead of stdout")
+	storePath     = flag.String("store", "", "Local rates history file for -source=cbr: stored days are not fetched again")
+	gapFill       = flag.String("gaps", "leave", "Fill days without a rate: leave, carry (last official rate, as CBR does) or linear; filled rates are marked in reports")
+	minCoverage   = flag.Float64("min-coverage", 0, "Drop currencies that have rates on less than this share (0..1) of the dates in the period")
+	correlation   = flag.Bool("correlation", false, "Report Pearson and Spearman correlations of daily log returns instead of rate statistics; -format console (heatmap), csv or json")
+	corrPair      = flag.String("pair", "", "Currency pair for rolling correlation with -correlation, e.g. USD/EUR")
+	corrWindow    = flag.Int("window", 20, "Rolling correlation window in dates for -pair")
+	anomalies     = flag.Bool("anomalies", false, "Flag anomalous daily moves: z-score against preceding moves, Hampel filter and nominal changes by 10^k")
+	anomalyWindow = flag.Int("anomaly-window", 20, "Window in daily moves for -anomalies")
+	anomalyZ      = flag.Float64("anomaly-z", 4, "Z-score threshold for -anomalies; 0 disables the check")
+	anomalyHampel = flag.Float64("anomaly-hampel", 3, "Hampel filter threshold in scaled MADs for -anomalies; 0 disables the check")
+	moves         = flag.Bool("moves", false, "Per-currency max drawdown and run-up, largest daily gains and losses and longest up and down streaks in console and JSON output")
+	movesTop      = flag.Int("moves-top", 3, "Largest daily gains and losses to list for -moves")
+	asOf          = flag.String("as-of", "", "Compute statistics as known at this time from -store, RFC 3339 or YYYY-MM-DD (end of day UTC); later revisions are ignored")
+
+	outputs       outputFlag
+	outputMode    = flag.String("output-mode", "sequential", "How to dispatch the report to several -output: sequential or concurrent")
+	outputPolicy  = flag.String("output-policy", "best-effort", "On a failed -output: best-effort (report to the rest, return all errors) or fail-fast")
+	outputTimeout = flag.Duration("output-timeout", 0, "Timeout for each -output, e.g. 5s; 0 means no limit")
 )
 
+func init() {
+	flag.Var(&outputs, "output", "Report destination format:destination, repeatable: console:-, json:report.json, xlsx:report.xlsx, webhook:https://...; replaces -format and -o")
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		if err := runBackfill(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "forecast" {
+		if err := runForecast(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "portfolio" {
+		if err := runPortfolio(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "revisions" {
+		if err := runRevisions(os.Args[2:]); err != nil {
+			fail(err)
+		}
+		return
+	}
 	flag.Parse()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := checkInstrument(); err != nil {
+		fail(err)
+	}
+
+	// Таймаут ограничивает только загрузку: время вывода задаёт -output-timeout
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
 	defer cancel()
 
-	client := fetcher.NewClient(*apiUrl)
-	rep := reporter.NewConsoleReporter()
-	application := app.NewApp(client, rep)
+	locale, err := i18n.Get(*lang)
+	if err != nil {
+		fail(&usageError{err: err})
+	}
+	reportOpts := []reporter.Option{reporter.WithLocale(locale)}
+
+	currentDate, days := time.Now(), *daysToFetch
+	if *period != "" {
+		from, to, err := parsePeriod("-period", *period)
+		if err != nil {
+			fail(&usageError{err: err})
+		}
+		currentDate, days = to, app.Days(from, to)
+	}
+	// Самая ранняя нужная дата: от неё зависит, какой архив ЕЦБ брать
+	earliest := currentDate.AddDate(0, 0, -(days - 1))
+
+	var client fetcher.CurrencyRateFetcher
+	var opts []app.Option
+	if *compareWith != "" {
+		if *instrument != "currencies" || *correlation {
+			fail(usageErrorf("-compare is supported only for currency statistics"))
+		}
+		from, to, err := parseComparison(*compareWith, earliest, currentDate)
+		if err != nil {
+			fail(&usageError{err: err})
+		}
+		if from.Before(earliest) {
+			earliest = from
+		}
+		opts = append(opts, app.WithComparison(from, to))
+	}
+	switch *source {
+	case "cbr":
+		// Названия валют приходят на языке эндпоинта — берём тот, что совпадает с отчётом
+		url := cbrDailyURL
+		if locale.Lang == "en" {
+			url = cbrDailyEng
+		}
+		client = fetcher.NewClient(urlOrDefault(url))
+		opts = append(opts, app.WithSource(reporter.Source{Name: "Bank of Russia", URL: urlOrDefault(url)}))
+	case "ecb":
+		url := ecbHist90URL
+		if app.Days(earliest, time.Now()) > 90 {
+			url = ecbHistURL
+		}
+		client = fetcher.NewECBClient(urlOrDefault(url))
+		opts = append(opts, app.WithSource(reporter.Source{Name: "European Central Bank", URL: urlOrDefault(url)}))
+		opts = append(opts, app.WithParser(func(data []byte) ([]model.CurrencyRate, error) {
+			return parser.ParseECBRates(data, *baseCode)
+		}))
+		reportOpts = append(reportOpts, reporter.WithUnit(strings.ToUpper(*baseCode)))
+	default:
+		fail(usageErrorf("unknown source %q", *source))
+	}
+
+	if *lenient {
+		if *source != "cbr" {
+			fail(usageErrorf("-lenient is supported only for -source=cbr"))
+		}
+		opts = append(opts, app.WithLenientParser(parser.ParseRatesLenient))
+	}
+
+	if *storePath != "" {
+		if *source != "cbr" {
+			fail(usageErrorf("-store is supported only for -source=cbr"))
+		}
+		store, err := storage.Open(*storePath)
+		if err != nil {
+			fail(err)
+		}
+		defer store.Close()
+		opts = append(opts, app.WithStore(store))
+	}
+
+	if *asOf != "" {
+		if *storePath == "" {
+			fail(usageErrorf("-as-of requires -store"))
+		}
+		t, err := parseAsOf(*asOf)
+		if err != nil {
+			fail(&usageError{err: err})
+		}
+		opts = append(opts, app.WithAsOf(t))
+	}
+
+	fill, err := gaps.ParseFill(*gapFill)
+	if err != nil {
+		fail(&usageError{err: err})
+	}
+	if *minCoverage < 0 || *minCoverage > 1 {
+		fail(usageErrorf("-min-coverage must be between 0 and 1, got %v", *minCoverage))
+	}
+	opts = append(opts, app.WithGapPolicy(gaps.Policy{Fill: fill, MinCoverage: *minCoverage}))
+
+	if *anomalies {
+		if *anomalyWindow < 1 {
+			fail(usageErrorf("-anomaly-window must be positive, got %d", *anomalyWindow))
+		}
+		opts = append(opts, app.WithAnomalies(anomaly.NewDetector(
+			anomaly.WithWindow(*anomalyWindow),
+			anomaly.WithZScore(*anomalyZ),
+			anomaly.WithHampel(*anomalyHampel),
+		)))
+	}
+	if *moves {
+		if *movesTop < 1 {
+			fail(usageErrorf("-moves-top must be positive, got %d", *movesTop))
+		}
+		opts = append(opts, app.WithMoves(*movesTop))
+	}
+
+	if *currencies != "" {
+		codes, err := resolveCurrencies(ctx, *currencies, *source == "ecb")
+		if err != nil {
+			fail(err)
+		}
+		opts = append(opts, app.WithCurrencies(codes...))
+	}
+
+	if *indicators != "" {
+		daily := dailyinfo.NewClient(*dailyURL)
+		for _, name := range strings.Split(*indicators, ",") {
+			switch strings.TrimSpace(name) {
+			case "keyrate":
+				opts = append(opts, app.WithIndicators(daily.KeyRate))
+			default:
+				fail(usageErrorf("unknown indicator %q", name))
+			}
+		}
+	}
+
+	if *correlation {
+		if len(outputs) > 0 || *tmplPath != "" {
+			fail(usageErrorf("-correlation cannot be combined with -output or -template"))
+		}
+		if *corrPair != "" {
+			a, b, ok := strings.Cut(*corrPair, "/")
+			if !ok {
+				fail(usageErrorf("invalid -pair %q: use A/B, e.g. USD/EUR", *corrPair))
+			}
+			reportOpts = append(reportOpts, reporter.WithPair(strings.TrimSpace(a), strings.TrimSpace(b), *corrWindow))
+		}
+	}
+
+	var (
+		rep   reporter.Reporter
+		files []*os.File
+	)
+	if len(outputs) > 0 {
+		if err := checkOutputFlags(flag.CommandLine); err != nil {
+			fail(err)
+		}
+		rep, files, err = newOutputs(outputs, reportOpts)
+	} else {
+		rep, files, err = newSingleReporter(reportOpts)
+	}
+	if err != nil {
+		closeAll(files)
+		fail(err)
+	}
+	rep = reportContext{Reporter: rep, ctx: context.Background()}
+
+	switch *instrument {
+	case "currencies":
+		err = app.NewApp(client, rep, opts...).Run(ctx, days, currentDate)
+	case "metals":
+		metals := fetcher.NewMetalClient(urlOrDefault(cbrMetalsURL))
+		source := reporter.Source{Name: "Bank of Russia", URL: urlOrDefault(cbrMetalsURL)}
+		opts = append(opts, app.WithMetalFetcher(metals), app.WithSource(source))
+		err = app.NewApp(client, rep, opts...).RunMetals(ctx, days, currentDate)
+	}
+	if cerr := closeAll(files); cerr != nil && err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fail(err)
+	}
+
+}
+
+// checkInstrument проверяет -instrument. Для драгметаллов работают -gaps,
+// -min-coverage, -anomalies и -moves; флаги загрузки курсов валют к ним
+// неприменимы и отклоняются, а не игнорируются молча.
+func checkInstrument() error {
+	switch *instrument {
+	case "currencies":
+		return nil
+	case "metals":
+	default:
+		return usageErrorf("unknown instrument %q", *instrument)
+	}
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"-source", *source != "cbr"},
+		{"-currency", *currencies != ""},
+		{"-lenient", *lenient},
+		{"-store", *storePath != ""},
+		{"-as-of", *asOf != ""},
+		{"-indicators", *indicators != ""},
+	} {
+		if f.set {
+			return usageErrorf("%s is not supported with -instrument=metals", f.name)
+		}
+	}
+	return nil
+}
 
-	currentDate := time.Now()
-	err := application.Run(ctx, *daysToFetch, currentDate)
+// parseAsOf разбирает -as-of; дата без времени означает конец дня UTC.
+func parseAsOf(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := time.Parse("2006-01-02", s)
 	if err != nil {
-		log.Fatal(err)
+		return time.Time{}, fmt.Errorf("invalid -as-of %q: use RFC 3339 or YYYY-MM-DD", s)
+	}
+	return d.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
+
+// parsePeriod разбирает период YYYY-MM-DD..YYYY-MM-DD; name — флаг для
+// сообщения об ошибке.
+func parsePeriod(name, s string) (time.Time, time.Time, error) {
+	fromStr, toStr, ok := strings.Cut(s, "..")
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid %s %q: use YYYY-MM-DD..YYYY-MM-DD", name, s)
+	}
+	from, err := time.Parse("2006-01-02", strings.TrimSpace(fromStr))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid %s start: %w", name, err)
+	}
+	to, err := time.Parse("2006-01-02", strings.TrimSpace(toStr))
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid %s end: %w", name, err)
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid %s %q: ends before it starts", name, s)
+	}
+	return from, to, nil
+}
+
+// parseComparison разбирает -compare для периода from..to: previous — столько
+// же дней сразу перед ним, year — те же даты годом раньше, иначе явный
+// период.
+func parseComparison(s string, from, to time.Time) (time.Time, time.Time, error) {
+	switch s {
+	case "previous":
+		end := from.AddDate(0, 0, -1)
+		return end.AddDate(0, 0, -(app.Days(from, to) - 1)), end, nil
+	case "year":
+		return from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0), nil
+	default:
+		return parsePeriod("-compare", s)
+	}
+}
+
+// newSingleReporter строит репортер по -template, -format и -o; шаблон
+// пользователя важнее формата. С -correlation -format выбирает вывод
+// корреляций.
+func newSingleReporter(opts []reporter.Option) (reporter.Reporter, []*os.File, error) {
+	var (
+		out   *os.File
+		files []*os.File
+	)
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		out, files = f, []*os.File{f}
+		opts = append(opts, reporter.WithWriter(out))
 	}
 
+	if *tmplPath != "" {
+		rep, err := reporter.NewTemplateReporter(*tmplPath, opts...)
+		return rep, files, err
+	}
+	if *correlation {
+		rep, err := reporter.NewCorrelationReporter(*format, opts...)
+		return rep, files, err
+	}
+	rep, err := newReporter(*format, opts, out)
+	return rep, files, err
+}
+
+// newReporter создаёт репортер формата format. out — файл назначения или nil
+// для stdout.
+func newReporter(format string, opts []reporter.Option, out *os.File) (reporter.Reporter, error) {
+	switch format {
+	case "console":
+		return reporter.NewConsoleReporter(opts...), nil
+	case "terminal":
+		if out == nil {
+			out = os.Stdout
+		}
+		width, color := term.Detect(out)
+		opts = append(opts, reporter.WithWidth(width), reporter.WithColor(color), reporter.WithSort(*sortBy))
+		if *charts != "" {
+			opts = append(opts, reporter.WithCharts(strings.Split(*charts, ",")...))
+		}
+		return reporter.NewTerminalReporter(opts...)
+	case "markdown":
+		opts = append(opts, reporter.WithSort(*sortBy))
+		if *columns != "" {
+			opts = append(opts, reporter.WithColumns(strings.Split(*columns, ",")...))
+		}
+		return reporter.NewMarkdownReporter(opts...)
+	case "html":
+		return reporter.NewHTMLReporter(opts...)
+	case "xlsx":
+		if out == nil && term.IsTerminal(os.Stdout) {
+			return nil, fmt.Errorf("-format=xlsx writes a binary workbook: use -o report.xlsx or redirect stdout")
+		}
+		return reporter.NewXLSXReporter(append(opts, reporter.WithSort(*sortBy))...), nil
+	case "json":
+		return reporter.NewJSONReporter(opts...), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// closeAll закрывает файлы отчётов и возвращает первую ошибку: для записанного
+// файла она значит, что отчёт мог не сохраниться.
+func closeAll(files []*os.File) error {
+	var first error
+	for _, f := range files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func urlOrDefault(def string) string {
+	if *apiUrl != "" {
+		return *apiUrl
+	}
+	return def
+}
+
+func defaultCatalogPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "procontext", "XML_valFull.xml")
+}
+
+// resolveCurrencies переводит ввод пользователя в коды для фильтра приложения.
+func resolveCurrencies(ctx context.Context, input string, isoCodes bool) ([]string, error) {
+	cache := catalog.NewCache(fetcher.NewCatalogClient(*catalogURL), *catalogPath, catalogTTL)
+	cat, err := cache.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return catalogCodes(cat, input, isoCodes)
+}
+
+// catalogCodes находит в справочнике коды валют из ввода через запятую. У
+// валют, менявших код ЦБ при деноминации, берётся только запрошенный код:
+// курсы до и после деноминации несопоставимы (BYR → BYN — в 10 000 раз), а
+// множитель справочник не указывает. Остальные коды перечисляются в
+// предупреждении — их можно запросить отдельно.
+func catalogCodes(cat *catalog.Catalog, input string, isoCodes bool) ([]string, error) {
+	var codes []string
+	for _, in := range strings.Split(input, ",") {
+		item, err := cat.Resolve(in)
+		if err != nil {
+			return nil, err
+		}
+		if lineage := cat.Lineage(item); len(lineage) > 1 {
+			var others []string
+			for _, l := range lineage {
+				if l.ID != item.ID {
+					others = append(others, fmt.Sprintf("%s %s (nominal %d)", l.ID, l.ISOCharCode, l.Nominal))
+				}
+			}
+			log.Printf("warning: %s was redenominated, rates under %s are not included", in, strings.Join(others, ", "))
+		}
+		if isoCodes {
+			codes = append(codes, item.ISOCharCode)
+		} else {
+			codes = append(codes, item.ID)
+		}
+	}
+	return codes, nil
 }
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"task3/internal/reporter"
+)
+
+// outputFlag собирает повторяющиеся -output format:destination.
+type outputFlag []output
+
+type output struct {
+	format string
+	// dest — путь к файлу, URL для webhook или пусто/"-" для stdout.
+	dest string
+}
+
+func (f *outputFlag) String() string {
+	specs := make([]string, 0, len(*f))
+	for _, o := range *f {
+		specs = append(specs, o.format+":"+o.dest)
+	}
+	return strings.Join(specs, ",")
+}
+
+func (f *outputFlag) Set(spec string) error {
+	o, err := parseOutput(spec)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, o)
+	return nil
+}
+
+// parseOutput разбирает format:destination; делится по первому двоеточию,
+// так что URL webhook остаётся целым.
+func parseOutput(spec string) (output, error) {
+	format, dest, _ := strings.Cut(spec, ":")
+	o := output{format: strings.TrimSpace(format), dest: strings.TrimSpace(dest)}
+	switch o.format {
+	case "console", "terminal", "markdown", "html", "xlsx", "json":
+	case "webhook":
+		if !strings.HasPrefix(o.dest, "http://") && !strings.HasPrefix(o.dest, "https://") {
+			return output{}, fmt.Errorf("webhook output needs an http(s) URL, got %q", o.dest)
+		}
+	default:
+		return output{}, fmt.Errorf("unknown output format %q", o.format)
+	}
+	if o.dest == "-" {
+		o.dest = ""
+	}
+	return o, nil
+}
+
+// newOutputs строит MultiReporter из -output и открывает файлы назначения;
+// их нужно закрыть после отчёта, даже если вернулась ошибка.
+// checkOutputFlags отклоняет флаги одиночного вывода рядом с -output: они
+// были бы молча проигнорированы.
+func checkOutputFlags(fs *flag.FlagSet) error {
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "template", "format", "o":
+			if err == nil {
+				err = usageErrorf("-%s cannot be combined with -output: use -output format:destination", f.Name)
+			}
+		}
+	})
+	return err
+}
+
+func newOutputs(outputs []output, opts []reporter.Option) (*reporter.MultiReporter, []*os.File, error) {
+	multiOpts := []reporter.MultiOption{reporter.WithTimeout(*outputTimeout)}
+	switch *outputMode {
+	case "sequential":
+	case "concurrent":
+		multiOpts = append(multiOpts, reporter.Concurrently())
+	default:
+		return nil, nil, usageErrorf("unknown output mode %q: use sequential or concurrent", *outputMode)
+	}
+	switch *outputPolicy {
+	case "best-effort":
+		multiOpts = append(multiOpts, reporter.WithPolicy(reporter.BestEffort))
+	case "fail-fast":
+		multiOpts = append(multiOpts, reporter.WithPolicy(reporter.FailFast))
+	default:
+		return nil, nil, usageErrorf("unknown output policy %q: use best-effort or fail-fast", *outputPolicy)
+	}
+
+	var (
+		targets []reporter.Target
+		files   []*os.File
+		stdout  bool
+	)
+	for _, o := range outputs {
+		name := o.format
+		if o.dest != "" {
+			name += ":" + o.dest
+		}
+
+		if o.format == "webhook" {
+			targets = append(targets, reporter.Target{Name: name, Reporter: reporter.NewWebhookReporter(o.dest)})
+			continue
+		}
+
+		// Два отчёта в stdout перемешались бы
+		var out *os.File
+		if o.dest == "" {
+			if stdout {
+				return nil, files, usageErrorf("only one -output may write to stdout")
+			}
+			stdout = true
+		} else {
+			f, err := os.Create(o.dest)
+			if err != nil {
+				return nil, files, err
+			}
+			files = append(files, f)
+			out = f
+		}
+
+		targetOpts := opts
+		if out != nil {
+			targetOpts = append(append([]reporter.Option(nil), opts...), reporter.WithWriter(out))
+		}
+		rep, err := newReporter(o.format, targetOpts, out)
+		if err != nil {
+			return nil, files, fmt.Errorf("%s: %w", name, err)
+		}
+		targets = append(targets, reporter.Target{Name: name, Reporter: rep})
+	}
+	return reporter.NewMultiReporter(targets, multiOpts...), files, nil
+}
+
+// reportContext вызывает репортер в контексте ctx вместо контекста загрузки,
+// чтобы таймаут загрузки не обрывал вывод.
+type reportContext struct {
+	reporter.Reporter
+	ctx context.Context
+}
+
+func (r reportContext) Report(_ context.Context, summary reporter.Summary) error {
+	return r.Reporter.Report(r.ctx, summary)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"task3/internal/reporter"
+)
+
+func TestParseOutput(t *testing.T) {
+	tests := []struct {
+		spec string
+		want output
+	}{
+		{"console", output{format: "console"}},
+		{"console:-", output{format: "console"}},
+		{"json:report.json", output{format: "json", dest: "report.json"}},
+		// URL делится только по первому двоеточию
+		{"webhook:https://example.com:8443/hook", output{format: "webhook", dest: "https://example.com:8443/hook"}},
+	}
+	for _, tt := range tests {
+		got, err := parseOutput(tt.spec)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.spec, tt.want, got)
+		}
+	}
+
+	for _, spec := range []string{"pdf:report.pdf", "webhook:report.json", "webhook"} {
+		if _, err := parseOutput(spec); err == nil {
+			t.Errorf("%s: expected error", spec)
+		}
+	}
+}
+
+func TestOutputFlag(t *testing.T) {
+	var f outputFlag
+	for _, spec := range []string{"console:-", "json:out.json"} {
+		if err := f.Set(spec); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(f) != 2 || f.String() != "console:,json:out.json" {
+		t.Errorf("Unexpected outputs: %s", f.String())
+	}
+}
+
+// ctxReporter запоминает ошибку контекста, с которым его вызвали.
+type ctxReporter struct {
+	err error
+}
+
+func (r *ctxReporter) Report(ctx context.Context, _ reporter.Summary) error {
+	r.err = ctx.Err()
+	return nil
+}
+
+func TestReportContext(t *testing.T) {
+	// Загрузка исчерпала свой таймаут — вывод идёт в собственном контексте
+	fetchCtx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-fetchCtx.Done()
+
+	inner := &ctxReporter{}
+	rep := reportContext{Reporter: inner, ctx: context.Background()}
+	if err := rep.Report(fetchCtx, reporter.Summary{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if inner.err != nil {
+		t.Errorf("Reporter got the fetch context: %v", inner.err)
+	}
+}
+
+func TestCheckOutputFlags(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"-output", "json:r.json", "-days", "5"}, true},
+		{[]string{"-output", "json:r.json", "-format", "console"}, false},
+		{[]string{"-output", "json:r.json", "-o", "r.txt"}, false},
+		{[]string{"-output", "json:r.json", "-template", "console.tmpl"}, false},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("format", "console", "")
+		fs.String("o", "", "")
+		fs.String("template", "", "")
+		fs.Int("days", 10, "")
+		var outs outputFlag
+		fs.Var(&outs, "output", "")
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+
+		err := checkOutputFlags(fs)
+		var usage *usageError
+		if tt.ok && err != nil || !tt.ok && !errors.As(err, &usage) {
+			t.Errorf("%v: unexpected result %v", tt.args, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseComparison(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	from, to := day(2025, 7, 1), day(2025, 9, 30)
+
+	tests := []struct {
+		in       string
+		from, to time.Time
+	}{
+		// Квартал из 92 дней — 92 дня перед ним
+		{"previous", day(2025, 3, 31), day(2025, 6, 30)},
+		{"year", day(2024, 7, 1), day(2024, 9, 30)},
+		{"2025-04-01..2025-06-30", day(2025, 4, 1), day(2025, 6, 30)},
+	}
+	for _, tt := range tests {
+		gotFrom, gotTo, err := parseComparison(tt.in, from, to)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if !gotFrom.Equal(tt.from) || !gotTo.Equal(tt.to) {
+			t.Errorf("%s: expected %v..%v, got %v..%v", tt.in, tt.from, tt.to, gotFrom, gotTo)
+		}
+	}
+
+	for _, in := range []string{"quarter", "2025-06-30..2025-04-01", "2025-04-01"} {
+		if _, _, err := parseComparison(in, from, to); err == nil {
+			t.Errorf("%s: expected error", in)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"task3/internal/app"
+	"task3/internal/fetcher"
+	"task3/internal/i18n"
+	"task3/internal/portfolio"
+	"task3/internal/reporter"
+	"task3/internal/storage"
+)
+
+// runPortfolio — подкоманда portfolio: стоимость остатков в валютах по
+// курсам ЦБ на каждую дату периода.
+func runPortfolio(args []string) error {
+	fs := flag.NewFlagSet("portfolio", flag.ExitOnError)
+	holdingsPath := fs.String("holdings", "", "Holdings file, .csv (currency,amount,acquired) or .json, required")
+	base := fs.String("base", portfolio.RUB, "Valuation currency: RUB or a currency with CBR rates, valued via cross-rates")
+	days := fs.Int("days", 90, "Number of days to value")
+	periodFlag := fs.String("period", "", "Valuation period YYYY-MM-DD..YYYY-MM-DD instead of the last -days days")
+	format := fs.String("format", "console", "Output format: console, csv or json")
+	lang := fs.String("lang", "ru", "Report language: ru or en")
+	storePath := fs.String("store", "", "Local rates history file: stored days are not fetched again")
+	dailyURL := fs.String("daily-url", cbrDailyURL, "URL of CBR XML_daily")
+	fs.Parse(args)
+
+	if *holdingsPath == "" {
+		return usageErrorf("-holdings is required")
+	}
+	holdings, err := portfolio.Load(*holdingsPath)
+	if err != nil {
+		return err
+	}
+	locale, err := i18n.Get(*lang)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	currentDate, n := time.Now(), *days
+	if *periodFlag != "" {
+		from, to, err := parsePeriod("-period", *periodFlag)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		currentDate, n = to, app.Days(from, to)
+	}
+	if n < 1 {
+		return usageErrorf("-days must be positive, got %d", n)
+	}
+	from := currentDate.AddDate(0, 0, -(n - 1))
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	// Курсы нужны и на даты покупок раньше периода — для их стоимости
+	if earliest := portfolio.Earliest(holdings); !earliest.IsZero() && earliest.Before(from) {
+		n = app.Days(earliest, currentDate)
+	}
+
+	codes := portfolio.Codes(holdings)
+	if !strings.EqualFold(*base, portfolio.RUB) {
+		codes = append(codes, *base)
+	}
+	if len(codes) == 0 {
+		return fmt.Errorf("holdings are all in %s, nothing to value", portfolio.RUB)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ids, err := resolveCurrencies(ctx, strings.Join(codes, ","), false)
+	if err != nil {
+		return err
+	}
+
+	rep, err := reporter.NewPortfolioReporter(*format, reporter.PortfolioConfig{
+		Holdings: holdings,
+		Base:     *base,
+		From:     from,
+	}, reporter.WithLocale(locale))
+	if err != nil {
+		return err
+	}
+
+	url := *dailyURL
+	if *lang == "en" && url == cbrDailyURL {
+		url = cbrDailyEng
+	}
+	opts := []app.Option{app.WithCurrencies(ids...)}
+	if *storePath != "" {
+		store, err := storage.Open(*storePath)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		opts = append(opts, app.WithStore(store))
+	}
+	return app.NewApp(fetcher.NewClient(url), rep, opts...).Run(ctx, n, currentDate)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"task3/internal/fetcher"
+	"task3/internal/i18n"
+	"task3/internal/reporter"
+	"task3/internal/revision"
+	"task3/internal/storage"
+)
+
+// runRevisions — подкоманда revisions: повторная загрузка сохранённых в
+// -store дней и запись исправлений курсов ЦБ.
+func runRevisions(args []string) error {
+	fs := flag.NewFlagSet("revisions", flag.ExitOnError)
+	from := fs.String("from", time.Now().AddDate(0, 0, -30).Format("2006-01-02"), "First stored day to check, YYYY-MM-DD")
+	to := fs.String("to", time.Now().Format("2006-01-02"), "Last stored day to check, YYYY-MM-DD")
+	storePath := fs.String("store", "", "Local rates history file (required)")
+	lang := fs.String("lang", "ru", "Report and currency names language: ru or en")
+	dailyURL := fs.String("daily-url", cbrDailyURL, "URL of CBR XML_daily")
+	fs.Parse(args)
+
+	if *storePath == "" {
+		return usageErrorf("-store is required")
+	}
+	fromDate, err := time.Parse("2006-01-02", *from)
+	if err != nil {
+		return usageErrorf("invalid -from: %w", err)
+	}
+	toDate, err := time.Parse("2006-01-02", *to)
+	if err != nil {
+		return usageErrorf("invalid -to: %w", err)
+	}
+	locale, err := i18n.Get(*lang)
+	if err != nil {
+		return &usageError{err: err}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Названия не входят ни в хэш, ни в версии журнала — язык влияет только
+	// на названия новых дней
+	url := *dailyURL
+	if *lang == "en" && url == cbrDailyURL {
+		url = cbrDailyEng
+	}
+
+	store, err := storage.Open(*storePath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	result, err := revision.NewChecker(store, fetcher.NewClient(url)).Run(ctx, fromDate, toDate)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(locale.T("revisions.summary", result.Checked, len(result.Changed)))
+	if len(result.Revisions) == 0 {
+		return nil
+	}
+	fmt.Println(locale.T("revisions.title", len(result.Revisions)))
+	for _, r := range result.Revisions {
+		fmt.Println(reporter.RevisionLine(locale, r))
+	}
+	return nil
+}
@@ -5,6 +5,5 @@ go 1.25.1
 require (
 	golang.org/x/net v0.46.0
 	golang.org/x/sync v0.17.0
+	golang.org/x/text v0.30.0
 )
-
-require golang.org/x/text v0.30.0 // indirect
@@ -0,0 +1,209 @@
+package anomaly
+
+import (
+	"math"
+	"sort"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+const (
+	defaultWindow    = 20
+	defaultZScore    = 4
+	defaultHampel    = 3
+	defaultTolerance = 0.05
+	// madScale переводит MAD в оценку стандартного отклонения для
+	// нормального распределения.
+	madScale = 1.4826
+	// minScale — нижняя граница разброса (0,1% в день): у почти
+	// неподвижного курса разброс близок к нулю, и любое движение на шаг
+	// округления выглядело бы выбросом.
+	minScale = 0.001
+)
+
+// Detector ищет аномальные дневные изменения курсов. Ряд каждой валюты —
+// лог-доходности между соседними официальными курсами; восстановленные
+// политикой пропусков курсы не участвуют.
+type Detector struct {
+	window    int
+	zScore    float64
+	hampel    float64
+	tolerance float64
+}
+
+type Option func(*Detector)
+
+// WithWindow задаёт окно в изменениях: предыдущие дни для z-оценки и
+// соседние дни для фильтра Хампеля.
+func WithWindow(window int) Option {
+	return func(d *Detector) {
+		if window > 0 {
+			d.window = window
+		}
+	}
+}
+
+// WithZScore задаёт порог z-оценки (в стандартных отклонениях); 0 отключает
+// проверку.
+func WithZScore(threshold float64) Option {
+	return func(d *Detector) {
+		d.zScore = threshold
+	}
+}
+
+// WithHampel задаёт порог фильтра Хампеля в масштабированных MAD; 0
+// отключает проверку.
+func WithHampel(threshold float64) Option {
+	return func(d *Detector) {
+		d.hampel = threshold
+	}
+}
+
+func NewDetector(opts ...Option) *Detector {
+	d := &Detector{
+		window:    defaultWindow,
+		zScore:    defaultZScore,
+		hampel:    defaultHampel,
+		tolerance: defaultTolerance,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Detect проверяет каждую валюту и возвращает аномалии по датам, на каждое
+// изменение — не больше одной: смена номинала важнее z-оценки, z-оценка —
+// фильтра Хампеля.
+func (d *Detector) Detect(currencies []stats.Currency) []model.Anomaly {
+	var result []model.Anomaly
+	for _, c := range currencies {
+		result = append(result, d.detect(c.Points)...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].Rate.Date.Equal(result[j].Rate.Date) {
+			return result[i].Rate.Date.Before(result[j].Rate.Date)
+		}
+		return stats.Key(result[i].Rate) < stats.Key(result[j].Rate)
+	})
+	return result
+}
+
+func (d *Detector) detect(points []model.CurrencyRate) []model.Anomaly {
+	var official []model.CurrencyRate
+	for _, p := range points {
+		if !p.Filled && p.Rate > 0 {
+			official = append(official, p)
+		}
+	}
+	if len(official) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, len(official)-1)
+	for i := range returns {
+		returns[i] = math.Log(official[i+1].Rate / official[i].Rate)
+	}
+
+	// Скачки из-за номинала не должны раздувать разброс для соседних дней
+	nominal := make([]bool, len(returns))
+	var result []model.Anomaly
+	anomaly := func(i int, reason string, score float64) model.Anomaly {
+		return model.Anomaly{Prev: official[i], Rate: official[i+1], Return: returns[i], Reason: reason, Score: score}
+	}
+	for i, r := range returns {
+		if factor, ok := d.nominalJump(r); ok {
+			nominal[i] = true
+			result = append(result, anomaly(i, model.AnomalyNominal, factor))
+		}
+	}
+
+	for i, r := range returns {
+		if nominal[i] {
+			continue
+		}
+		if d.zScore > 0 {
+			if z, ok := zScore(r, baseline(returns, nominal, i-d.window, i)); ok && math.Abs(z) >= d.zScore {
+				result = append(result, anomaly(i, model.AnomalyZScore, z))
+				continue
+			}
+		}
+		if d.hampel > 0 {
+			half := d.window / 2
+			if score, ok := hampel(r, baseline(returns, nominal, i-half, i+half+1)); ok && math.Abs(score) >= d.hampel {
+				result = append(result, anomaly(i, model.AnomalyHampel, score))
+			}
+		}
+	}
+	return result
+}
+
+// nominalJump — курс изменился в 10^k раз (k ≠ 0) с точностью tolerance.
+// Возвращает отношение нового курса к прежнему.
+func (d *Detector) nominalJump(r float64) (float64, bool) {
+	power := math.Round(r / math.Ln10)
+	if power == 0 {
+		return 0, false
+	}
+	if math.Abs(r-power*math.Ln10) > math.Log1p(d.tolerance) {
+		return 0, false
+	}
+	return math.Exp(r), true
+}
+
+// baseline — изменения из [from, to) без скачков номинала; текущее
+// изменение в окно Хампеля входит, как и положено фильтру.
+func baseline(returns []float64, nominal []bool, from, to int) []float64 {
+	from, to = max(from, 0), min(to, len(returns))
+	var values []float64
+	for i := from; i < to; i++ {
+		if !nominal[i] {
+			values = append(values, returns[i])
+		}
+	}
+	return values
+}
+
+// zScore сравнивает r со средним и стандартным отклонением предыдущих
+// изменений; истории меньше пяти изменений недостаточно.
+func zScore(r float64, history []float64) (float64, bool) {
+	if len(history) < 5 {
+		return 0, false
+	}
+	var mean float64
+	for _, v := range history {
+		mean += v
+	}
+	mean /= float64(len(history))
+	var sum float64
+	for _, v := range history {
+		sum += (v - mean) * (v - mean)
+	}
+	std := math.Sqrt(sum / float64(len(history)-1))
+	return (r - mean) / max(std, minScale), true
+}
+
+// hampel — отклонение r от медианы окна в масштабированных MAD.
+func hampel(r float64, window []float64) (float64, bool) {
+	if len(window) < 5 {
+		return 0, false
+	}
+	med := median(window)
+	deviations := make([]float64, len(window))
+	for i, v := range window {
+		deviations[i] = math.Abs(v - med)
+	}
+	mad := madScale * median(deviations)
+	return (r - med) / max(mad, minScale), true
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
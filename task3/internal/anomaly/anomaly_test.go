@@ -0,0 +1,97 @@
+package anomaly
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+// usd строит ряд с небольшими колебаниями около 80 и подменяет курсы по
+// индексам из override.
+func usd(n int, override map[int]float64) stats.Currency {
+	var rates []model.CurrencyRate
+	for i := 0; i < n; i++ {
+		rate := 80 + 0.3*math.Sin(float64(i)*1.7)
+		if v, ok := override[i]; ok {
+			rate = v
+		}
+		rates = append(rates, model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: rate, Date: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)})
+	}
+	return stats.ByCurrency(rates)[0]
+}
+
+func TestDetector_Quiet(t *testing.T) {
+	if got := NewDetector().Detect([]stats.Currency{usd(40, nil)}); len(got) != 0 {
+		t.Errorf("Expected no anomalies, got %+v", got)
+	}
+}
+
+func TestDetector_Jump(t *testing.T) {
+	// Курс скачком вырос на 8% и остался
+	override := map[int]float64{}
+	for i := 30; i < 40; i++ {
+		override[i] = 86.4 + 0.3*math.Sin(float64(i)*1.7)
+	}
+	got := NewDetector().Detect([]stats.Currency{usd(40, override)})
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 anomaly, got %+v", got)
+	}
+	a := got[0]
+	if a.Reason != model.AnomalyZScore || a.Score < 4 || a.Rate.Date.Day() != 1 || a.Rate.Date.Month() != time.October {
+		t.Errorf("Unexpected anomaly: %+v", a)
+	}
+}
+
+func TestDetector_Spike(t *testing.T) {
+	// Одиночное подозрительное значение: отмечены и скачок, и возврат
+	got := NewDetector().Detect([]stats.Currency{usd(40, map[int]float64{25: 84})})
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 anomalies, got %+v", got)
+	}
+	if got[0].Rate.Rate != 84 || got[0].Score < 4 || got[1].Prev.Rate != 84 || got[1].Score > -4 {
+		t.Errorf("Unexpected anomalies: %+v", got)
+	}
+
+	// Возврат после скачка раздувает разброс для z-оценки, но не медиану
+	got = NewDetector(WithZScore(0)).Detect([]stats.Currency{usd(40, map[int]float64{25: 84})})
+	if len(got) != 2 || got[1].Reason != model.AnomalyHampel || got[1].Score > -3 {
+		t.Errorf("Unexpected Hampel anomalies: %+v", got)
+	}
+}
+
+func TestDetector_Nominal(t *testing.T) {
+	// Курс за 10 единиц вместо одной и обратно
+	got := NewDetector().Detect([]stats.Currency{usd(40, map[int]float64{20: 801.2, 21: 802})})
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 anomalies, got %+v", got)
+	}
+	for _, a := range got {
+		if a.Reason != model.AnomalyNominal {
+			t.Errorf("Expected nominal anomaly, got %+v", a)
+		}
+	}
+	if math.Abs(got[0].Score-10) > 0.5 || math.Abs(got[1].Score-0.1) > 0.005 {
+		t.Errorf("Unexpected factors: %v %v", got[0].Score, got[1].Score)
+	}
+}
+
+func TestDetector_Sensitivity(t *testing.T) {
+	override := map[int]float64{25: 80.7}
+	if got := NewDetector().Detect([]stats.Currency{usd(40, override)}); len(got) != 0 {
+		t.Errorf("Default sensitivity must ignore a moderate move: %+v", got)
+	}
+	if got := NewDetector(WithZScore(2), WithHampel(0)).Detect([]stats.Currency{usd(40, override)}); len(got) == 0 {
+		t.Error("Lower threshold must flag the move")
+	}
+}
+
+func TestDetector_SkipsFilled(t *testing.T) {
+	c := usd(40, map[int]float64{25: 84})
+	c.Points[25].Filled = true
+	if got := NewDetector().Detect([]stats.Currency{c}); len(got) != 0 {
+		t.Errorf("Filled points must be ignored: %+v", got)
+	}
+}
@@ -2,12 +2,18 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"task3/internal/anomaly"
 	"task3/internal/fetcher"
+	"task3/internal/gaps"
 	"task3/internal/model"
 	"task3/internal/parser"
 	"task3/internal/reporter"
+	"task3/internal/stats"
+	"task3/internal/storage"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -15,67 +21,316 @@ import (
 
 const workersNum = 10
 
+type ParseFunc func([]byte) ([]model.CurrencyRate, error)
+
+// LenientParseFunc разбирает день, пропуская битые записи, например parser.ParseRatesLenient.
+type LenientParseFunc func([]byte) ([]model.CurrencyRate, []model.Diagnostic, error)
+
+// IndicatorFunc загружает ряд показателя за период, например dailyinfo.Client.KeyRate.
+type IndicatorFunc func(ctx context.Context, from, to time.Time) (model.Series, error)
+
 type App struct {
-	fetcher  fetcher.CurrencyRateFetcher
-	reporter reporter.Reporter
+	fetcher    fetcher.CurrencyRateFetcher
+	reporter   reporter.Reporter
+	parse      ParseFunc
+	lenient    LenientParseFunc
+	metals     fetcher.MetalFetcher
+	codes      map[string]bool
+	indicators []IndicatorFunc
+	source     reporter.Source
+	store      storage.Store
+	asOf       time.Time
+	gaps       gaps.Policy
+	anomalies  *anomaly.Detector
+	compare    *reporter.Period
+	moves      int
+}
+
+type Option func(*App)
+
+// WithParser подменяет разбор ответа фетчера, например для источника ЕЦБ.
+func WithParser(parse ParseFunc) Option {
+	return func(a *App) {
+		a.parse = parse
+	}
+}
+
+// WithLenientParser включает нестрогий разбор: пропущенные записи попадают
+// в Summary.Diagnostics.
+func WithLenientParser(parse LenientParseFunc) Option {
+	return func(a *App) {
+		a.lenient = parse
+	}
+}
+
+func WithMetalFetcher(metals fetcher.MetalFetcher) Option {
+	return func(a *App) {
+		a.metals = metals
+	}
+}
+
+// WithCurrencies ограничивает статистику валютами с указанными кодами ЦБ
+// (ID) или буквенными ISO-кодами.
+func WithCurrencies(codes ...string) Option {
+	return func(a *App) {
+		if len(codes) == 0 {
+			return
+		}
+		a.codes = make(map[string]bool, len(codes))
+		for _, code := range codes {
+			a.codes[code] = true
+		}
+	}
+}
+
+// WithIndicators добавляет к отчёту ряды показателей за тот же период
+// (Summary.Indicators).
+func WithIndicators(indicators ...IndicatorFunc) Option {
+	return func(a *App) {
+		a.indicators = append(a.indicators, indicators...)
+	}
+}
+
+// WithSource указывает источник курсов для заголовка отчёта.
+func WithSource(source reporter.Source) Option {
+	return func(a *App) {
+		a.source = source
+	}
+}
+
+// WithStore берёт курсы из локального хранилища и запрашивает у фетчера
+// только дни, которых в нём нет; полученные дни сохраняются.
+func WithStore(store storage.Store) Option {
+	return func(a *App) {
+		a.store = store
+	}
+}
+
+// WithAsOf считает статистику по состоянию знаний на asOf: исправления,
+// найденные позже, не учитываются, а недостающие дни не запрашиваются.
+// Нужно хранилище с историей (storage.History).
+func WithAsOf(asOf time.Time) Option {
+	return func(a *App) {
+		a.asOf = asOf
+	}
+}
+
+// WithGapPolicy дополняет ряды курсов за период по политике пропусков до
+// расчёта статистики, так что её видят все отчёты.
+func WithGapPolicy(policy gaps.Policy) Option {
+	return func(a *App) {
+		a.gaps = policy
+	}
+}
+
+// WithAnomalies проверяет ряды валют детектором и добавляет найденное в
+// Summary.Anomalies.
+func WithAnomalies(detector *anomaly.Detector) Option {
+	return func(a *App) {
+		a.anomalies = detector
+	}
+}
+
+// WithMoves добавляет к статистике каждой валюты просадку, рост, top
+// крупнейших дневных изменений каждого знака и самые длинные серии
+// (stats.Currency.Moves).
+func WithMoves(top int) Option {
+	return func(a *App) {
+		a.moves = top
+	}
 }
 
-func NewApp(fetcher fetcher.CurrencyRateFetcher, reporter reporter.Reporter) *App {
-	return &App{
+// WithComparison сравнивает период отчёта с базовым периодом from..to
+// (включительно): курсы за него проходят тот же конвейер — хранилище,
+// фильтр валют, политику пропусков, — а результат попадает в
+// Summary.Comparison.
+func WithComparison(from, to time.Time) Option {
+	return func(a *App) {
+		a.compare = &reporter.Period{From: from, To: to}
+	}
+}
+
+// Days — сколько календарных дней в периоде from..to включительно.
+func Days(from, to time.Time) int {
+	return int(storage.Day(to).Sub(storage.Day(from)).Hours()/24) + 1
+}
+
+func NewApp(fetcher fetcher.CurrencyRateFetcher, reporter reporter.Reporter, opts ...Option) *App {
+	a := &App{
 		fetcher:  fetcher,
 		reporter: reporter,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
 
 func (a *App) Run(ctx context.Context, daysToFetch int, now time.Time) error {
-	allRates, err := a.fetchAllRates(ctx, daysToFetch, now)
+	history, _ := a.store.(storage.History)
+	if !a.asOf.IsZero() && history == nil {
+		return fmt.Errorf("as-of statistics need a store with revision history")
+	}
+
+	allRates, diagnostics, missing, err := a.fetchAllRates(ctx, daysToFetch, now)
 	if err != nil {
 		return fmt.Errorf("failed to fetch rates: %w", err)
 	}
 
 	if len(allRates) == 0 {
-		return fmt.Errorf("no data collected after %d days", daysToFetch)
+		return &NoDataError{From: now.AddDate(0, 0, -(daysToFetch - 1)), To: now}
 	}
 
-	err = a.calculateAndReport(allRates)
+	period := reporter.Period{From: now.AddDate(0, 0, -(daysToFetch - 1)), To: now}
+	series, err := a.fetchIndicators(ctx, period.From, period.To)
+	if err != nil {
+		return fmt.Errorf("failed to fetch indicators: %w", err)
+	}
+
+	summary, err := a.summarize(period, allRates)
 	if err != nil {
 		return fmt.Errorf("failed to calculate and report: %w", err)
 	}
+	if a.compare != nil {
+		var baseDiagnostics []model.Diagnostic
+		summary.Comparison, baseDiagnostics, err = a.comparison(ctx, summary.Currencies)
+		if err != nil {
+			return err
+		}
+		diagnostics = append(baseDiagnostics, diagnostics...)
+	}
+	summary.Source = a.source
+	summary.Indicators = series
+	summary.Diagnostics = diagnostics
+	summary.MissingDates = missing
+	summary.AsOf = a.asOf
+	if history != nil {
+		summary.Revisions, err = a.revisions(ctx, history, period)
+		if err != nil {
+			return fmt.Errorf("failed to read revisions: %w", err)
+		}
+	}
+
+	if err := a.reporter.Report(ctx, summary); err != nil {
+		return fmt.Errorf("failed to report: %w", err)
+	}
 	return nil
 }
 
-func (a *App) fetchAllRates(ctx context.Context, daysToFetch int, now time.Time) (map[time.Time][]model.CurrencyRate, error) {
+// comparison загружает базовый период и сопоставляет его валюты с
+// currencies. Диагностика разбора базового периода возвращается отдельно,
+// чтобы попасть в отчёт вместе с основной.
+func (a *App) comparison(ctx context.Context, currencies []stats.Currency) (*reporter.Comparison, []model.Diagnostic, error) {
+	base := *a.compare
+	days := Days(base.From, base.To)
+	if days < 1 {
+		return nil, nil, fmt.Errorf("comparison period ends before it starts: %s — %s", base.From.Format("2006-01-02"), base.To.Format("2006-01-02"))
+	}
+	allRates, diagnostics, _, err := a.fetchAllRates(ctx, days, base.To)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch comparison rates: %w", err)
+	}
+	if len(allRates) == 0 {
+		return nil, nil, &NoDataError{From: base.From, To: base.To}
+	}
+	summary, err := a.summarize(base, allRates)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to calculate comparison: %w", err)
+	}
+	return reporter.NewComparison(base, summary.Currencies, currencies), diagnostics, nil
+}
+
+// revisions возвращает исправления курсов отобранных валют за период,
+// известные на момент asOf.
+func (a *App) revisions(ctx context.Context, history storage.History, period reporter.Period) ([]model.Revision, error) {
+	all, err := history.Revisions(ctx, period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+	var revisions []model.Revision
+	for _, r := range all {
+		if !a.asOf.IsZero() && r.Detected.After(a.asOf) {
+			continue
+		}
+		if len(a.filter([]model.CurrencyRate{r.New})) == 0 {
+			continue
+		}
+		revisions = append(revisions, r)
+	}
+	return revisions, nil
+}
+
+func (a *App) fetchIndicators(ctx context.Context, from, to time.Time) ([]model.Series, error) {
+	series := make([]model.Series, 0, len(a.indicators))
+	for _, fetch := range a.indicators {
+		s, err := fetch(ctx, from, to)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+// fetchAllRates загружает курсы за каждый день периода. Кроме курсов по дате
+// ValCurs возвращает диагностику нестрогого разбора и запрошенные даты, за
+// которые источник не вернул ни одного курса.
+func (a *App) fetchAllRates(ctx context.Context, daysToFetch int, now time.Time) (map[time.Time][]model.CurrencyRate, []model.Diagnostic, []time.Time, error) {
 	eg, gCtx := errgroup.WithContext(ctx)
 	eg.SetLimit(workersNum)
 
 	var mu sync.Mutex
 	allRates := make(map[time.Time][]model.CurrencyRate)
+	var diagnostics []model.Diagnostic
+	var missing []time.Time
+	addMissing := func(date time.Time) {
+		mu.Lock()
+		missing = append(missing, time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()))
+		mu.Unlock()
+	}
+
+	var stored map[time.Time][]model.CurrencyRate
+	if a.store != nil {
+		var err error
+		from := now.AddDate(0, 0, -(daysToFetch - 1))
+		if a.asOf.IsZero() {
+			stored, err = a.store.Range(ctx, from, now)
+		} else {
+			stored, err = a.store.(storage.History).RangeAsOf(ctx, from, now, a.asOf)
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to read store: %w", err)
+		}
+	}
 
 	for i := 0; i < daysToFetch; i++ {
 		date := now.AddDate(0, 0, -i)
 		eg.Go(func() error {
-			xml, err := a.fetcher.GetCourseByDate(gCtx, date)
-			if err != nil {
-				return fmt.Errorf("failed to get course by date %v: %w", date, err)
-			}
-
-			if len(xml) == 0 {
-				return nil
-			}
-
-			parsedRates, err := parser.ParseRates(xml)
-			if err != nil {
-				return fmt.Errorf("failed to parse rates for date %v: %w", date, err)
+			parsedRates, ok := stored[storage.Day(date)]
+			// На момент asOf дня не было в хранилище — запрашивать его
+			// сейчас значило бы подмешать более поздние знания
+			if !ok && a.asOf.IsZero() {
+				var diags []model.Diagnostic
+				var err error
+				parsedRates, diags, err = a.fetchDay(gCtx, date)
+				if err != nil {
+					return err
+				}
+				if len(diags) > 0 {
+					mu.Lock()
+					diagnostics = append(diagnostics, diags...)
+					mu.Unlock()
+				}
 			}
+			parsedRates = a.filter(parsedRates)
 
 			if len(parsedRates) == 0 {
+				addMissing(date)
 				return nil
 			}
 
-			valDate := parsedRates[0].Date
-
 			mu.Lock()
-			allRates[valDate] = parsedRates
+			addRates(allRates, parsedRates)
 			mu.Unlock()
 
 			return nil
@@ -83,39 +338,202 @@ func (a *App) fetchAllRates(ctx context.Context, daysToFetch int, now time.Time)
 	}
 
 	if err := eg.Wait(); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	return allRates, nil
+	sort.Slice(diagnostics, func(i, j int) bool {
+		if !diagnostics[i].Date.Equal(diagnostics[j].Date) {
+			return diagnostics[i].Date.Before(diagnostics[j].Date)
+		}
+		return diagnostics[i].Index < diagnostics[j].Index
+	})
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].Before(missing[j])
+	})
+
+	return allRates, diagnostics, missing, nil
 }
 
-func (a *App) calculateAndReport(allRates map[time.Time][]model.CurrencyRate) error {
-	var minRate, maxRate model.CurrencyRate
-	var totalRate float64
-	totalRateLen := 0
+// addRates раскладывает курсы дня по их собственным датам: на нерабочий день
+// ЦБ отдаёт курсы прошлой даты, а день из хранилища может содержать курсы
+// разных дат. Валюта, у которой курс на эту дату уже есть, не дублируется.
+func addRates(all map[time.Time][]model.CurrencyRate, rates []model.CurrencyRate) {
+	for _, r := range rates {
+		dup := false
+		for _, have := range all[r.Date] {
+			if stats.Key(have) == stats.Key(r) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			all[r.Date] = append(all[r.Date], r)
+		}
+	}
+}
 
+// fetchDay загружает и разбирает курсы на date и сохраняет их в хранилище,
+// если оно задано. День с пропущенными записями не сохраняется, чтобы
+// в следующий раз его запросили заново.
+func (a *App) fetchDay(ctx context.Context, date time.Time) ([]model.CurrencyRate, []model.Diagnostic, error) {
+	rates, diags, err := a.readDay(ctx, date)
+	if err != nil {
+		return nil, nil, err
+	}
+	if a.store != nil && len(rates) > 0 && len(diags) == 0 {
+		if err := a.store.Put(ctx, date, rates); err != nil {
+			return nil, nil, fmt.Errorf("failed to store rates for date %v: %w", date, err)
+		}
+	}
+	return rates, diags, nil
+}
+
+// readDay загружает и разбирает курсы на date. Суточные курсы ЦБ без
+// подменённого разбора читаются потоком (parser.StreamRates), если фетчер
+// это умеет: тело ответа целиком в памяти не держится.
+func (a *App) readDay(ctx context.Context, date time.Time) ([]model.CurrencyRate, []model.Diagnostic, error) {
+	if stream, ok := a.fetcher.(fetcher.StreamFetcher); ok && a.parse == nil && a.lenient == nil {
+		body, err := stream.OpenCourseByDate(ctx, date)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get course by date %v: %w", date, err)
+		}
+		defer body.Close()
+
+		var rates []model.CurrencyRate
+		err = parser.StreamRates(body, func(r model.CurrencyRate) error {
+			rates = append(rates, r)
+			return nil
+		})
+		// Пустой ответ — дня нет, как и в ветке с ответом целиком
+		if errors.Is(err, parser.ErrNoRoot) {
+			return nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse rates for date %v: %w", date, err)
+		}
+		return rates, nil, nil
+	}
+
+	xml, err := a.fetcher.GetCourseByDate(ctx, date)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get course by date %v: %w", date, err)
+	}
+	if len(xml) == 0 {
+		return nil, nil, nil
+	}
+	rates, diags, err := a.parseDay(xml)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse rates for date %v: %w", date, err)
+	}
+	return rates, diags, nil
+}
+
+func (a *App) parseDay(xml []byte) ([]model.CurrencyRate, []model.Diagnostic, error) {
+	switch {
+	case a.lenient != nil:
+		return a.lenient(xml)
+	case a.parse != nil:
+		rates, err := a.parse(xml)
+		return rates, nil, err
+	default:
+		rates, err := parser.ParseRates(xml)
+		return rates, nil, err
+	}
+}
+
+func (a *App) filter(rates []model.CurrencyRate) []model.CurrencyRate {
+	if a.codes == nil {
+		return rates
+	}
+	filtered := rates[:0]
+	for _, r := range rates {
+		if a.codes[r.ID] || a.codes[r.CharCode] {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+func (a *App) summarize(period reporter.Period, allRates map[time.Time][]model.CurrencyRate) (reporter.Summary, error) {
+	var rates []model.CurrencyRate
 	for _, ratesForDay := range allRates {
-		for _, r := range ratesForDay {
-			if totalRateLen == 0 {
-				minRate = r
-				maxRate = r
-			}
-			if r.Rate < minRate.Rate {
-				minRate = r
-			}
-			if r.Rate > maxRate.Rate {
-				maxRate = r
-			}
-			totalRate += r.Rate
-			totalRateLen++
+		rates = append(rates, ratesForDay...)
+	}
+	rates, dropped := a.gaps.Apply(rates, period.From, period.To)
+
+	s, err := stats.Compute(rates)
+	if err != nil {
+		return reporter.Summary{}, err
+	}
+
+	summary := reporter.Summary{
+		Period:     period,
+		Max:        s.Max,
+		Min:        s.Min,
+		Avg:        s.Avg,
+		Count:      s.Count,
+		Currencies: stats.ByCurrency(rates),
+		Gaps:       a.gaps,
+	}
+	if a.anomalies != nil {
+		summary.Anomalies = a.anomalies.Detect(summary.Currencies)
+	}
+	if a.moves > 0 {
+		for i := range summary.Currencies {
+			moves := stats.ComputeMoves(summary.Currencies[i].Points, a.moves)
+			summary.Currencies[i].Moves = &moves
 		}
 	}
+	for _, r := range dropped {
+		code := r.CharCode
+		if code == "" {
+			code = r.Name
+		}
+		summary.Dropped = append(summary.Dropped, code)
+	}
+	return summary, nil
+}
+
+// RunMetals считает статистику учётных цен драгметаллов отдельно по каждому металлу.
+func (a *App) RunMetals(ctx context.Context, daysToFetch int, now time.Time) error {
+	if a.metals == nil {
+		return fmt.Errorf("metal fetcher is not configured")
+	}
 
-	if totalRateLen == 0 {
-		return fmt.Errorf("no rate data found to calculate statistics")
+	from := now.AddDate(0, 0, -(daysToFetch - 1))
+	xml, err := a.metals.GetMetalsByRange(ctx, from, now)
+	if err != nil {
+		return fmt.Errorf("failed to fetch metals: %w", err)
 	}
 
-	avgRub := totalRate / float64(totalRateLen)
-	a.reporter.Report(maxRate, minRate, avgRub)
+	quotes, err := parser.ParseMetals(xml)
+	if err != nil {
+		return fmt.Errorf("failed to parse metals: %w", err)
+	}
+
+	if len(quotes) == 0 {
+		return &NoDataError{From: from, To: now}
+	}
+
+	rates := make([]model.CurrencyRate, 0, len(quotes))
+	for _, q := range quotes {
+		rates = append(rates, model.CurrencyRate{
+			Name: q.Name,
+			Rate: q.Buy,
+			Date: q.Date,
+		})
+	}
+
+	// Цены разных металлов несопоставимы: репортеры выводят их по рядам
+	summary, err := a.summarize(reporter.Period{From: from, To: now}, map[time.Time][]model.CurrencyRate{now: rates})
+	if err != nil {
+		return fmt.Errorf("failed to calculate and report: %w", err)
+	}
+	summary.Source = a.source
+	summary.PerSeries = true
+
+	if err := a.reporter.Report(ctx, summary); err != nil {
+		return fmt.Errorf("failed to report: %w", err)
+	}
 	return nil
 }
@@ -4,11 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"task3/internal/anomaly"
+	"task3/internal/fetcher"
+	"task3/internal/gaps"
 	"task3/internal/model"
+	"task3/internal/parser"
+	"task3/internal/reporter"
+	"task3/internal/storage"
 )
 
 type MockFetcher struct {
@@ -29,8 +42,9 @@ func (m *MockFetcher) GetCourseByDate(ctx context.Context, date time.Time) ([]by
 
 type MockReporter struct {
 	mu         sync.Mutex
-	ReportFn   func(max, min model.CurrencyRate, avg float64)
+	Err        error
 	ReportCall *reportCall
+	Summary    *reporter.Summary
 }
 
 type reportCall struct {
@@ -39,13 +53,21 @@ type reportCall struct {
 	avg float64
 }
 
-func (m *MockReporter) Report(max, min model.CurrencyRate, avg float64) {
+func (m *MockReporter) Report(_ context.Context, summary reporter.Summary) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.ReportCall = &reportCall{max: max, min: min, avg: avg}
-	if m.ReportFn != nil {
-		m.ReportFn(max, min, avg)
-	}
+	m.ReportCall = &reportCall{max: summary.Max, min: summary.Min, avg: summary.Avg}
+	m.Summary = &summary
+	return m.Err
+}
+
+// MockLegacyReporter реализует прежний интерфейс с одним методом Report.
+type MockLegacyReporter struct {
+	Calls []reportCall
+}
+
+func (m *MockLegacyReporter) Report(max, min model.CurrencyRate, avg float64) {
+	m.Calls = append(m.Calls, reportCall{max: max, min: min, avg: avg})
 }
 
 func TestApp_Run_Success(t *testing.T) {
@@ -123,6 +145,84 @@ func TestApp_Run_Success(t *testing.T) {
 	}
 }
 
+// streamFetcher отдаёт дни только потоком: запрос целиком — ошибка теста.
+type streamFetcher struct {
+	t    *testing.T
+	body string
+}
+
+func (f *streamFetcher) GetCourseByDate(context.Context, time.Time) ([]byte, error) {
+	f.t.Error("Daily rates must be streamed")
+	return nil, nil
+}
+
+func (f *streamFetcher) OpenCourseByDate(_ context.Context, date time.Time) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(fmt.Sprintf(f.body, date.Format("02.01.2006")))), nil
+}
+
+func TestApp_Run_StreamsDailyRates(t *testing.T) {
+	f := &streamFetcher{t: t, body: `<ValCurs Date="%s"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>80,50</Value></Valute></ValCurs>`}
+	mockReporter := &MockReporter{}
+	now := time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)
+	if err := NewApp(f, mockReporter).Run(context.Background(), 3, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mockReporter.Summary.Count != 3 || mockReporter.Summary.Avg != 80.5 {
+		t.Errorf("Unexpected summary: %+v", mockReporter.ReportCall)
+	}
+
+	// Подменённый разбор (ЕЦБ, нестрогий режим) получает ответ целиком
+	f = &streamFetcher{t: t}
+	var parsed bool
+	parse := func([]byte) ([]model.CurrencyRate, error) { parsed = true; return nil, nil }
+	err := NewApp(&MockFetcher{FetchFn: func(context.Context, time.Time) ([]byte, error) { return []byte("<x/>"), nil }}, &MockReporter{}, WithParser(parse)).Run(context.Background(), 1, now)
+	if !parsed {
+		t.Errorf("Expected custom parser to be used, got %v", err)
+	}
+}
+
+func TestAddRates(t *testing.T) {
+	sat := time.Date(2025, 10, 4, 0, 0, 0, 0, time.UTC)
+	fri := sat.AddDate(0, 0, -1)
+	all := make(map[time.Time][]model.CurrencyRate)
+	// Суббота и понедельник с курсами субботы, день из хранилища с курсами
+	// двух дат
+	addRates(all, []model.CurrencyRate{{ID: "R01235", Rate: 80, Date: sat}, {ID: "R01239", Rate: 90, Date: sat}})
+	addRates(all, []model.CurrencyRate{{ID: "R01235", Rate: 80, Date: sat}, {ID: "R01239", Rate: 90, Date: sat}})
+	addRates(all, []model.CurrencyRate{{ID: "R01235", Rate: 79, Date: fri}, {ID: "R01239", Rate: 90, Date: sat}})
+
+	if len(all) != 2 || len(all[sat]) != 2 || len(all[fri]) != 1 || all[fri][0].Rate != 79 {
+		t.Errorf("Unexpected rates by date: %+v", all)
+	}
+}
+
+func TestApp_Run_StreamSkipsEmptyDays(t *testing.T) {
+	// Пустой ответ на 9 октября — день без данных, а не ошибка разбора
+	now := time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)
+	f := &streamFetcher{t: t, body: `<ValCurs Date="%s"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>80,50</Value></Valute></ValCurs>`}
+	stream := &emptyDayFetcher{streamFetcher: f, empty: now.AddDate(0, 0, -1)}
+	mockReporter := &MockReporter{}
+	if err := NewApp(stream, mockReporter).Run(context.Background(), 3, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mockReporter.Summary.Count != 2 || len(mockReporter.Summary.MissingDates) != 1 || !mockReporter.Summary.MissingDates[0].Equal(now.AddDate(0, 0, -1)) {
+		t.Errorf("Expected one missing day, got %+v", mockReporter.Summary)
+	}
+}
+
+// emptyDayFetcher отвечает пустым телом на дату empty.
+type emptyDayFetcher struct {
+	*streamFetcher
+	empty time.Time
+}
+
+func (f *emptyDayFetcher) OpenCourseByDate(ctx context.Context, date time.Time) (io.ReadCloser, error) {
+	if date.Equal(f.empty) {
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+	return f.streamFetcher.OpenCourseByDate(ctx, date)
+}
+
 func TestApp_Run_FetchError(t *testing.T) {
 	mockFetcher := &MockFetcher{
 		FetchFn: func(_ context.Context, _ time.Time) ([]byte, error) {
@@ -156,6 +256,10 @@ func TestApp_Run_ParseError(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected error from parser, got nil")
 	}
+	var parseErr *parser.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Errorf("Expected *parser.ParseError, got %v", err)
+	}
 	if mockReporter.ReportCall != nil {
 		t.Error("Reporter should not be called on parse error")
 	}
@@ -171,12 +275,17 @@ func TestApp_Run_NoDataCollected(t *testing.T) {
 	app := NewApp(mockFetcher, mockReporter)
 
 	ctx := context.Background()
-	err := app.Run(ctx, 2, time.Now())
+	now := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
+	err := app.Run(ctx, 2, now)
 	if err == nil {
 		t.Fatal("Expected 'no data collected' error")
 	}
-	if err.Error() != "no data collected after 2 days" {
-		t.Errorf("Unexpected error: %v", err)
+	var noData *NoDataError
+	if !errors.As(err, &noData) {
+		t.Fatalf("Expected *NoDataError, got %v", err)
+	}
+	if !noData.From.Equal(now.AddDate(0, 0, -1)) || !noData.To.Equal(now) {
+		t.Errorf("Unexpected period: %v — %v", noData.From, noData.To)
 	}
 }
 
@@ -196,8 +305,9 @@ func TestApp_Run_NoRatesAfterParsing(t *testing.T) {
 		t.Fatal("Expected error")
 	}
 	// После фетчинга allRates остаётся пустым → ошибка "no data collected"
-	if err.Error() != "no data collected after 1 days" {
-		t.Errorf("Unexpected error: %v", err)
+	var noData *NoDataError
+	if !errors.As(err, &noData) {
+		t.Errorf("Expected *NoDataError, got %v", err)
 	}
 	// Отчёт не должен вызываться
 	if mockReporter.ReportCall != nil {
@@ -241,3 +351,550 @@ func TestApp_Run_ContextCancelled(t *testing.T) {
 		t.Errorf("Expected context.Canceled, got %v", err)
 	}
 }
+
+func TestApp_Run_ECBSource(t *testing.T) {
+	body, err := os.ReadFile("../parser/testdata/eurofxref-hist.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	mockReporter := &MockReporter{}
+	app := NewApp(fetcher.NewECBClient(server.URL), mockReporter, WithParser(func(data []byte) ([]model.CurrencyRate, error) {
+		return parser.ParseECBRates(data, "EUR")
+	}))
+
+	// 17.10.2025 — пятница, 18 и 19 — выходные без данных
+	now := time.Date(2025, time.October, 19, 0, 0, 0, 0, time.UTC)
+	if err := app.Run(context.Background(), 5, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mockReporter.ReportCall == nil {
+		t.Fatal("Reporter.Report was not called")
+	}
+	// Максимум — фунт: 1/0.8690 EUR на 17.10.2025
+	if mockReporter.ReportCall.max.Name != "GBP" || mockReporter.ReportCall.max.Rate != 1/0.8690 {
+		t.Errorf("Unexpected max: %+v", mockReporter.ReportCall.max)
+	}
+	// Минимум — иена: 1/176.32 EUR на 15.10.2025
+	if mockReporter.ReportCall.min.Name != "JPY" || mockReporter.ReportCall.min.Rate != 1/176.32 {
+		t.Errorf("Unexpected min: %+v", mockReporter.ReportCall.min)
+	}
+}
+
+func TestApp_RunMetals(t *testing.T) {
+	body, err := os.ReadFile("../parser/testdata/xml_metall.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("date_req1") != "01/10/2025" || r.URL.Query().Get("date_req2") != "02/10/2025" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	mockReporter := &MockLegacyReporter{}
+	app := NewApp(&MockFetcher{}, reporter.FromLegacy(mockReporter), WithMetalFetcher(fetcher.NewMetalClient(server.URL)))
+
+	now := time.Date(2025, time.October, 2, 0, 0, 0, 0, time.UTC)
+	if err := app.RunMetals(context.Background(), 2, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Прежний репортер получает по отчёту на каждый металл в порядке названий
+	if len(mockReporter.Calls) != 4 {
+		t.Fatalf("Expected 4 reports, got %d", len(mockReporter.Calls))
+	}
+	gold := mockReporter.Calls[0]
+	if gold.max.Name != "Gold" || gold.max.Rate != 10702.31 || gold.min.Rate != 10535.97 {
+		t.Errorf("Unexpected gold stats: %+v", gold)
+	}
+	if gold.avg != (10535.97+10702.31)/2 {
+		t.Errorf("Unexpected gold avg: %.4f", gold.avg)
+	}
+	palladium := mockReporter.Calls[1]
+	if palladium.max.Name != "Palladium" || palladium.max.Rate != 3609.05 {
+		t.Errorf("Unexpected palladium stats: %+v", palladium)
+	}
+}
+
+func TestApp_RunMetals_Summary(t *testing.T) {
+	body, err := os.ReadFile("../parser/testdata/xml_metall.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	mockReporter := &MockReporter{}
+	app := NewApp(&MockFetcher{}, mockReporter, WithMetalFetcher(fetcher.NewMetalClient(server.URL)))
+
+	now := time.Date(2025, time.October, 2, 0, 0, 0, 0, time.UTC)
+	if err := app.RunMetals(context.Background(), 2, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Одна сводка со всеми металлами, статистика по каждому ряду
+	s := mockReporter.Summary
+	if s == nil || !s.PerSeries || len(s.Currencies) != 4 {
+		t.Fatalf("Expected summary with 4 metals, got %+v", s)
+	}
+	if s.Currencies[0].Name != "Gold" || s.Currencies[0].Max.Rate != 10702.31 || len(s.Currencies[0].Points) != 2 {
+		t.Errorf("Unexpected gold stats: %+v", s.Currencies[0])
+	}
+
+	// Анализ рядов работает и для драгметаллов
+	app = NewApp(&MockFetcher{}, mockReporter, WithMetalFetcher(fetcher.NewMetalClient(server.URL)),
+		WithGapPolicy(gaps.Policy{Fill: gaps.CarryForward}), WithMoves(1))
+	if err := app.RunMetals(context.Background(), 2, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s = mockReporter.Summary
+	if s.Gaps.Fill != gaps.CarryForward || s.Currencies[0].Moves == nil {
+		t.Errorf("Expected gap policy and moves for metals, got %+v", s)
+	}
+}
+
+func TestApp_RunMetals_NotConfigured(t *testing.T) {
+	app := NewApp(&MockFetcher{}, &MockReporter{})
+
+	if err := app.RunMetals(context.Background(), 1, time.Now()); err == nil {
+		t.Fatal("Expected error without metal fetcher, got nil")
+	}
+}
+
+func TestApp_Run_WithCurrencies(t *testing.T) {
+	mockFetcher := &MockFetcher{
+		FetchFn: func(_ context.Context, date time.Time) ([]byte, error) {
+			return []byte(fmt.Sprintf(`<ValCurs Date="%s">
+				<Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>80,00</Value></Valute>
+				<Valute ID="R01239"><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Euro</Name><Value>90,00</Value></Valute>
+				<Valute ID="R01375"><CharCode>CNY</CharCode><Nominal>1</Nominal><Name>Yuan</Name><Value>11,00</Value></Valute>
+			</ValCurs>`, date.Format("02.01.2006"))), nil
+		},
+	}
+	mockReporter := &MockReporter{}
+	app := NewApp(mockFetcher, mockReporter, WithCurrencies("R01235", "CNY"))
+
+	if err := app.Run(context.Background(), 2, time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Евро отфильтрован: максимум — доллар, минимум — юань
+	if mockReporter.ReportCall.max.ID != "R01235" || mockReporter.ReportCall.min.CharCode != "CNY" {
+		t.Errorf("Unexpected report: %+v", mockReporter.ReportCall)
+	}
+	if mockReporter.ReportCall.avg != (80.0+11.0)/2 {
+		t.Errorf("Unexpected avg: %.4f", mockReporter.ReportCall.avg)
+	}
+}
+
+func usdFetcher() *MockFetcher {
+	return &MockFetcher{
+		FetchFn: func(_ context.Context, date time.Time) ([]byte, error) {
+			return []byte(fmt.Sprintf(`<ValCurs Date="%s"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>80,00</Value></Valute></ValCurs>`, date.Format("02.01.2006"))), nil
+		},
+	}
+}
+
+func TestApp_Run_WithIndicators(t *testing.T) {
+	now := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
+	keyRate := func(_ context.Context, from, to time.Time) (model.Series, error) {
+		// Период показателя совпадает с периодом курсов
+		if !from.Equal(now.AddDate(0, 0, -2)) || !to.Equal(now) {
+			t.Errorf("Unexpected period: %v - %v", from, to)
+		}
+		return model.Series{Name: "Key rate", Unit: "%", Points: []model.Point{{Date: from, Value: 17}}}, nil
+	}
+
+	mockReporter := &MockReporter{}
+	app := NewApp(usdFetcher(), mockReporter, WithIndicators(keyRate))
+
+	if err := app.Run(context.Background(), 3, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mockReporter.Summary == nil {
+		t.Fatal("Reporter.Report was not called")
+	}
+	if series := mockReporter.Summary.Indicators; len(series) != 1 || series[0].Name != "Key rate" {
+		t.Errorf("Unexpected series: %+v", series)
+	}
+}
+
+func TestApp_Run_IndicatorError(t *testing.T) {
+	failing := func(_ context.Context, _, _ time.Time) (model.Series, error) {
+		return model.Series{}, errors.New("soap fault")
+	}
+
+	mockReporter := &MockReporter{}
+	app := NewApp(usdFetcher(), mockReporter, WithIndicators(failing))
+
+	if err := app.Run(context.Background(), 1, time.Now()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if mockReporter.ReportCall != nil {
+		t.Error("Reporter should not be called on indicator error")
+	}
+}
+
+func TestApp_Run_LenientParser(t *testing.T) {
+	now := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
+	mockFetcher := &MockFetcher{
+		FetchFn: func(_ context.Context, date time.Time) ([]byte, error) {
+			return []byte(fmt.Sprintf(`<ValCurs Date="%s">
+				<Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>80,00</Value></Valute>
+				<Valute ID="R01239"><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Euro</Name><Value>n/a</Value></Valute>
+			</ValCurs>`, date.Format("02.01.2006"))), nil
+		},
+	}
+
+	// В строгом режиме день целиком отбрасывается с ошибкой
+	if err := NewApp(mockFetcher, &MockReporter{}).Run(context.Background(), 2, now); err == nil {
+		t.Fatal("Expected parse error in strict mode")
+	}
+
+	mockReporter := &MockReporter{}
+	app := NewApp(mockFetcher, mockReporter, WithLenientParser(parser.ParseRatesLenient))
+	if err := app.Run(context.Background(), 2, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mockReporter.ReportCall == nil || mockReporter.ReportCall.avg != 80 {
+		t.Fatalf("Expected USD-only statistics, got %+v", mockReporter.ReportCall)
+	}
+	// По одной записи на день, по возрастанию дат
+	diagnostics := mockReporter.Summary.Diagnostics
+	if len(diagnostics) != 2 {
+		t.Fatalf("Expected 2 diagnostics, got %+v", diagnostics)
+	}
+	if !diagnostics[0].Date.Equal(now.AddDate(0, 0, -1)) || diagnostics[1].Currency != "EUR" {
+		t.Errorf("Unexpected diagnostics: %+v", diagnostics)
+	}
+}
+
+func TestApp_Run_Summary(t *testing.T) {
+	now := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
+	keyRate := func(_ context.Context, from, _ time.Time) (model.Series, error) {
+		return model.Series{Name: "Key rate", Points: []model.Point{{Date: from, Value: 17}}}, nil
+	}
+
+	mockReporter := &MockReporter{}
+	app := NewApp(usdFetcher(), mockReporter, WithIndicators(keyRate))
+	if err := app.Run(context.Background(), 3, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	s := mockReporter.Summary
+	if s == nil {
+		t.Fatal("Report was not called")
+	}
+	if s.PerSeries {
+		t.Error("Currency summary must not be per series")
+	}
+	if !s.Period.From.Equal(now.AddDate(0, 0, -2)) || !s.Period.To.Equal(now) {
+		t.Errorf("Unexpected period: %+v", s.Period)
+	}
+	if s.Count != 3 || s.Avg != 80 {
+		t.Errorf("Unexpected totals: count=%d avg=%.4f", s.Count, s.Avg)
+	}
+	if len(s.Currencies) != 1 || s.Currencies[0].CharCode != "USD" || len(s.Currencies[0].Points) != 3 {
+		t.Errorf("Unexpected currencies: %+v", s.Currencies)
+	}
+	if len(s.Indicators) != 1 {
+		t.Errorf("Unexpected indicators: %+v", s.Indicators)
+	}
+}
+
+func TestApp_Run_MissingDatesAndSource(t *testing.T) {
+	now := time.Date(2025, 10, 22, 15, 30, 0, 0, time.UTC)
+	usd := usdFetcher()
+	// 21 и 19 октября источник данных не вернул
+	fetcher := &MockFetcher{
+		FetchFn: func(ctx context.Context, date time.Time) ([]byte, error) {
+			if date.Day() == 21 || date.Day() == 19 {
+				return nil, nil
+			}
+			return usd.FetchFn(ctx, date)
+		},
+	}
+
+	mockReporter := &MockReporter{}
+	source := reporter.Source{Name: "Bank of Russia", URL: "http://www.cbr.ru/scripts/XML_daily.asp"}
+	app := NewApp(fetcher, mockReporter, WithSource(source))
+	if err := app.Run(context.Background(), 4, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	s := mockReporter.Summary
+	if s.Source != source {
+		t.Errorf("Unexpected source: %+v", s.Source)
+	}
+	expected := []time.Time{
+		time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC),
+	}
+	if len(s.MissingDates) != len(expected) {
+		t.Fatalf("Expected missing dates %v, got %v", expected, s.MissingDates)
+	}
+	for i := range expected {
+		if !s.MissingDates[i].Equal(expected[i]) {
+			t.Errorf("Expected missing dates %v, got %v", expected, s.MissingDates)
+		}
+	}
+}
+
+func TestApp_Run_ReportError(t *testing.T) {
+	mockReporter := &MockReporter{Err: errors.New("disk full")}
+	app := NewApp(usdFetcher(), mockReporter)
+
+	// Ошибка доставки отчёта возвращается из Run
+	err := app.Run(context.Background(), 1, time.Now())
+	if !errors.Is(err, mockReporter.Err) {
+		t.Errorf("Expected report error, got %v", err)
+	}
+}
+
+func TestApp_Run_WithStore(t *testing.T) {
+	now := time.Date(2025, 10, 22, 15, 0, 0, 0, time.UTC)
+	store, err := storage.Open(filepath.Join(t.TempDir(), "rates.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	// 21 октября уже в хранилище
+	stored := []model.CurrencyRate{{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 81, Date: time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC)}}
+	if err := store.Put(context.Background(), now.AddDate(0, 0, -1), stored); err != nil {
+		t.Fatal(err)
+	}
+
+	fetcher := usdFetcher()
+	mockReporter := &MockReporter{}
+	if err := NewApp(fetcher, mockReporter, WithStore(store)).Run(context.Background(), 3, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fetcher.CallLog) != 2 {
+		t.Errorf("Expected 2 fetches for missing days, got %v", fetcher.CallLog)
+	}
+	if mockReporter.Summary.Count != 3 || mockReporter.Summary.Max.Rate != 81 {
+		t.Errorf("Stored rates must be used: %+v", mockReporter.Summary)
+	}
+
+	// Повторный запуск целиком из хранилища
+	fetcher = usdFetcher()
+	if err := NewApp(fetcher, mockReporter, WithStore(store)).Run(context.Background(), 3, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fetcher.CallLog) != 0 {
+		t.Errorf("Expected no fetches, got %v", fetcher.CallLog)
+	}
+	if mockReporter.Summary.Count != 3 {
+		t.Errorf("Unexpected count: %d", mockReporter.Summary.Count)
+	}
+}
+
+func TestApp_Run_AsOf(t *testing.T) {
+	now := time.Date(2025, 10, 22, 15, 0, 0, 0, time.UTC)
+	clock := time.Date(2025, 10, 22, 9, 0, 0, 0, time.UTC)
+	store, err := storage.Open(filepath.Join(t.TempDir(), "rates.jsonl"), storage.WithClock(func() time.Time { return clock }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	usd := model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 81, Date: time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC)}
+	if err := store.Put(context.Background(), now.AddDate(0, 0, -1), []model.CurrencyRate{usd}); err != nil {
+		t.Fatal(err)
+	}
+	// Днём ЦБ исправил курс
+	clock = clock.Add(3 * time.Hour)
+	usd.Rate = 85
+	if err := store.Put(context.Background(), now.AddDate(0, 0, -1), []model.CurrencyRate{usd}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Без asOf — последнее значение и исправление в отчёте
+	mockReporter := &MockReporter{}
+	if err := NewApp(usdFetcher(), mockReporter, WithStore(store)).Run(context.Background(), 1, now.AddDate(0, 0, -1)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := mockReporter.Summary; got.Max.Rate != 85 || len(got.Revisions) != 1 || got.Revisions[0].Old.Rate != 81 {
+		t.Errorf("Unexpected summary: max=%v revisions=%+v", got.Max.Rate, got.Revisions)
+	}
+
+	// До исправления: прежнее значение, исправления ещё не известно, а
+	// отсутствующие в хранилище дни не запрашиваются
+	asOf := time.Date(2025, 10, 22, 10, 0, 0, 0, time.UTC)
+	fetcher := usdFetcher()
+	if err := NewApp(fetcher, mockReporter, WithStore(store), WithAsOf(asOf)).Run(context.Background(), 2, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fetcher.CallLog) != 0 {
+		t.Errorf("Expected no fetches, got %v", fetcher.CallLog)
+	}
+	got := mockReporter.Summary
+	if got.Max.Rate != 81 || len(got.Revisions) != 0 || !got.AsOf.Equal(asOf) || len(got.MissingDates) != 1 {
+		t.Errorf("Unexpected as-of summary: max=%v revisions=%+v asOf=%v missing=%v", got.Max.Rate, got.Revisions, got.AsOf, got.MissingDates)
+	}
+}
+
+func TestApp_Run_AsOfWithoutHistory(t *testing.T) {
+	err := NewApp(usdFetcher(), &MockReporter{}, WithAsOf(time.Now())).Run(context.Background(), 1, time.Now())
+	if err == nil {
+		t.Error("Expected error without store")
+	}
+}
+
+func TestApp_Run_GapPolicy(t *testing.T) {
+	now := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
+	fetcher := &MockFetcher{
+		FetchFn: func(_ context.Context, date time.Time) ([]byte, error) {
+			valutes := `<Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>80,00</Value></Valute>`
+			switch date.Day() {
+			case 20:
+				valutes = ""
+			case 22:
+				valutes += `<Valute ID="R01239"><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Euro</Name><Value>95,00</Value></Valute>`
+			}
+			return []byte(fmt.Sprintf(`<ValCurs Date="%s">%s</ValCurs>`, date.Format("02.01.2006"), valutes)), nil
+		},
+	}
+
+	mockReporter := &MockReporter{}
+	policy := gaps.Policy{Fill: gaps.CarryForward, MinCoverage: 0.5}
+	if err := NewApp(fetcher, mockReporter, WithGapPolicy(policy)).Run(context.Background(), 4, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// EUR есть на одну дату из трёх — исключён; у USD восстановлено 20-е
+	got := mockReporter.Summary
+	if len(got.Dropped) != 1 || got.Dropped[0] != "EUR" || got.Gaps != policy {
+		t.Errorf("Unexpected gaps: %+v %v", got.Gaps, got.Dropped)
+	}
+	if len(got.Currencies) != 1 || got.Count != 4 || got.Filled() != 1 {
+		t.Fatalf("Unexpected summary: count=%d filled=%d %+v", got.Count, got.Filled(), got.Currencies)
+	}
+	if p := got.Currencies[0].Points[1]; !p.Filled || p.Rate != 80 || p.Date.Day() != 20 {
+		t.Errorf("Unexpected filled point: %+v", p)
+	}
+}
+
+func TestApp_Run_WithAnomalies(t *testing.T) {
+	now := time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)
+	fetcher := &MockFetcher{
+		FetchFn: func(_ context.Context, date time.Time) ([]byte, error) {
+			// Номинал 10 вместо 1 без пересчёта — курс в 10 раз больше
+			value := fmt.Sprintf("%.4f", 80+0.3*math.Sin(float64(date.Day())*1.7))
+			if date.Day() == 15 {
+				value = "801,00"
+			}
+			return []byte(fmt.Sprintf(`<ValCurs Date="%s"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>%s</Value></Valute></ValCurs>`,
+				date.Format("02.01.2006"), strings.Replace(value, ".", ",", 1))), nil
+		},
+	}
+
+	mockReporter := &MockReporter{}
+	if err := NewApp(fetcher, mockReporter, WithAnomalies(anomaly.NewDetector())).Run(context.Background(), 30, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := mockReporter.Summary.Anomalies
+	if len(got) != 2 || got[0].Reason != model.AnomalyNominal || got[0].Rate.Date.Day() != 15 || got[1].Rate.Date.Day() != 16 {
+		t.Errorf("Unexpected anomalies: %+v", got)
+	}
+}
+
+func TestApp_Run_WithMoves(t *testing.T) {
+	now := time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)
+	fetcher := &MockFetcher{
+		FetchFn: func(_ context.Context, date time.Time) ([]byte, error) {
+			// Курс растёт на рубль в день до 5 октября, затем падает
+			value := 80 + min(date.Day(), 5) - max(date.Day()-5, 0)
+			return []byte(fmt.Sprintf(`<ValCurs Date="%s"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>%d,00</Value></Valute></ValCurs>`,
+				date.Format("02.01.2006"), value)), nil
+		},
+	}
+
+	mockReporter := &MockReporter{}
+	if err := NewApp(fetcher, mockReporter, WithMoves(2)).Run(context.Background(), 10, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m := mockReporter.Summary.Currencies[0].Moves
+	if m == nil {
+		t.Fatal("Expected moves")
+	}
+	if m.Up.Length != 4 || m.Down.Length != 5 || len(m.Gains) != 2 || m.Drawdown.Peak.Value != 85 || m.Drawdown.Trough.Value != 80 {
+		t.Errorf("Unexpected moves: %+v", m)
+	}
+
+	mockReporter = &MockReporter{}
+	if err := NewApp(fetcher, mockReporter).Run(context.Background(), 10, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if mockReporter.Summary.Currencies[0].Moves != nil {
+		t.Error("Moves must not be computed unless requested")
+	}
+}
+
+func TestApp_Run_WithComparison(t *testing.T) {
+	now := time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)
+	fetcher := &MockFetcher{
+		FetchFn: func(_ context.Context, date time.Time) ([]byte, error) {
+			// В июле доллар стоил 78, в октябре — 82
+			value := "82,00"
+			if date.Month() == time.July {
+				value = "78,00"
+			}
+			return []byte(fmt.Sprintf(`<ValCurs Date="%s"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>%s</Value></Valute></ValCurs>`,
+				date.Format("02.01.2006"), value)), nil
+		},
+	}
+
+	mockReporter := &MockReporter{}
+	from, to := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 7, 10, 0, 0, 0, 0, time.UTC)
+	if err := NewApp(fetcher, mockReporter, WithComparison(from, to)).Run(context.Background(), 10, now); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	c := mockReporter.Summary.Comparison
+	if c == nil || !c.Base.From.Equal(from) || !c.Base.To.Equal(to) {
+		t.Fatalf("Unexpected comparison: %+v", c)
+	}
+	if len(c.Deltas) != 1 || c.Deltas[0].Base.Count != 10 || math.Abs(c.Deltas[0].Avg-(82.0/78-1)) > 1e-9 {
+		t.Errorf("Unexpected deltas: %+v", c.Deltas)
+	}
+	// Базовый период не попадает в основную статистику
+	if s := mockReporter.Summary; s.Count != 10 || s.Avg != 82 {
+		t.Errorf("Base period leaked into summary: count %d, avg %v", s.Count, s.Avg)
+	}
+}
+
+func TestApp_Run_ComparisonNoData(t *testing.T) {
+	fetcher := &MockFetcher{
+		FetchFn: func(_ context.Context, date time.Time) ([]byte, error) {
+			if date.Year() == 2024 {
+				return nil, nil
+			}
+			return []byte(fmt.Sprintf(`<ValCurs Date="%s"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>82,00</Value></Valute></ValCurs>`,
+				date.Format("02.01.2006"))), nil
+		},
+	}
+	from, to := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 10, 10, 0, 0, 0, 0, time.UTC)
+	err := NewApp(fetcher, &MockReporter{}, WithComparison(from, to)).Run(context.Background(), 10, time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC))
+	var noData *NoDataError
+	if !errors.As(err, &noData) || !noData.From.Equal(from) {
+		t.Errorf("Expected NoDataError for the comparison period, got %v", err)
+	}
+}
+
+func TestDays(t *testing.T) {
+	if got := Days(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 30, 15, 0, 0, 0, time.UTC)); got != 92 {
+		t.Errorf("Expected 92 days, got %d", got)
+	}
+}
@@ -0,0 +1,16 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// NoDataError — за весь запрошенный период не получено ни одного курса.
+type NoDataError struct {
+	From time.Time
+	To   time.Time
+}
+
+func (e *NoDataError) Error() string {
+	return fmt.Sprintf("no data collected for %s — %s", e.From.Format("2006-01-02"), e.To.Format("2006-01-02"))
+}
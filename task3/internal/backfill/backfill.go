@@ -0,0 +1,328 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"task3/internal/catalog"
+	"task3/internal/fetcher"
+	"task3/internal/model"
+	"task3/internal/parser"
+	"task3/internal/storage"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultChunkDays = 365
+	workersNum       = 4
+	// lookback — запас перед началом куска для XML_dynamic: на праздники
+	// действует курс последнего рабочего дня, который может лежать в
+	// предыдущем куске.
+	lookback = 14
+)
+
+// FirstDate — с этого дня ЦБ публикует официальные курсы.
+var FirstDate = time.Date(1992, time.July, 1, 0, 0, 0, 0, time.UTC)
+
+// Progress — состояние загрузки после очередного куска.
+type Progress struct {
+	From, To  time.Time
+	Chunk     int
+	Chunks    int
+	Days      int
+	TotalDays int
+	// Dynamic — кусок загружен через XML_dynamic, а не по дням.
+	Dynamic bool
+	Elapsed time.Duration
+	ETA     time.Duration
+}
+
+// Backfiller загружает историю курсов в хранилище кусками по chunkDays дней.
+// После каждого куска сохраняется контрольная точка, и прерванная загрузка
+// продолжается с места остановки.
+type Backfiller struct {
+	store      storage.Store
+	daily      fetcher.CurrencyRateFetcher
+	dynamic    fetcher.DynamicFetcher
+	items      []catalog.Item
+	english    bool
+	checkpoint string
+	chunkDays  int
+	retries    int
+	backoff    time.Duration
+	progress   func(Progress)
+	now        func() time.Time
+}
+
+type Option func(*Backfiller)
+
+// WithDynamic разрешает загрузку через XML_dynamic: запрос на валюту за
+// кусок вместо запроса на каждый день. Названия и коды валют берутся из
+// справочника; english — брать английские названия.
+func WithDynamic(dynamic fetcher.DynamicFetcher, items []catalog.Item, english bool) Option {
+	return func(b *Backfiller) {
+		b.dynamic = dynamic
+		b.items = items
+		b.english = english
+	}
+}
+
+// WithCheckpoint задаёт файл контрольной точки; без него загрузка каждый раз
+// начинается сначала.
+func WithCheckpoint(path string) Option {
+	return func(b *Backfiller) {
+		b.checkpoint = path
+	}
+}
+
+func WithChunkDays(days int) Option {
+	return func(b *Backfiller) {
+		if days > 0 {
+			b.chunkDays = days
+		}
+	}
+}
+
+// WithRetries повторяет запрос при недоступности источника (сеть, 5xx, 429)
+// до n раз с растущей паузой backoff, 2·backoff, ...
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(b *Backfiller) {
+		b.retries = n
+		b.backoff = backoff
+	}
+}
+
+func WithProgress(fn func(Progress)) Option {
+	return func(b *Backfiller) {
+		b.progress = fn
+	}
+}
+
+func New(store storage.Store, daily fetcher.CurrencyRateFetcher, opts ...Option) *Backfiller {
+	b := &Backfiller{
+		store:     store,
+		daily:     daily,
+		chunkDays: defaultChunkDays,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Run загружает курсы за from..to включительно. Повторный запуск после
+// завершения ничего не загружает; без контрольной точки — перезаписывает те
+// же значения, не меняя хранилища.
+func (b *Backfiller) Run(ctx context.Context, from, to time.Time) error {
+	from, to = storage.Day(from), storage.Day(to)
+	if to.Before(from) {
+		return fmt.Errorf("invalid range: %s is after %s", from.Format(dayFormat), to.Format(dayFormat))
+	}
+
+	start := from
+	if b.checkpoint != "" {
+		cp, err := readCheckpoint(b.checkpoint)
+		if err != nil {
+			return err
+		}
+		if cp.matches(from, to) {
+			start = cp.done.AddDate(0, 0, 1)
+		}
+	}
+
+	totalDays := days(from, to)
+	chunks := (totalDays + b.chunkDays - 1) / b.chunkDays
+	began := b.now()
+	doneBefore := days(from, start) - 1
+
+	for chunkFrom := start; !chunkFrom.After(to); chunkFrom = chunkFrom.AddDate(0, 0, b.chunkDays) {
+		chunkTo := chunkFrom.AddDate(0, 0, b.chunkDays-1)
+		if chunkTo.After(to) {
+			chunkTo = to
+		}
+
+		dynamic := b.useDynamic(days(chunkFrom, chunkTo))
+		var err error
+		if dynamic {
+			err = b.fetchDynamic(ctx, chunkFrom, chunkTo)
+		} else {
+			err = b.fetchDaily(ctx, chunkFrom, chunkTo)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to backfill %s — %s: %w", chunkFrom.Format(dayFormat), chunkTo.Format(dayFormat), err)
+		}
+
+		if b.checkpoint != "" {
+			if err := writeCheckpoint(b.checkpoint, checkpoint{from: from, to: to, done: chunkTo}); err != nil {
+				return err
+			}
+		}
+
+		if b.progress != nil {
+			done := days(from, chunkTo)
+			elapsed := b.now().Sub(began)
+			p := Progress{
+				From: chunkFrom, To: chunkTo,
+				Chunk: (done + b.chunkDays - 1) / b.chunkDays, Chunks: chunks,
+				Days: done, TotalDays: totalDays,
+				Dynamic: dynamic,
+				Elapsed: elapsed,
+			}
+			// Оценка по скорости текущего запуска, без дней из прошлого
+			if fetched := done - doneBefore; fetched > 0 {
+				p.ETA = elapsed / time.Duration(fetched) * time.Duration(totalDays-done)
+			}
+			b.progress(p)
+		}
+	}
+	return nil
+}
+
+// useDynamic выбирает способ загрузки с меньшим числом запросов.
+func (b *Backfiller) useDynamic(days int) bool {
+	return b.dynamic != nil && len(b.items) > 0 && len(b.items) < days
+}
+
+func (b *Backfiller) fetchDaily(ctx context.Context, from, to time.Time) error {
+	eg, gCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(workersNum)
+
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		eg.Go(func() error {
+			var xml []byte
+			err := b.retry(gCtx, func() error {
+				var err error
+				xml, err = b.daily.GetCourseByDate(gCtx, date)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get course by date %s: %w", date.Format(dayFormat), err)
+			}
+			if len(xml) == 0 {
+				return nil
+			}
+
+			rates, err := parser.ParseRates(xml)
+			if err != nil {
+				return fmt.Errorf("failed to parse rates for date %s: %w", date.Format(dayFormat), err)
+			}
+			if len(rates) == 0 {
+				return nil
+			}
+			return b.store.Put(gCtx, date, rates)
+		})
+	}
+	return eg.Wait()
+}
+
+// fetchDynamic загружает кусок по валютам и сохраняет дни, на которые в
+// XML_dynamic есть курсы. Валюта без записи на такой день получает курс
+// своей последней даты установления с датой этого дня, пока её ряд не
+// закончился: в одном дне все курсы с одной датой, а валюты, курс которых
+// больше не устанавливается, не тянутся дальше своей последней записи.
+func (b *Backfiller) fetchDynamic(ctx context.Context, from, to time.Time) error {
+	eg, gCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(workersNum)
+
+	var mu sync.Mutex
+	byDate := make(map[time.Time][]model.CurrencyRate)
+	last := make(map[string]time.Time, len(b.items))
+
+	for _, item := range b.items {
+		eg.Go(func() error {
+			var rates []model.CurrencyRate
+			err := b.retry(gCtx, func() error {
+				rates = rates[:0]
+				body, err := b.dynamic.OpenDynamic(gCtx, item.ID, from.AddDate(0, 0, -lookback), to)
+				if err != nil {
+					return err
+				}
+				defer body.Close()
+				return parser.StreamDynamic(body, func(r model.CurrencyRate) error {
+					rates = append(rates, b.named(item, r))
+					return nil
+				})
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get dynamic for %s: %w", item.ID, err)
+			}
+
+			mu.Lock()
+			for _, r := range rates {
+				byDate[r.Date] = append(byDate[r.Date], r)
+				if r.Date.After(last[item.ID]) {
+					last[item.ID] = r.Date
+				}
+			}
+			mu.Unlock()
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	dates := make([]time.Time, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	current := make(map[string]model.CurrencyRate, len(b.items))
+	for _, date := range dates {
+		for _, r := range byDate[date] {
+			current[r.ID] = r
+		}
+		if date.Before(from) {
+			continue
+		}
+		rates := make([]model.CurrencyRate, 0, len(current))
+		for _, item := range b.items {
+			r, ok := current[item.ID]
+			if !ok || date.After(last[item.ID]) {
+				continue
+			}
+			r.Date = date
+			rates = append(rates, r)
+		}
+		if err := b.store.Put(ctx, date, rates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// named дополняет запись XML_dynamic кодом и названием из справочника.
+func (b *Backfiller) named(item catalog.Item, r model.CurrencyRate) model.CurrencyRate {
+	r.CharCode = item.ISOCharCode
+	r.Name = item.Name
+	if b.english && item.EngName != "" {
+		r.Name = item.EngName
+	}
+	return r
+}
+
+func (b *Backfiller) retry(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= b.retries || !errors.Is(err, fetcher.ErrUpstreamUnavailable) {
+			return err
+		}
+		select {
+		case <-time.After(b.backoff * time.Duration(attempt+1)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// days — число дней в from..to включительно.
+func days(from, to time.Time) int {
+	return int(to.Sub(from).Hours()/24) + 1
+}
@@ -0,0 +1,351 @@
+package backfill
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"task3/internal/catalog"
+	"task3/internal/fetcher"
+	"task3/internal/storage"
+)
+
+func oct(d int) time.Time {
+	return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC)
+}
+
+// standIn — замена ЦБ: XML_daily и XML_dynamic с курсом, зависящим от даты,
+// и внедряемыми сбоями.
+type standIn struct {
+	mu       sync.Mutex
+	requests []string
+	// fail возвращает код ответа вместо данных; 0 — ответить нормально.
+	fail func(path string, date time.Time, n int) int
+	// skip — у валюты id нет курса на date в XML_dynamic.
+	skip func(id string, date time.Time) bool
+}
+
+func rate(date time.Time, base float64) float64 {
+	return base + float64(date.Day())/100
+}
+
+// published — ЦБ устанавливает курсы со вторника по субботу.
+func published(date time.Time) bool {
+	return date.Weekday() != time.Sunday && date.Weekday() != time.Monday
+}
+
+func (s *standIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Path+"?"+r.URL.RawQuery)
+	n := len(s.requests)
+	s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/daily":
+		date, _ := time.Parse("02/01/2006", q.Get("date_req"))
+		if s.fail != nil {
+			if code := s.fail(r.URL.Path, date, n); code != 0 {
+				w.WriteHeader(code)
+				return
+			}
+		}
+		// На день без установления — курсы последнего рабочего дня
+		for !published(date) {
+			date = date.AddDate(0, 0, -1)
+		}
+		fmt.Fprintf(w, `<ValCurs Date="%s">`, date.Format("02.01.2006"))
+		fmt.Fprintf(w, `<Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>Доллар США</Name><Value>%s</Value></Valute>`, strings.Replace(fmt.Sprint(rate(date, 80)), ".", ",", 1))
+		fmt.Fprintf(w, `<Valute ID="R01239"><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Евро</Name><Value>%s</Value></Valute>`, strings.Replace(fmt.Sprint(rate(date, 90)), ".", ",", 1))
+		fmt.Fprint(w, `</ValCurs>`)
+	case "/dynamic":
+		from, _ := time.Parse("02/01/2006", q.Get("date_req1"))
+		to, _ := time.Parse("02/01/2006", q.Get("date_req2"))
+		if s.fail != nil {
+			if code := s.fail(r.URL.Path, from, n); code != 0 {
+				w.WriteHeader(code)
+				return
+			}
+		}
+		id := q.Get("VAL_NM_RQ")
+		base := map[string]float64{"R01235": 80, "R01239": 90}[id]
+		fmt.Fprintf(w, `<ValCurs ID="%s">`, id)
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			if published(d) && (s.skip == nil || !s.skip(id, d)) {
+				fmt.Fprintf(w, `<Record Date="%s" Id="%s"><Nominal>1</Nominal><Value>%s</Value></Record>`,
+					d.Format("02.01.2006"), id, strings.Replace(fmt.Sprint(rate(d, base)), ".", ",", 1))
+			}
+		}
+		fmt.Fprint(w, `</ValCurs>`)
+	}
+}
+
+func (s *standIn) count(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if strings.HasPrefix(r, path) {
+			n++
+		}
+	}
+	return n
+}
+
+var items = []catalog.Item{
+	{ID: "R01235", Name: "Доллар США", EngName: "US Dollar", ISOCharCode: "USD"},
+	{ID: "R01239", Name: "Евро", EngName: "Euro", ISOCharCode: "EUR"},
+}
+
+func openStore(t *testing.T, path string) *storage.LogStore {
+	t.Helper()
+	s, err := storage.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func fileLines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "\n")
+}
+
+func TestBackfill_Dynamic(t *testing.T) {
+	cbr := &standIn{}
+	server := httptest.NewServer(cbr)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	store := openStore(t, path)
+	var progress []Progress
+	b := New(store, fetcher.NewClient(server.URL+"/daily"),
+		WithDynamic(fetcher.NewDynamicClient(server.URL+"/dynamic"), items, true),
+		WithChunkDays(10),
+		WithProgress(func(p Progress) { progress = append(progress, p) }))
+
+	if err := b.Run(context.Background(), oct(1), oct(31)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 2 валюты × 3 куска вместо 30 запросов по дням; последний кусок из
+	// одного дня дешевле загрузить через XML_daily
+	if cbr.count("/daily") != 1 || cbr.count("/dynamic") != 6 {
+		t.Errorf("Unexpected requests: %v", cbr.requests)
+	}
+	got, _ := store.Range(context.Background(), oct(1), oct(31))
+	// Из XML_dynamic — только дни установления курсов (22 с 1 по 30
+	// октября), 31 октября — из XML_daily
+	if len(got) != 23 {
+		t.Fatalf("Expected 23 days stored, got %d", len(got))
+	}
+	if _, ok := got[oct(6)]; ok {
+		t.Errorf("Monday without rates in XML_dynamic must not be stored: %+v", got[oct(6)])
+	}
+	saturday := got[oct(4)]
+	if len(saturday) != 2 || !saturday[0].Date.Equal(oct(4)) || saturday[0].Rate != 80.04 || saturday[0].CharCode != "USD" || saturday[0].Name != "US Dollar" {
+		t.Errorf("Unexpected rates for Saturday: %+v", saturday)
+	}
+	// 1 октября — среда, курсы того же дня из куска с запасом назад
+	if first := got[oct(1)]; len(first) != 2 || first[1].Rate != 90.01 {
+		t.Errorf("Unexpected rates for Oct 1: %+v", first)
+	}
+
+	if len(progress) != 4 || !progress[2].Dynamic || progress[3].Dynamic || progress[3].Days != 31 || progress[3].TotalDays != 31 || progress[3].Chunk != 4 || progress[3].ETA != 0 {
+		t.Errorf("Unexpected progress: %+v", progress)
+	}
+
+	// Повтор без контрольной точки не меняет хранилище
+	lines := fileLines(t, path)
+	if err := b.Run(context.Background(), oct(1), oct(31)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fileLines(t, path) != lines {
+		t.Errorf("Rerun must be idempotent: %d lines, was %d", fileLines(t, path), lines)
+	}
+}
+
+func TestBackfill_DailyMatchesDynamic(t *testing.T) {
+	server := httptest.NewServer(&standIn{})
+	defer server.Close()
+	ctx := context.Background()
+
+	daily := openStore(t, filepath.Join(t.TempDir(), "daily.jsonl"))
+	if err := New(daily, fetcher.NewClient(server.URL+"/daily")).Run(ctx, oct(1), oct(20)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	dynamic := openStore(t, filepath.Join(t.TempDir(), "dynamic.jsonl"))
+	b := New(dynamic, nil, WithDynamic(fetcher.NewDynamicClient(server.URL+"/dynamic"), items, false))
+	if err := b.Run(ctx, oct(1), oct(20)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// На дни установления курсов оба способа дают одни и те же курсы;
+	// воскресенья и понедельники есть только у XML_daily
+	want, _ := daily.Range(ctx, oct(1), oct(20))
+	got, _ := dynamic.Range(ctx, oct(1), oct(20))
+	n := 0
+	for d, rates := range want {
+		if !published(d) {
+			continue
+		}
+		n++
+		if fmt.Sprint(got[d]) != fmt.Sprint(rates) {
+			t.Errorf("%s: expected %+v, got %+v", d.Format(dayFormat), rates, got[d])
+		}
+	}
+	if len(got) != n {
+		t.Errorf("Expected %d days, got %d", n, len(got))
+	}
+}
+
+func TestBackfill_DynamicCarriesEachCurrency(t *testing.T) {
+	// У евро нет курса на среду 8 октября, у доллара есть
+	cbr := &standIn{skip: func(id string, date time.Time) bool {
+		return id == "R01239" && date.Equal(oct(8))
+	}}
+	server := httptest.NewServer(cbr)
+	defer server.Close()
+
+	store := openStore(t, filepath.Join(t.TempDir(), "rates.jsonl"))
+	b := New(store, nil, WithDynamic(fetcher.NewDynamicClient(server.URL+"/dynamic"), items, false))
+	if err := b.Run(context.Background(), oct(1), oct(20)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, _ := store.Range(context.Background(), oct(8), oct(8))
+	day := got[oct(8)]
+	if len(day) != 2 {
+		t.Fatalf("Expected both currencies on Oct 8, got %+v", day)
+	}
+	if day[0].CharCode != "USD" || !day[0].Date.Equal(oct(8)) || day[0].Rate != 80.08 {
+		t.Errorf("Unexpected USD on Oct 8: %+v", day[0])
+	}
+	// Евро — с курсом предыдущей даты, но с датой дня
+	if day[1].CharCode != "EUR" || !day[1].Date.Equal(oct(8)) || day[1].Rate != 90.07 {
+		t.Errorf("Unexpected EUR on Oct 8: %+v", day[1])
+	}
+}
+
+func TestBackfill_DynamicStopsAfterLastRecord(t *testing.T) {
+	// Курс евро перестали устанавливать после 10 октября
+	cbr := &standIn{skip: func(id string, date time.Time) bool {
+		return id == "R01239" && date.After(oct(10))
+	}}
+	server := httptest.NewServer(cbr)
+	defer server.Close()
+
+	store := openStore(t, filepath.Join(t.TempDir(), "rates.jsonl"))
+	b := New(store, nil, WithDynamic(fetcher.NewDynamicClient(server.URL+"/dynamic"), items, false))
+	if err := b.Run(context.Background(), oct(1), oct(20)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, _ := store.Range(context.Background(), oct(1), oct(20))
+	for d, rates := range got {
+		var codes []string
+		for _, r := range rates {
+			if !r.Date.Equal(d) {
+				t.Errorf("%s: rate with another date %+v", d.Format(dayFormat), r)
+			}
+			codes = append(codes, r.CharCode)
+		}
+		want := "USD,EUR"
+		if d.After(oct(10)) {
+			want = "USD"
+		}
+		if got := strings.Join(codes, ","); got != want {
+			t.Errorf("%s: expected %s, got %s", d.Format(dayFormat), want, got)
+		}
+	}
+}
+
+func TestBackfill_ResumeAfterFailure(t *testing.T) {
+	failing := true
+	cbr := &standIn{fail: func(_ string, date time.Time, n int) int {
+		switch {
+		case n == 1:
+			// Разовый сбой — повторяется
+			return http.StatusServiceUnavailable
+		case failing && date.Equal(oct(12)):
+			return http.StatusNotFound
+		}
+		return 0
+	}}
+	server := httptest.NewServer(cbr)
+	defer server.Close()
+
+	dir := t.TempDir()
+	store := openStore(t, filepath.Join(dir, "rates.jsonl"))
+	checkpoint := filepath.Join(dir, "rates.checkpoint")
+	b := New(store, fetcher.NewClient(server.URL+"/daily"),
+		WithChunkDays(5), WithCheckpoint(checkpoint), WithRetries(2, time.Millisecond))
+
+	err := b.Run(context.Background(), oct(1), oct(20))
+	if err == nil || !strings.Contains(err.Error(), "2025-10-11 — 2025-10-15") {
+		t.Fatalf("Expected failure in third chunk, got %v", err)
+	}
+	cp, _ := readCheckpoint(checkpoint)
+	if !cp.done.Equal(oct(10)) {
+		t.Errorf("Expected checkpoint at Oct 10, got %v", cp.done)
+	}
+
+	// Повторный запуск начинает с 11 октября
+	failing = false
+	before := cbr.count("/daily")
+	if err := b.Run(context.Background(), oct(1), oct(20)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, r := range cbr.requests[before:] {
+		date, _ := time.Parse("02/01/2006", strings.TrimPrefix(r, "/daily?date_req="))
+		if date.Before(oct(11)) {
+			t.Errorf("Day before checkpoint fetched again: %s", r)
+		}
+	}
+	got, _ := store.Range(context.Background(), oct(1), oct(20))
+	if len(got) != 20 {
+		t.Errorf("Expected 20 stored days, got %d", len(got))
+	}
+
+	// Завершённая загрузка не повторяется
+	before = cbr.count("/daily")
+	if err := b.Run(context.Background(), oct(1), oct(20)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := cbr.count("/daily") - before; n != 0 {
+		t.Errorf("Expected no requests for finished backfill, got %d", n)
+	}
+
+	// Продлённый диапазон догружает только новые дни
+	before = cbr.count("/daily")
+	if err := b.Run(context.Background(), oct(1), oct(25)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := cbr.count("/daily") - before; n != 5 {
+		t.Errorf("Expected 5 requests for extended range, got %d", n)
+	}
+	if cp, _ := readCheckpoint(checkpoint); !cp.to.Equal(oct(25)) || !cp.done.Equal(oct(25)) {
+		t.Errorf("Expected checkpoint extended to Oct 25, got %+v", cp)
+	}
+
+	// Другое начало — контрольная точка не подходит
+	before = cbr.count("/daily")
+	if err := b.Run(context.Background(), oct(1), oct(3)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := cbr.count("/daily") - before; n != 3 {
+		t.Errorf("Expected 3 requests for new range, got %d", n)
+	}
+}
@@ -0,0 +1,74 @@
+package backfill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const dayFormat = "2006-01-02"
+
+// checkpoint — последний загруженный день для диапазона from..to; to
+// сдвигается, если диапазон продлили.
+type checkpoint struct {
+	from, to, done time.Time
+}
+
+type checkpointFile struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Done string `json:"done"`
+}
+
+// matches — загрузку from..to можно продолжить с этой точки: начало то же,
+// а загруженная часть не выходит за to. Так продлённый до новой даты
+// диапазон догружает только новые дни; для другого начала загрузка идёт
+// сначала.
+func (c checkpoint) matches(from, to time.Time) bool {
+	return c.from.Equal(from) && !c.done.Before(from) && !c.done.After(to)
+}
+
+func readCheckpoint(path string) (checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint{}, nil
+	}
+	if err != nil {
+		return checkpoint{}, err
+	}
+
+	var f checkpointFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return checkpoint{}, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	var c checkpoint
+	for _, field := range []struct {
+		raw string
+		dst *time.Time
+	}{{f.From, &c.from}, {f.To, &c.to}, {f.Done, &c.done}} {
+		if *field.dst, err = time.Parse(dayFormat, field.raw); err != nil {
+			return checkpoint{}, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// writeCheckpoint записывает файл через временный и rename, чтобы прерывание
+// не оставило половину файла.
+func writeCheckpoint(path string, c checkpoint) error {
+	data, err := json.Marshal(checkpointFile{
+		From: c.from.Format(dayFormat),
+		To:   c.to.Format(dayFormat),
+		Done: c.done.Format(dayFormat),
+	})
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,157 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"task3/internal/fetcher"
+)
+
+// Cache держит справочник в памяти и, если задан path, на диске: свежая копия
+// файла используется без запроса к ЦБ, устаревшая — если ЦБ недоступен.
+type Cache struct {
+	fetcher fetcher.CatalogFetcher
+	path    string
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	catalog *Catalog
+}
+
+func NewCache(fetcher fetcher.CatalogFetcher, path string, ttl time.Duration) *Cache {
+	return &Cache{
+		fetcher: fetcher,
+		path:    path,
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+func (c *Cache) Get(ctx context.Context) (*Catalog, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.catalog != nil {
+		return c.catalog, nil
+	}
+
+	modTime, statErr := c.modTime()
+	if statErr == nil && c.now().Sub(modTime) < c.ttl {
+		if cat, err := c.readFile(); err == nil {
+			c.catalog = cat
+			return cat, nil
+		}
+	}
+
+	body, fetchErr := c.fetcher.OpenCatalog(ctx)
+	if fetchErr != nil {
+		if statErr == nil {
+			if cat, err := c.readFile(); err == nil {
+				c.catalog = cat
+				return cat, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to fetch currency catalog: %w", fetchErr)
+	}
+	defer body.Close()
+
+	// Ответ разбирается по мере чтения и тут же пишется во временный файл:
+	// целиком в памяти он не держится, а кеш заменяется, только если разбор
+	// удался
+	var r io.Reader = body
+	tmp, err := c.createTemp()
+	if err != nil {
+		// Справочник всё равно будет получен: без кеша на диске он просто
+		// будет загружен заново при следующем запуске
+		log.Printf("warning: failed to cache currency catalog: %v", err)
+	}
+	var cw *cacheWriter
+	if tmp != nil {
+		defer os.Remove(tmp.Name())
+		cw = &cacheWriter{w: tmp}
+		r = io.TeeReader(body, cw)
+	}
+
+	cat, err := ParseReader(r)
+	if err != nil {
+		if tmp != nil {
+			tmp.Close()
+		}
+		return nil, fmt.Errorf("failed to parse currency catalog: %w", err)
+	}
+	if tmp != nil {
+		if cw.err != nil {
+			tmp.Close()
+			log.Printf("warning: failed to cache currency catalog: %v", cw.err)
+		} else if err := c.commit(tmp); err != nil {
+			log.Printf("warning: failed to cache currency catalog: %v", err)
+		}
+	}
+
+	c.catalog = cat
+	return cat, nil
+}
+
+func (c *Cache) modTime() (time.Time, error) {
+	if c.path == "" {
+		return time.Time{}, os.ErrNotExist
+	}
+	info, err := os.Stat(c.path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+func (c *Cache) readFile() (*Catalog, error) {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseReader(f)
+}
+
+// createTemp создаёт временный файл рядом с кешем; без пути кеша — nil.
+func (c *Cache) createTemp() (*os.File, error) {
+	if c.path == "" {
+		return nil, nil
+	}
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, filepath.Base(c.path)+".*.tmp")
+}
+
+// cacheWriter копирует ответ в файл кеша. Первая ошибка записи
+// запоминается, но разбору не передаётся: справочник нужен и без кеша.
+type cacheWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *cacheWriter) Write(p []byte) (int, error) {
+	if w.err == nil {
+		_, w.err = w.w.Write(p)
+	}
+	return len(p), nil
+}
+
+// commit заменяет файл кеша записанным временным файлом.
+func (c *Cache) commit(tmp *os.File) error {
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
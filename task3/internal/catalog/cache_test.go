@@ -0,0 +1,177 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type mockCatalogFetcher struct {
+	data  []byte
+	err   error
+	calls int
+}
+
+func (m *mockCatalogFetcher) OpenCatalog(_ context.Context) (io.ReadCloser, error) {
+	m.calls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return io.NopCloser(bytes.NewReader(m.data)), nil
+}
+
+func fixtureBytes(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/XML_valFull.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func TestCache_FetchesOnceAndWritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "valfull.xml")
+	f := &mockCatalogFetcher{data: fixtureBytes(t)}
+	cache := NewCache(f, path, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		cat, err := cache.Get(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(cat.Items()) != 6 {
+			t.Fatalf("Expected 6 items, got %d", len(cat.Items()))
+		}
+	}
+	if f.calls != 1 {
+		t.Errorf("Expected 1 fetch, got %d", f.calls)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected cache file to be written: %v", err)
+	}
+
+	// Новый кеш с тем же файлом не ходит в сеть, пока файл свежий
+	f2 := &mockCatalogFetcher{err: errors.New("must not be called")}
+	if _, err := NewCache(f2, path, time.Hour).Get(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f2.calls != 0 {
+		t.Errorf("Expected fresh file to be used, got %d fetches", f2.calls)
+	}
+}
+
+func TestCache_StaleFileRefreshed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "valfull.xml")
+	if err := os.WriteFile(path, []byte(`<Valuta></Valuta>`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := &mockCatalogFetcher{data: fixtureBytes(t)}
+	cache := NewCache(f, path, time.Hour)
+	cache.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	cat, err := cache.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if f.calls != 1 || len(cat.Items()) != 6 {
+		t.Errorf("Expected stale file to be refreshed, got %d fetches and %d items", f.calls, len(cat.Items()))
+	}
+}
+
+func TestCache_StaleFileUsedWhenUpstreamFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "valfull.xml")
+	if err := os.WriteFile(path, fixtureBytes(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewCache(&mockCatalogFetcher{err: errors.New("network error")}, path, time.Hour)
+	cache.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+
+	cat, err := cache.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cat.Items()) != 6 {
+		t.Errorf("Expected 6 items from stale file, got %d", len(cat.Items()))
+	}
+}
+
+func TestCache_FetchError(t *testing.T) {
+	cache := NewCache(&mockCatalogFetcher{err: errors.New("network error")}, "", time.Hour)
+
+	if _, err := cache.Get(context.Background()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestCache_UnwritablePathKeepsCatalog(t *testing.T) {
+	// Каталог кеша — обычный файл, записать в него нельзя
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f := &mockCatalogFetcher{data: fixtureBytes(t)}
+	cat, err := NewCache(f, filepath.Join(blocker, "valfull.xml"), time.Hour).Get(context.Background())
+	if err != nil {
+		t.Fatalf("Cache write error must not fail Get: %v", err)
+	}
+	if len(cat.Items()) != 6 {
+		t.Errorf("Expected 6 items, got %d", len(cat.Items()))
+	}
+}
+
+func TestCache_ParseErrorKeepsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "valfull.xml")
+	if err := os.WriteFile(path, fixtureBytes(t), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cache := NewCache(&mockCatalogFetcher{data: []byte("<Valuta><Item")}, path, time.Hour)
+	cache.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+
+	if _, err := cache.Get(context.Background()); err == nil {
+		t.Fatal("Expected parse error")
+	}
+	// Битый ответ не должен затереть прежний кеш и оставить временные файлы
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(data, fixtureBytes(t)) {
+		t.Errorf("Cache file must be kept, got %d bytes, %v", len(data), err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Expected only the cache file, got %d entries", len(entries))
+	}
+}
+
+// failingWriter отказывает после первых n байт.
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		written := w.n
+		w.n = 0
+		return written, errors.New("disk full")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestCacheWriter_WriteErrorKeepsParsing(t *testing.T) {
+	cw := &cacheWriter{w: &failingWriter{n: 100}}
+	cat, err := ParseReader(io.TeeReader(bytes.NewReader(fixtureBytes(t)), cw))
+	if err != nil {
+		t.Fatalf("Cache write error must not fail parsing: %v", err)
+	}
+	if len(cat.Items()) != 6 {
+		t.Errorf("Expected 6 items, got %d", len(cat.Items()))
+	}
+	if cw.err == nil {
+		t.Error("Expected write error to be recorded")
+	}
+}
@@ -0,0 +1,159 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Item — запись справочника валют XML_valFull.asp.
+type Item struct {
+	ID          string
+	Name        string
+	EngName     string
+	Nominal     int
+	ParentCode  string
+	ISONumCode  int
+	ISOCharCode string
+}
+
+type Catalog struct {
+	items    []Item
+	byID     map[string]int
+	byChar   map[string]int
+	byNum    map[int]int
+	byParent map[string][]int
+}
+
+type valutaItem struct {
+	ID          string `xml:"ID,attr"`
+	Name        string `xml:"Name"`
+	EngName     string `xml:"EngName"`
+	Nominal     int    `xml:"Nominal"`
+	ParentCode  string `xml:"ParentCode"`
+	ISONumCode  string `xml:"ISO_Num_Code"`
+	ISOCharCode string `xml:"ISO_Char_Code"`
+}
+
+func Parse(xmlData []byte) (*Catalog, error) {
+	return ParseReader(bytes.NewReader(xmlData))
+}
+
+// ParseReader читает справочник по токенам, декодируя по одному Item за раз.
+func ParseReader(r io.Reader) (*Catalog, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	var items []Item
+	seenRoot := false
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		el, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if el.Name.Local == "Valuta" {
+			seenRoot = true
+			continue
+		}
+		if el.Name.Local != "Item" {
+			continue
+		}
+
+		var it valutaItem
+		if err := decoder.DecodeElement(&it, &el); err != nil {
+			return nil, err
+		}
+		item := Item{
+			ID:          strings.TrimSpace(it.ID),
+			Name:        strings.TrimSpace(it.Name),
+			EngName:     strings.TrimSpace(it.EngName),
+			Nominal:     it.Nominal,
+			ParentCode:  strings.TrimSpace(it.ParentCode),
+			ISOCharCode: strings.ToUpper(strings.TrimSpace(it.ISOCharCode)),
+		}
+		if num := strings.TrimSpace(it.ISONumCode); num != "" {
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ISO_Num_Code %q for currency %s", num, item.ID)
+			}
+			item.ISONumCode = n
+		}
+		items = append(items, item)
+	}
+
+	if !seenRoot {
+		return nil, fmt.Errorf("valuta element not found")
+	}
+	return New(items), nil
+}
+
+func New(items []Item) *Catalog {
+	c := &Catalog{
+		items:    items,
+		byID:     make(map[string]int),
+		byChar:   make(map[string]int),
+		byNum:    make(map[int]int),
+		byParent: make(map[string][]int),
+	}
+	for i, item := range items {
+		c.byID[strings.ToUpper(item.ID)] = i
+		if _, ok := c.byChar[item.ISOCharCode]; !ok && item.ISOCharCode != "" {
+			c.byChar[item.ISOCharCode] = i
+		}
+		if _, ok := c.byNum[item.ISONumCode]; !ok && item.ISONumCode != 0 {
+			c.byNum[item.ISONumCode] = i
+		}
+		if item.ParentCode != "" {
+			c.byParent[item.ParentCode] = append(c.byParent[item.ParentCode], i)
+		}
+	}
+	return c
+}
+
+func (c *Catalog) Items() []Item {
+	return c.items
+}
+
+// Resolve находит валюту по вводу пользователя: коду ЦБ ("R01235"),
+// цифровому ISO-коду ("840") или буквенному ("usd"), без учёта регистра.
+func (c *Catalog) Resolve(input string) (Item, error) {
+	key := strings.ToUpper(strings.TrimSpace(input))
+	if i, ok := c.byID[key]; ok {
+		return c.items[i], nil
+	}
+	if n, err := strconv.Atoi(key); err == nil {
+		if i, ok := c.byNum[n]; ok {
+			return c.items[i], nil
+		}
+	}
+	if i, ok := c.byChar[key]; ok {
+		return c.items[i], nil
+	}
+	return Item{}, fmt.Errorf("unknown currency %q", input)
+}
+
+// Lineage возвращает все коды ЦБ, которыми валюта обозначалась в разные
+// периоды, включая её саму.
+func (c *Catalog) Lineage(item Item) []Item {
+	idx := c.byParent[item.ParentCode]
+	if len(idx) == 0 {
+		return []Item{item}
+	}
+	lineage := make([]Item, 0, len(idx))
+	for _, i := range idx {
+		lineage = append(lineage, c.items[i])
+	}
+	return lineage
+}
@@ -0,0 +1,103 @@
+package catalog
+
+import (
+	"os"
+	"testing"
+)
+
+func loadFixture(t *testing.T) *Catalog {
+	t.Helper()
+	data, err := os.ReadFile("testdata/XML_valFull.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	cat, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return cat
+}
+
+func TestParse(t *testing.T) {
+	cat := loadFixture(t)
+
+	if len(cat.Items()) != 6 {
+		t.Fatalf("Expected 6 items, got %d", len(cat.Items()))
+	}
+
+	expected := Item{
+		ID:          "R01090",
+		Name:        "Белорусский рубль",
+		EngName:     "Belarussian Ruble",
+		Nominal:     10000,
+		ParentCode:  "R01090",
+		ISONumCode:  974,
+		ISOCharCode: "BYR",
+	}
+	if cat.Items()[1] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, cat.Items()[1])
+	}
+
+	// Пустые ISO-коды у исторических валют не считаются ошибкой
+	if lit := cat.Items()[5]; lit.ISONumCode != 0 || lit.ISOCharCode != "" {
+		t.Errorf("Expected empty ISO codes, got %+v", lit)
+	}
+}
+
+func TestParse_InvalidNumCode(t *testing.T) {
+	_, err := Parse([]byte(`<Valuta><Item ID="R1"><ISO_Num_Code>abc</ISO_Num_Code></Item></Valuta>`))
+	if err == nil {
+		t.Fatal("Expected error for invalid ISO_Num_Code, got nil")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cat := loadFixture(t)
+
+	tests := []struct {
+		input string
+		id    string
+	}{
+		{"usd", "R01235"},
+		{"USD", "R01235"},
+		{"840", "R01235"},
+		{"r01235", "R01235"},
+		{" eur ", "R01239"},
+		{"036", "R01010"},
+		{"BYN", "R01090B"},
+		{"R01090B", "R01090B"},
+	}
+	for _, tt := range tests {
+		item, err := cat.Resolve(tt.input)
+		if err != nil {
+			t.Errorf("Resolve(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if item.ID != tt.id {
+			t.Errorf("Resolve(%q): expected %s, got %s", tt.input, tt.id, item.ID)
+		}
+	}
+}
+
+func TestResolve_Unknown(t *testing.T) {
+	cat := loadFixture(t)
+
+	if _, err := cat.Resolve("XYZ"); err == nil {
+		t.Fatal("Expected error for unknown currency, got nil")
+	}
+}
+
+func TestLineage(t *testing.T) {
+	cat := loadFixture(t)
+
+	byn, _ := cat.Resolve("BYN")
+	lineage := cat.Lineage(byn)
+	if len(lineage) != 2 || lineage[0].ID != "R01090" || lineage[1].ID != "R01090B" {
+		t.Errorf("Unexpected lineage: %+v", lineage)
+	}
+
+	usd, _ := cat.Resolve("USD")
+	if lineage := cat.Lineage(usd); len(lineage) != 1 {
+		t.Errorf("Expected USD without redenominations, got %+v", lineage)
+	}
+}
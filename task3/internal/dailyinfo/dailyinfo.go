@@ -0,0 +1,90 @@
+package dailyinfo
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/soap"
+)
+
+const (
+	namespace  = "http://web.cbr.ru/"
+	dateLayout = "2006-01-02T15:04:05"
+)
+
+// Client — клиент веб-сервиса ЦБ DailyInfoWebServ.
+type Client struct {
+	soap *soap.Client
+}
+
+func NewClient(url string) *Client {
+	return &Client{soap: soap.NewClient(url)}
+}
+
+type keyRateRequest struct {
+	XMLName  xml.Name `xml:"KeyRate"`
+	NS       string   `xml:"xmlns,attr"`
+	FromDate string   `xml:"fromDate"`
+	ToDate   string   `xml:"ToDate"`
+}
+
+type keyRateResponse struct {
+	Rows []keyRateRow `xml:"KeyRateResult>diffgram>KeyRate>KR"`
+}
+
+type keyRateRow struct {
+	DT   string `xml:"DT"`
+	Rate string `xml:"Rate"`
+}
+
+// KeyRate возвращает историю ключевой ставки за период в порядке возрастания дат.
+func (c *Client) KeyRate(ctx context.Context, from, to time.Time) (model.Series, error) {
+	req := keyRateRequest{
+		NS:       namespace,
+		FromDate: from.Format(dateLayout),
+		ToDate:   to.Format(dateLayout),
+	}
+
+	var resp keyRateResponse
+	if err := c.soap.Call(ctx, namespace+"KeyRate", req, &resp); err != nil {
+		return model.Series{}, fmt.Errorf("failed to call KeyRate: %w", err)
+	}
+
+	series := model.Series{Name: "Key rate", Unit: "%"}
+	for _, row := range resp.Rows {
+		date, err := parseDate(row.DT)
+		if err != nil {
+			return model.Series{}, err
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(row.Rate), 64)
+		if err != nil {
+			return model.Series{}, fmt.Errorf("invalid key rate %q on %s: %w", row.Rate, row.DT, err)
+		}
+		series.Points = append(series.Points, model.Point{Date: date, Value: value})
+	}
+
+	sort.Slice(series.Points, func(i, j int) bool {
+		return series.Points[i].Date.Before(series.Points[j].Date)
+	})
+	return series, nil
+}
+
+// parseDate отбрасывает время и часовой пояс: ЦБ отдаёт даты как
+// полночь по Москве ("2025-09-15T00:00:00+03:00").
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.Parse(dateLayout, s)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+}
@@ -0,0 +1,77 @@
+package dailyinfo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeyRate(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/KeyRateResponse.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("SOAPAction") != `"http://web.cbr.ru/KeyRate"` {
+			t.Errorf("Unexpected SOAPAction: %s", r.Header.Get("SOAPAction"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `<KeyRate xmlns="http://web.cbr.ru/"><fromDate>2025-07-01T00:00:00</fromDate><ToDate>2025-10-22T00:00:00</ToDate></KeyRate>`) {
+			t.Errorf("Unexpected request: %s", body)
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	from := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)
+	series, err := NewClient(server.URL).KeyRate(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if series.Name != "Key rate" || series.Unit != "%" {
+		t.Errorf("Unexpected series header: %q %q", series.Name, series.Unit)
+	}
+	if len(series.Points) != 3 {
+		t.Fatalf("Expected 3 points, got %d", len(series.Points))
+	}
+	// ЦБ отдаёт строки от новых к старым, ряд — по возрастанию дат
+	first, last := series.Points[0], series.Points[2]
+	if !first.Date.Equal(time.Date(2025, time.July, 28, 0, 0, 0, 0, time.UTC)) || first.Value != 18 {
+		t.Errorf("Unexpected first point: %+v", first)
+	}
+	if !last.Date.Equal(time.Date(2025, time.October, 20, 0, 0, 0, 0, time.UTC)) || last.Value != 16.5 {
+		t.Errorf("Unexpected last point: %+v", last)
+	}
+}
+
+func TestKeyRate_Fault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>soap:Server</faultcode><faultstring>Server was unable to process request.</faultstring></soap:Fault></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).KeyRate(context.Background(), time.Now(), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "unable to process request") {
+		t.Fatalf("Expected fault error, got %v", err)
+	}
+}
+
+func TestKeyRate_InvalidRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><KeyRateResponse><KeyRateResult><diffgram><KeyRate><KR><DT>2025-10-20T00:00:00+03:00</DT><Rate>n/a</Rate></KR></KeyRate></diffgram></KeyRateResult></KeyRateResponse></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL).KeyRate(context.Background(), time.Now(), time.Now()); err == nil {
+		t.Fatal("Expected rate parsing error, got nil")
+	}
+}
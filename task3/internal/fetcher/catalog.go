@@ -0,0 +1,35 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// CatalogFetcher отдаёт справочник валют потоком для catalog.ParseReader.
+// Вызывающий обязан закрыть reader.
+type CatalogFetcher interface {
+	OpenCatalog(ctx context.Context) (io.ReadCloser, error)
+}
+
+type catalogClient struct {
+	url         string
+	httpClient  *http.Client
+	maxBodySize int64
+}
+
+func NewCatalogClient(url string, opts ...Option) CatalogFetcher {
+	o := applyOptions(opts)
+	return &catalogClient{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		maxBodySize: o.maxBodySize,
+	}
+}
+
+func (c *catalogClient) OpenCatalog(ctx context.Context) (io.ReadCloser, error) {
+	return open(ctx, c.httpClient, c.url, c.maxBodySize)
+}
@@ -0,0 +1,41 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenCatalog_Success(t *testing.T) {
+	expectedBody := `<Valuta name="Foreign Currency Market Lib"></Valuta>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(expectedBody))
+	}))
+	defer server.Close()
+
+	r, err := NewCatalogClient(server.URL).OpenCatalog(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer r.Close()
+	body, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(body) != expectedBody {
+		t.Errorf("Expected body %q, got %q", expectedBody, body)
+	}
+}
+
+func TestOpenCatalog_BadStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := NewCatalogClient(server.URL).OpenCatalog(context.Background()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
@@ -0,0 +1,40 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DynamicFetcher загружает курсы одной валюты за период (XML_dynamic.asp):
+// один запрос вместо запроса на каждый день.
+type DynamicFetcher interface {
+	OpenDynamic(ctx context.Context, id string, from, to time.Time) (io.ReadCloser, error)
+}
+
+type dynamicClient struct {
+	baseURL     string
+	httpClient  *http.Client
+	maxBodySize int64
+}
+
+func NewDynamicClient(baseURL string, opts ...Option) DynamicFetcher {
+	o := applyOptions(opts)
+	return &dynamicClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		maxBodySize: o.maxBodySize,
+	}
+}
+
+func (c *dynamicClient) OpenDynamic(ctx context.Context, id string, from, to time.Time) (io.ReadCloser, error) {
+	fullUrl := fmt.Sprintf("%s?date_req1=%s&date_req2=%s&VAL_NM_RQ=%s",
+		c.baseURL, from.Format("02/01/2006"), to.Format("02/01/2006"), url.QueryEscape(id))
+
+	return open(ctx, c.httpClient, fullUrl, c.maxBodySize)
+}
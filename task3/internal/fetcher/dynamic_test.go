@@ -0,0 +1,32 @@
+package fetcher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOpenDynamic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("date_req1") != "01/10/2025" || q.Get("date_req2") != "03/10/2025" || q.Get("VAL_NM_RQ") != "R01235" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte("<ValCurs/>"))
+	}))
+	defer server.Close()
+
+	from := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	body, err := NewDynamicClient(server.URL).OpenDynamic(context.Background(), "R01235", from, from.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(body)
+	if string(data) != "<ValCurs/>" {
+		t.Errorf("Unexpected body: %q", data)
+	}
+}
@@ -0,0 +1,106 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const ecbEnvelope = `<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref"><Cube>%s</Cube></gesmes:Envelope>`
+
+// ecbClient скачивает eurofxref-документ один раз и отдаёт из него отдельные
+// дни в формате eurofxref-daily.xml, чтобы приложение могло запрашивать ЕЦБ
+// так же, как ЦБ РФ — по одной дате. Запоминается только успешная загрузка:
+// после ошибки следующий вызов скачивает документ заново.
+type ecbClient struct {
+	url         string
+	httpClient  *http.Client
+	maxBodySize int64
+
+	mu     sync.Mutex
+	loaded bool
+	days   map[string][]byte
+}
+
+func NewECBClient(url string, opts ...Option) CurrencyRateFetcher {
+	o := applyOptions(opts)
+	return &ecbClient{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		maxBodySize: o.maxBodySize,
+	}
+}
+
+func (c *ecbClient) GetCourseByDate(ctx context.Context, date time.Time) ([]byte, error) {
+	days, err := c.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	day, ok := days[date.Format("2006-01-02")]
+	if !ok {
+		return nil, nil
+	}
+	return []byte(fmt.Sprintf(ecbEnvelope, day)), nil
+}
+
+func (c *ecbClient) load(ctx context.Context) (map[string][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loaded {
+		return c.days, nil
+	}
+
+	// Документ общий для всех дат: отмена контекста одного вызова не должна
+	// прерывать загрузку для остальных, её время ограничивает таймаут клиента
+	body, err := get(context.WithoutCancel(ctx), c.httpClient, c.url, c.maxBodySize)
+	if err != nil {
+		return nil, err
+	}
+	days, err := splitECBDays(body)
+	if err != nil {
+		return nil, err
+	}
+	c.days, c.loaded = days, true
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// splitECBDays вырезает из документа исходные байты каждого <Cube time="...">.
+func splitECBDays(body []byte) (map[string][]byte, error) {
+	days := make(map[string][]byte)
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		start := decoder.InputOffset()
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return days, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		el, ok := tok.(xml.StartElement)
+		if !ok || el.Name.Local != "Cube" {
+			continue
+		}
+		for _, attr := range el.Attr {
+			if attr.Name.Local != "time" {
+				continue
+			}
+			if err := decoder.Skip(); err != nil {
+				return nil, err
+			}
+			days[attr.Value] = body[start:decoder.InputOffset()]
+		}
+	}
+}
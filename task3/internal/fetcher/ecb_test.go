@@ -0,0 +1,131 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newECBServer(t *testing.T, hits *int32) *httptest.Server {
+	t.Helper()
+	body, err := os.ReadFile("../parser/testdata/eurofxref-hist.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		w.Write(body)
+	}))
+}
+
+func TestECBClient_GetCourseByDate(t *testing.T) {
+	var hits int32
+	server := newECBServer(t, &hits)
+	defer server.Close()
+
+	client := NewECBClient(server.URL)
+	ctx := context.Background()
+
+	body, err := client.GetCourseByDate(ctx, time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(body), `<Cube time="2025-10-16">`) {
+		t.Errorf("Expected cube for 2025-10-16, got %q", body)
+	}
+	if strings.Contains(string(body), "2025-10-17") || strings.Contains(string(body), "2025-10-15") {
+		t.Errorf("Body must contain only the requested day, got %q", body)
+	}
+
+	// Выходной: данных нет, ошибки тоже
+	body, err = client.GetCourseByDate(ctx, time.Date(2025, time.October, 18, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(body) != 0 {
+		t.Errorf("Expected empty body for a day without data, got %q", body)
+	}
+
+	if hits != 1 {
+		t.Errorf("Expected the document to be downloaded once, got %d requests", hits)
+	}
+}
+
+func TestECBClient_BadStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewECBClient(server.URL)
+
+	_, err := client.GetCourseByDate(context.Background(), time.Now())
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected *HTTPStatusError with 503, got %v", err)
+	}
+}
+
+func TestECBClient_RetriesAfterError(t *testing.T) {
+	body, err := os.ReadFile("../parser/testdata/eurofxref-hist.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Первый запрос — временный сбой
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewECBClient(server.URL)
+	date := time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC)
+	if _, err := client.GetCourseByDate(context.Background(), date); err == nil {
+		t.Fatal("Expected error for the first request")
+	}
+	got, err := client.GetCourseByDate(context.Background(), date)
+	if err != nil {
+		t.Fatalf("Error must not be cached: %v", err)
+	}
+	if !strings.Contains(string(got), `<Cube time="2025-10-16">`) {
+		t.Errorf("Expected cube for 2025-10-16, got %q", got)
+	}
+	client.GetCourseByDate(context.Background(), date)
+	if hits != 2 {
+		t.Errorf("Expected a successful load to be cached, got %d requests", hits)
+	}
+}
+
+func TestECBClient_CanceledCallerDoesNotBreakLoad(t *testing.T) {
+	var hits int32
+	server := newECBServer(t, &hits)
+	defer server.Close()
+
+	client := NewECBClient(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	date := time.Date(2025, time.October, 16, 0, 0, 0, 0, time.UTC)
+	if _, err := client.GetCourseByDate(ctx, date); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled for the canceled caller, got %v", err)
+	}
+	got, err := client.GetCourseByDate(context.Background(), date)
+	if err != nil || len(got) == 0 {
+		t.Fatalf("Expected data for other callers, got %q, %v", got, err)
+	}
+	if hits != 1 {
+		t.Errorf("Expected the document to be downloaded once, got %d requests", hits)
+	}
+}
@@ -0,0 +1,29 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrUpstreamUnavailable — источник не ответил: сетевая ошибка, 5xx или 429.
+var ErrUpstreamUnavailable = errors.New("upstream unavailable")
+
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// HTTPStatusError — ответ с кодом, отличным от 200 OK.
+type HTTPStatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("bad status code: %d from %s", e.StatusCode, e.URL)
+}
+
+// Is относит ошибки сервера и ограничение частоты к ErrUpstreamUnavailable,
+// а ошибки запроса (4xx) — нет.
+func (e *HTTPStatusError) Is(target error) bool {
+	return target == ErrUpstreamUnavailable &&
+		(e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests)
+}
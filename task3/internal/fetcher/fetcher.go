@@ -12,17 +12,26 @@ type CurrencyRateFetcher interface {
 	GetCourseByDate(context.Context, time.Time) ([]byte, error)
 }
 
+// StreamFetcher отдаёт тело ответа без буферизации для потокового разбора
+// (parser.StreamRates). Вызывающий обязан закрыть reader.
+type StreamFetcher interface {
+	OpenCourseByDate(context.Context, time.Time) (io.ReadCloser, error)
+}
+
 type cbClient struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL     string
+	httpClient  *http.Client
+	maxBodySize int64
 }
 
-func NewClient(baseURL string) CurrencyRateFetcher {
+func NewClient(baseURL string, opts ...Option) CurrencyRateFetcher {
+	o := applyOptions(opts)
 	return &cbClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
+		maxBodySize: o.maxBodySize,
 	}
 }
 
@@ -32,26 +41,52 @@ func (c *cbClient) GetCourseByDate(ctx context.Context, date time.Time) ([]byte,
 
 	fullUrl := fmt.Sprintf("%s?date_req=%s", c.baseURL, dateStr)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", fullUrl, nil)
+	return get(ctx, c.httpClient, fullUrl, c.maxBodySize)
+}
+
+func (c *cbClient) OpenCourseByDate(ctx context.Context, date time.Time) (io.ReadCloser, error) {
+	fullUrl := fmt.Sprintf("%s?date_req=%s", c.baseURL, date.Format("02/01/2006"))
 
+	return open(ctx, c.httpClient, fullUrl, c.maxBodySize)
+}
+
+func get(ctx context.Context, httpClient *http.Client, url string, maxBodySize int64) ([]byte, error) {
+	body, err := open(ctx, httpClient, url, maxBodySize)
 	if err != nil {
 		return nil, err
 	}
+	defer body.Close()
+
+	return io.ReadAll(body)
+}
+
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
+
+func open(ctx context.Context, httpClient *http.Client, url string, maxBodySize int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, URL: url}
 	}
 
-	return body, nil
+	return limitedBody{
+		Reader: newLimitedReader(resp.Body, maxBodySize),
+		Closer: resp.Body,
+	}, nil
 }
@@ -2,6 +2,7 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -51,8 +52,15 @@ func TestGetCourseByDate_BadStatusCode(t *testing.T) {
 		t.Fatal("Expected error, got nil")
 	}
 
-	if !strings.Contains(err.Error(), "bad status code") {
-		t.Errorf("Expected 'bad status code' in error, got %v", err)
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("Expected *HTTPStatusError, got %v", err)
+	}
+	if statusErr.StatusCode != http.StatusInternalServerError || !strings.HasPrefix(statusErr.URL, server.URL) {
+		t.Errorf("Unexpected status error: %+v", statusErr)
+	}
+	if !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected 5xx to be ErrUpstreamUnavailable, got %v", err)
 	}
 }
 
@@ -104,6 +112,41 @@ func TestGetCourseByDate_HttpClientError(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected network error, got nil")
 	}
+	if !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
+
+func TestGetCourseByDate_ClientErrorIsNotUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).GetCourseByDate(context.Background(), time.Now())
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected *HTTPStatusError with 404, got %v", err)
+	}
+	if errors.Is(err, ErrUpstreamUnavailable) {
+		t.Error("4xx must not be ErrUpstreamUnavailable")
+	}
+}
+
+func TestGetCourseByDate_CanceledIsNotUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewClient(server.URL).GetCourseByDate(ctx, time.Now())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if errors.Is(err, ErrUpstreamUnavailable) {
+		t.Error("Canceled request must not be ErrUpstreamUnavailable")
+	}
 }
 
 func TestNewClient_ReturnsInterface(t *testing.T) {
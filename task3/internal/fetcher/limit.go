@@ -0,0 +1,61 @@
+package fetcher
+
+import (
+	"fmt"
+	"io"
+)
+
+// DefaultMaxBodySize ограничивает ответ ЦБ: суточный XML весит ~10 КБ,
+// динамика за десятилетия и справочник — единицы мегабайт.
+const DefaultMaxBodySize = 32 << 20
+
+type Option func(*options)
+
+type options struct {
+	maxBodySize int64
+}
+
+// WithMaxBodySize задаёт предельный размер тела ответа в байтах.
+func WithMaxBodySize(n int64) Option {
+	return func(o *options) {
+		o.maxBodySize = n
+	}
+}
+
+func applyOptions(opts []Option) options {
+	o := options{maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// limitedReader отдаёт не больше limit байт и возвращает ошибку, если тело
+// длиннее, вместо молчаливого обрезания как у io.LimitReader.
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+func newLimitedReader(r io.Reader, limit int64) *limitedReader {
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+	return &limitedReader{r: r, limit: limit}
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.read > l.limit {
+		return 0, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, l.limit)
+	}
+	if int64(len(p)) > l.limit-l.read+1 {
+		p = p[:l.limit-l.read+1]
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return n - int(l.read-l.limit), fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, l.limit)
+	}
+	return n, err
+}
@@ -0,0 +1,102 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetCourseByDate_BodyTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 101)))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, WithMaxBodySize(100)).GetCourseByDate(context.Background(), time.Now())
+	if err == nil {
+		t.Fatal("Expected error for oversized body, got nil")
+	}
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("Expected ErrBodyTooLarge, got %v", err)
+	}
+}
+
+func TestGetCourseByDate_BodyAtLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer server.Close()
+
+	body, err := NewClient(server.URL, WithMaxBodySize(100)).GetCourseByDate(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(body) != 100 {
+		t.Errorf("Expected 100 bytes, got %d", len(body))
+	}
+}
+
+func TestOpenCourseByDate(t *testing.T) {
+	expectedBody := `<ValCurs Date="22.10.2025"></ValCurs>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("date_req") != "22/10/2025" {
+			t.Errorf("Expected date_req=22/10/2025, got %s", r.URL.Query().Get("date_req"))
+		}
+		w.Write([]byte(expectedBody))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL).(StreamFetcher)
+	body, err := client.OpenCourseByDate(context.Background(), time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != expectedBody {
+		t.Errorf("Expected body %q, got %q", expectedBody, data)
+	}
+}
+
+func TestOpenCourseByDate_BodyTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 4096)))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithMaxBodySize(1000)).(StreamFetcher)
+	body, err := client.OpenCourseByDate(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err == nil {
+		t.Fatal("Expected error for oversized body, got nil")
+	}
+	if len(data) != 1000 {
+		t.Errorf("Expected reading to stop at the limit, got %d bytes", len(data))
+	}
+}
+
+func TestOpenCourseByDate_BadStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL).(StreamFetcher)
+	if _, err := client.OpenCourseByDate(context.Background(), time.Now()); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
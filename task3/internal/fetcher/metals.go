@@ -0,0 +1,35 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type MetalFetcher interface {
+	GetMetalsByRange(ctx context.Context, from, to time.Time) ([]byte, error)
+}
+
+type metalClient struct {
+	baseURL     string
+	httpClient  *http.Client
+	maxBodySize int64
+}
+
+func NewMetalClient(baseURL string, opts ...Option) MetalFetcher {
+	o := applyOptions(opts)
+	return &metalClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		maxBodySize: o.maxBodySize,
+	}
+}
+
+func (c *metalClient) GetMetalsByRange(ctx context.Context, from, to time.Time) ([]byte, error) {
+	fullUrl := fmt.Sprintf("%s?date_req1=%s&date_req2=%s", c.baseURL, from.Format("02/01/2006"), to.Format("02/01/2006"))
+
+	return get(ctx, c.httpClient, fullUrl, c.maxBodySize)
+}
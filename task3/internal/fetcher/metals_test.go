@@ -0,0 +1,51 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestGetMetalsByRange_Success(t *testing.T) {
+	expectedBody, err := os.ReadFile("testdata/xml_metall.xml")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("date_req1") != "01/10/2025" || q.Get("date_req2") != "02/10/2025" {
+			t.Errorf("Unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Write(expectedBody)
+	}))
+	defer server.Close()
+
+	client := NewMetalClient(server.URL)
+	from := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.October, 2, 0, 0, 0, 0, time.UTC)
+
+	body, err := client.GetMetalsByRange(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(body) != string(expectedBody) {
+		t.Errorf("Unexpected body: %q", body)
+	}
+}
+
+func TestGetMetalsByRange_BadStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewMetalClient(server.URL)
+
+	_, err := client.GetMetalsByRange(context.Background(), time.Now(), time.Now())
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
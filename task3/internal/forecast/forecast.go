@@ -0,0 +1,211 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"task3/internal/model"
+)
+
+type Method string
+
+const (
+	Linear Method = "linear"
+	Holt   Method = "holt"
+	ARIMA  Method = "arima"
+)
+
+// DefaultMethods — модели, которые строятся, если набор не задан. ARIMA
+// включается явно.
+var DefaultMethods = []Method{Linear, Holt}
+
+// ParseMethods разбирает список моделей через запятую: linear, holt, arima.
+func ParseMethods(s string) ([]Method, error) {
+	var methods []Method
+	seen := make(map[Method]bool)
+	for _, name := range strings.Split(s, ",") {
+		m := Method(strings.ToLower(strings.TrimSpace(name)))
+		switch m {
+		case Linear, Holt, ARIMA:
+		default:
+			return nil, fmt.Errorf("unknown forecast model %q: use linear, holt or arima", name)
+		}
+		if !seen[m] {
+			seen[m] = true
+			methods = append(methods, m)
+		}
+	}
+	return methods, nil
+}
+
+// Point — прогноз на дату и границы интервала.
+type Point struct {
+	Date         time.Time
+	Value        float64
+	Lower, Upper float64
+}
+
+// Param — оценённый параметр модели, например alpha у Хольта.
+type Param struct {
+	Name  string
+	Value float64
+}
+
+// Forecast — прогноз одной модели. Sigma — стандартное отклонение остатков
+// на шаг вперёд.
+type Forecast struct {
+	Method Method
+	Params []Param
+	Sigma  float64
+	Points []Point
+}
+
+// Backtest — прогноз по истории без последних наблюдений и его ошибки на
+// этих наблюдениях. MAPE — доля, не проценты.
+type Backtest struct {
+	Forecast
+	Actual []float64
+	MAE    float64
+	MAPE   float64
+}
+
+// fitted — подогнанная модель: прогноз на h шагов и стандартная ошибка
+// каждого шага.
+type fitted interface {
+	params() []Param
+	sigma() float64
+	predict(h int) (mean, se []float64)
+}
+
+// Official — официальные курсы ряда по возрастанию дат: восстановленные
+// политикой пропусков курсы в модели не попадают, шаг модели — одно
+// наблюдение.
+func Official(points []model.CurrencyRate) []model.CurrencyRate {
+	var result []model.CurrencyRate
+	for _, p := range points {
+		if !p.Filled && p.Rate > 0 {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Predict подгоняет модель method к ряду и прогнозирует horizon рабочих дней
+// после последнего курса. level — доверительная вероятность интервала,
+// например 0.95.
+func Predict(method Method, series []model.CurrencyRate, horizon int, level float64) (Forecast, error) {
+	if horizon < 1 {
+		return Forecast{}, fmt.Errorf("forecast horizon must be positive, got %d", horizon)
+	}
+	if err := checkLevel(level); err != nil {
+		return Forecast{}, err
+	}
+	if len(series) == 0 {
+		return Forecast{}, fmt.Errorf("no rates to forecast")
+	}
+	f, err := fit(method, values(series))
+	if err != nil {
+		return Forecast{}, err
+	}
+	return build(method, f, BusinessDays(series, horizon), level), nil
+}
+
+// Test прячет последние holdout курсов ряда, прогнозирует их по остальным и
+// считает MAE и MAPE.
+func Test(method Method, series []model.CurrencyRate, holdout int, level float64) (Backtest, error) {
+	if holdout < 1 {
+		return Backtest{}, fmt.Errorf("backtest holdout must be positive, got %d", holdout)
+	}
+	if err := checkLevel(level); err != nil {
+		return Backtest{}, err
+	}
+	if holdout >= len(series) {
+		return Backtest{}, fmt.Errorf("backtest holdout %d leaves no history: only %d rates", holdout, len(series))
+	}
+	train, test := series[:len(series)-holdout], series[len(series)-holdout:]
+	f, err := fit(method, values(train))
+	if err != nil {
+		return Backtest{}, err
+	}
+	dates := make([]time.Time, len(test))
+	for i, r := range test {
+		dates[i] = r.Date
+	}
+
+	b := Backtest{Forecast: build(method, f, dates, level), Actual: values(test)}
+	for i, p := range b.Points {
+		e := math.Abs(b.Actual[i] - p.Value)
+		b.MAE += e
+		b.MAPE += e / b.Actual[i]
+	}
+	b.MAE /= float64(holdout)
+	b.MAPE /= float64(holdout)
+	return b, nil
+}
+
+// BusinessDays — count дат после последнего курса ряда в те дни недели, в
+// которые в ряду есть курсы: у ЦБ курс устанавливается со вторника по
+// субботу, у ЕЦБ — с понедельника по пятницу. Ряду короче недели не из чего
+// вывести расписание — для него берутся дни с понедельника по пятницу.
+// Праздники не учитываются.
+func BusinessDays(series []model.CurrencyRate, count int) []time.Time {
+	last := series[len(series)-1].Date
+	var weekdays [7]bool
+	if last.Sub(series[0].Date) < 6*24*time.Hour {
+		for d := time.Monday; d <= time.Friday; d++ {
+			weekdays[d] = true
+		}
+	} else {
+		for _, r := range series {
+			weekdays[r.Date.Weekday()] = true
+		}
+	}
+
+	dates := make([]time.Time, 0, count)
+	for d := last.AddDate(0, 0, 1); len(dates) < count; d = d.AddDate(0, 0, 1) {
+		if weekdays[d.Weekday()] {
+			dates = append(dates, d)
+		}
+	}
+	return dates
+}
+
+func checkLevel(level float64) error {
+	if level <= 0 || level >= 1 {
+		return fmt.Errorf("prediction interval level must be between 0 and 1, got %v", level)
+	}
+	return nil
+}
+
+func fit(method Method, y []float64) (fitted, error) {
+	switch method {
+	case Linear:
+		return fitLinear(y)
+	case Holt:
+		return fitHolt(y)
+	case ARIMA:
+		return fitARIMA(y)
+	default:
+		return nil, fmt.Errorf("unknown forecast model %q", method)
+	}
+}
+
+func build(method Method, f fitted, dates []time.Time, level float64) Forecast {
+	z := math.Sqrt2 * math.Erfinv(level)
+	mean, se := f.predict(len(dates))
+	result := Forecast{Method: method, Params: f.params(), Sigma: f.sigma(), Points: make([]Point, len(dates))}
+	for i, d := range dates {
+		result.Points[i] = Point{Date: d, Value: mean[i], Lower: mean[i] - z*se[i], Upper: mean[i] + z*se[i]}
+	}
+	return result
+}
+
+func values(series []model.CurrencyRate) []float64 {
+	result := make([]float64, len(series))
+	for i, r := range series {
+		result[i] = r.Rate
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+// series строит ряд по дням с понедельника по пятницу, начиная с 1 сентября
+// 2025.
+func series(values ...float64) []model.CurrencyRate {
+	rates := make([]model.CurrencyRate, 0, len(values))
+	for d := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC); len(rates) < len(values); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			rates = append(rates, model.CurrencyRate{CharCode: "USD", Rate: values[len(rates)], Date: d})
+		}
+	}
+	return rates
+}
+
+// trend — прямая с небольшим периодическим шумом.
+func trend(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 80 + 0.1*float64(i) + 0.05*math.Sin(float64(i)*1.7)
+	}
+	return values
+}
+
+func TestParseMethods(t *testing.T) {
+	got, err := ParseMethods("holt, ARIMA,holt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != Holt || got[1] != ARIMA {
+		t.Errorf("Unexpected methods: %v", got)
+	}
+	if _, err := ParseMethods("prophet"); err == nil {
+		t.Error("Expected error for unknown model")
+	}
+}
+
+func TestBusinessDays(t *testing.T) {
+	// Ряд с понедельника по пятницу (ЕЦБ): после пятницы — понедельник
+	got := BusinessDays(series(trend(10)...), 2)
+	if got[0].Day() != 15 || got[1].Day() != 16 {
+		t.Errorf("Unexpected dates after Mon–Fri series: %v", got)
+	}
+
+	// Ряд со вторника по субботу (ЦБ): после субботы — вторник
+	var cbr []model.CurrencyRate
+	for d := time.Date(2025, 9, 2, 0, 0, 0, 0, time.UTC); len(cbr) < 10; d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Sunday && d.Weekday() != time.Monday {
+			cbr = append(cbr, model.CurrencyRate{Rate: 80, Date: d})
+		}
+	}
+	got = BusinessDays(cbr, 3)
+	if last := cbr[len(cbr)-1].Date; last.Weekday() != time.Saturday || last.Day() != 13 {
+		t.Fatalf("Unexpected test series end: %v", last)
+	}
+	if got[0].Day() != 16 || got[1].Day() != 17 || got[2].Day() != 18 {
+		t.Errorf("Unexpected dates after Tue–Sat series: %v", got)
+	}
+
+	// Пропуск на праздник не меняет расписание
+	holiday := append(append([]model.CurrencyRate(nil), cbr[:3]...), cbr[4:]...)
+	if got := BusinessDays(holiday, 1); got[0].Day() != 16 {
+		t.Errorf("Unexpected date after series with a holiday: %v", got)
+	}
+
+	// Ряд короче недели — с понедельника по пятницу
+	if got := BusinessDays(cbr[len(cbr)-2:], 1); got[0].Day() != 15 {
+		t.Errorf("Unexpected date after short series: %v", got)
+	}
+}
+
+func TestPredict_Linear(t *testing.T) {
+	f, err := Predict(Linear, series(80, 81, 82, 83, 84), 3, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ряд из пяти рабочих дней кончается в пятницу 5 сентября
+	if len(f.Points) != 3 || f.Points[0].Date.Day() != 8 {
+		t.Fatalf("Unexpected points: %+v", f.Points)
+	}
+	for i, p := range f.Points {
+		if want := 85 + float64(i); math.Abs(p.Value-want) > 1e-9 || p.Upper-p.Lower > 1e-9 {
+			t.Errorf("Point %d: expected exact %v, got %+v", i, want, p)
+		}
+	}
+	if f.Params[0].Name != "slope" || math.Abs(f.Params[0].Value-1) > 1e-9 {
+		t.Errorf("Unexpected params: %+v", f.Params)
+	}
+}
+
+func TestPredict_Intervals(t *testing.T) {
+	for _, method := range []Method{Linear, Holt, ARIMA} {
+		t.Run(string(method), func(t *testing.T) {
+			f, err := Predict(method, series(trend(60)...), 10, 0.95)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Тренд продолжается, интервал содержит прогноз и расширяется
+			if last := f.Points[9].Value; math.Abs(last-87) > 0.5 {
+				t.Errorf("Expected about 87 on day 10, got %v", last)
+			}
+			for i, p := range f.Points {
+				if p.Lower >= p.Value || p.Upper <= p.Value {
+					t.Errorf("Point %d outside its interval: %+v", i, p)
+				}
+				if i > 0 && p.Upper-p.Lower < f.Points[i-1].Upper-f.Points[i-1].Lower {
+					t.Errorf("Interval narrows at point %d", i)
+				}
+			}
+
+			// Чем выше уровень, тем шире интервал
+			narrow, _ := Predict(method, series(trend(60)...), 10, 0.5)
+			if narrow.Points[0].Upper >= f.Points[0].Upper {
+				t.Errorf("50%% interval must be narrower than 95%%")
+			}
+		})
+	}
+}
+
+func TestPredict_Errors(t *testing.T) {
+	if _, err := Predict(Holt, series(80, 81, 82), 5, 0.95); err == nil {
+		t.Error("Expected error for too short series")
+	}
+	if _, err := Predict(Linear, series(trend(10)...), 0, 0.95); err == nil {
+		t.Error("Expected error for zero horizon")
+	}
+	if _, err := Predict(Linear, series(trend(10)...), 5, 95); err == nil {
+		t.Error("Expected error for level out of range")
+	}
+	// Разности растут быстрее, чем позволяет стационарная AR(1)
+	if _, err := Predict(ARIMA, series(1, 2, 4, 8, 16, 32, 64), 5, 0.95); err == nil {
+		t.Error("Expected error for explosive series")
+	}
+}
+
+func TestTest(t *testing.T) {
+	b, err := Test(Linear, series(trend(60)...), 10, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Points) != 10 || len(b.Actual) != 10 {
+		t.Fatalf("Unexpected backtest size: %d points, %d actual", len(b.Points), len(b.Actual))
+	}
+	// Прогноз на спрятанные дни — на их же даты
+	if !b.Points[0].Date.Equal(series(trend(60)...)[50].Date) {
+		t.Errorf("Unexpected first date: %v", b.Points[0].Date)
+	}
+	if b.MAE <= 0 || b.MAE > 0.1 || b.MAPE <= 0 || b.MAPE > 0.001 {
+		t.Errorf("Unexpected errors: MAE %v, MAPE %v", b.MAE, b.MAPE)
+	}
+
+	if _, err := Test(Linear, series(trend(10)...), 10, 0.95); err == nil {
+		t.Error("Expected error when holdout takes the whole history")
+	}
+}
+
+func TestOfficial(t *testing.T) {
+	rates := series(80, 81, 82)
+	rates[1].Filled = true
+	if got := Official(rates); len(got) != 2 || got[1].Rate != 82 {
+		t.Errorf("Filled rates must be skipped: %+v", got)
+	}
+}
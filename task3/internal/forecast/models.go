@@ -0,0 +1,214 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+)
+
+// linear — линейный тренд y = a + b·t по методу наименьших квадратов.
+type linear struct {
+	a, b   float64
+	n      int
+	tMean  float64
+	sxx    float64
+	stdErr float64
+}
+
+func fitLinear(y []float64) (fitted, error) {
+	n := len(y)
+	if n < 3 {
+		return nil, fmt.Errorf("linear trend needs at least 3 rates, got %d", n)
+	}
+	var tMean, yMean float64
+	for t, v := range y {
+		tMean += float64(t)
+		yMean += v
+	}
+	tMean /= float64(n)
+	yMean /= float64(n)
+
+	var sxx, sxy float64
+	for t, v := range y {
+		dt := float64(t) - tMean
+		sxx += dt * dt
+		sxy += dt * (v - yMean)
+	}
+	m := &linear{b: sxy / sxx, n: n, tMean: tMean, sxx: sxx}
+	m.a = yMean - m.b*tMean
+
+	var sse float64
+	for t, v := range y {
+		e := v - m.a - m.b*float64(t)
+		sse += e * e
+	}
+	m.stdErr = math.Sqrt(sse / float64(n-2))
+	return m, nil
+}
+
+func (m *linear) params() []Param {
+	return []Param{{"slope", m.b}}
+}
+
+func (m *linear) sigma() float64 {
+	return m.stdErr
+}
+
+// predict учитывает и шум, и неопределённость оценки тренда: интервал
+// расширяется по мере удаления от середины истории.
+func (m *linear) predict(h int) ([]float64, []float64) {
+	mean, se := make([]float64, h), make([]float64, h)
+	for i := range mean {
+		t := float64(m.n + i)
+		dt := t - m.tMean
+		mean[i] = m.a + m.b*t
+		se[i] = m.stdErr * math.Sqrt(1+1/float64(m.n)+dt*dt/m.sxx)
+	}
+	return mean, se
+}
+
+// holt — двойное экспоненциальное сглаживание Хольта (уровень и тренд).
+// Параметры подбираются перебором по сетке с шагом 0,05 по сумме квадратов
+// ошибок прогноза на шаг вперёд.
+type holt struct {
+	alpha, beta  float64
+	level, trend float64
+	stdErr       float64
+}
+
+func fitHolt(y []float64) (fitted, error) {
+	if len(y) < 4 {
+		return nil, fmt.Errorf("double exponential smoothing needs at least 4 rates, got %d", len(y))
+	}
+	var best *holt
+	bestSSE := math.Inf(1)
+	for i := 1; i < 20; i++ {
+		for j := 1; j < 20; j++ {
+			m := &holt{alpha: float64(i) / 20, beta: float64(j) / 20}
+			if sse := m.smooth(y); sse < bestSSE {
+				best, bestSSE = m, sse
+			}
+		}
+	}
+	// Первая ошибка — на y[2]: уровень и тренд начинаются с y[0] и y[1]-y[0]
+	best.stdErr = math.Sqrt(bestSSE / float64(len(y)-2))
+	return best, nil
+}
+
+// smooth проходит ряд, оставляет в m последние уровень и тренд и возвращает
+// сумму квадратов ошибок прогноза на шаг вперёд.
+func (m *holt) smooth(y []float64) float64 {
+	m.level, m.trend = y[0], y[1]-y[0]
+	var sse float64
+	for t := 1; t < len(y); t++ {
+		forecast := m.level + m.trend
+		if t > 1 {
+			e := y[t] - forecast
+			sse += e * e
+		}
+		level := m.alpha*y[t] + (1-m.alpha)*forecast
+		m.trend = m.beta*(level-m.level) + (1-m.beta)*m.trend
+		m.level = level
+	}
+	return sse
+}
+
+func (m *holt) params() []Param {
+	return []Param{{"alpha", m.alpha}, {"beta", m.beta}}
+}
+
+func (m *holt) sigma() float64 {
+	return m.stdErr
+}
+
+// predict: дисперсия прогноза на h шагов σ²·(1 + Σ α²(1+jβ)², j < h) —
+// как у модели ETS(A,A,N).
+func (m *holt) predict(h int) ([]float64, []float64) {
+	mean, se := make([]float64, h), make([]float64, h)
+	variance := 1.0
+	for i := range mean {
+		if i > 0 {
+			c := m.alpha * (1 + float64(i)*m.beta)
+			variance += c * c
+		}
+		mean[i] = m.level + float64(i+1)*m.trend
+		se[i] = m.stdErr * math.Sqrt(variance)
+	}
+	return mean, se
+}
+
+// arima — ARIMA(1,1,0) со сносом: первые разности d подчиняются
+// d[t] = c + φ·d[t-1] + e; c и φ оцениваются регрессией.
+type arima struct {
+	c, phi float64
+	last   float64
+	diff   float64
+	stdErr float64
+}
+
+func fitARIMA(y []float64) (fitted, error) {
+	if len(y) < 5 {
+		return nil, fmt.Errorf("ARIMA(1,1,0) needs at least 5 rates, got %d", len(y))
+	}
+	d := make([]float64, len(y)-1)
+	for t := range d {
+		d[t] = y[t+1] - y[t]
+	}
+	x, z := d[:len(d)-1], d[1:]
+	n := float64(len(x))
+
+	var xMean, zMean float64
+	for i := range x {
+		xMean += x[i]
+		zMean += z[i]
+	}
+	xMean /= n
+	zMean /= n
+	var sxx, sxz float64
+	for i := range x {
+		sxx += (x[i] - xMean) * (x[i] - xMean)
+		sxz += (x[i] - xMean) * (z[i] - zMean)
+	}
+
+	m := &arima{last: y[len(y)-1], diff: d[len(d)-1]}
+	if sxx > 0 {
+		m.phi = sxz / sxx
+	}
+	if math.Abs(m.phi) >= 1 {
+		return nil, fmt.Errorf("ARIMA(1,1,0) fit is not stationary: phi = %.3f", m.phi)
+	}
+	m.c = zMean - m.phi*xMean
+
+	var sse float64
+	for i := range x {
+		e := z[i] - m.c - m.phi*x[i]
+		sse += e * e
+	}
+	m.stdErr = math.Sqrt(sse / (n - 2))
+	return m, nil
+}
+
+func (m *arima) params() []Param {
+	return []Param{{"phi", m.phi}, {"drift", m.c}}
+}
+
+func (m *arima) sigma() float64 {
+	return m.stdErr
+}
+
+// predict: ошибка курса на h шагов накапливает ошибки разностей с весами
+// ψ[j] = 1 + φ + … + φ^j.
+func (m *arima) predict(h int) ([]float64, []float64) {
+	mean, se := make([]float64, h), make([]float64, h)
+	level, diff := m.last, m.diff
+	var psi, power, variance float64 = 0, 1, 0
+	for i := range mean {
+		diff = m.c + m.phi*diff
+		level += diff
+		psi += power
+		power *= m.phi
+		variance += psi * psi
+		mean[i] = level
+		se[i] = m.stdErr * math.Sqrt(variance)
+	}
+	return mean, se
+}
@@ -0,0 +1,153 @@
+package gaps
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+// Fill — чем заполнять дни без курса внутри периода.
+type Fill int
+
+const (
+	// Leave оставляет пропуски как есть.
+	Leave Fill = iota
+	// CarryForward переносит последний официальный курс: так ЦБ и
+	// определяет курс на выходные и праздники.
+	CarryForward
+	// Linear интерполирует между соседними официальными курсами; до
+	// первого и после последнего курса пропуски остаются.
+	Linear
+)
+
+var fillNames = map[Fill]string{
+	Leave:        "leave",
+	CarryForward: "carry",
+	Linear:       "linear",
+}
+
+func (f Fill) String() string {
+	return fillNames[f]
+}
+
+func ParseFill(s string) (Fill, error) {
+	for f, name := range fillNames {
+		if name == s {
+			return f, nil
+		}
+	}
+	return Leave, fmt.Errorf("unknown gap fill %q: use leave, carry or linear", s)
+}
+
+// Policy — как обращаться с пропусками в рядах курсов.
+type Policy struct {
+	Fill Fill
+	// MinCoverage — доля дат периода с официальным курсом (0..1), ниже
+	// которой валюта исключается; 0 — не исключать.
+	MinCoverage float64
+}
+
+// Apply применяет политику к курсам за from..to. Даты периода — дни с
+// from по to; покрытие валюты считается по датам, на которые курс есть
+// хотя бы у одной валюты, чтобы выходные не снижали его. Возвращает курсы
+// вместе с заполненными (Filled) и исключённые валюты.
+func (p Policy) Apply(rates []model.CurrencyRate, from, to time.Time) ([]model.CurrencyRate, []model.CurrencyRate) {
+	if p.Fill == Leave && p.MinCoverage <= 0 {
+		return rates, nil
+	}
+	from, to = day(from), day(to)
+
+	var keys []string
+	series := make(map[string][]model.CurrencyRate)
+	published := make(map[time.Time]bool)
+	for _, r := range rates {
+		k := stats.Key(r)
+		if _, ok := series[k]; !ok {
+			keys = append(keys, k)
+		}
+		series[k] = append(series[k], r)
+		if d := day(r.Date); !d.Before(from) && !d.After(to) {
+			published[d] = true
+		}
+	}
+
+	var result, dropped []model.CurrencyRate
+	for _, k := range keys {
+		points := series[k]
+		sort.Slice(points, func(i, j int) bool { return points[i].Date.Before(points[j].Date) })
+		if p.MinCoverage > 0 && coverage(points, from, to, len(published)) < p.MinCoverage {
+			dropped = append(dropped, points[len(points)-1])
+			continue
+		}
+		result = append(result, fill(points, from, to, p.Fill)...)
+	}
+	return result, dropped
+}
+
+func coverage(points []model.CurrencyRate, from, to time.Time, dates int) float64 {
+	if dates == 0 {
+		return 0
+	}
+	var n int
+	for _, r := range points {
+		if d := day(r.Date); !d.Before(from) && !d.After(to) {
+			n++
+		}
+	}
+	return float64(n) / float64(dates)
+}
+
+// fill дополняет отсортированный ряд одной валюты курсами на дни from..to
+// без официального курса.
+func fill(points []model.CurrencyRate, from, to time.Time, f Fill) []model.CurrencyRate {
+	if f == Leave || len(points) == 0 {
+		return points
+	}
+
+	result := make([]model.CurrencyRate, 0, len(points))
+	// next — первая официальная точка после текущего дня
+	next := 0
+	for next < len(points) && day(points[next].Date).Before(from) {
+		result = append(result, points[next])
+		next++
+	}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if next < len(points) && day(points[next].Date).Equal(d) {
+			result = append(result, points[next])
+			next++
+			continue
+		}
+		if next == 0 {
+			// До первого курса валюты заполнять нечем
+			continue
+		}
+		prev := points[next-1]
+		switch f {
+		case CarryForward:
+			result = append(result, filled(prev, prev.Rate, d))
+		case Linear:
+			if next == len(points) {
+				continue
+			}
+			after := points[next]
+			span := day(after.Date).Sub(day(prev.Date)).Hours()
+			share := d.Sub(day(prev.Date)).Hours() / span
+			result = append(result, filled(after, prev.Rate+(after.Rate-prev.Rate)*share, d))
+		}
+	}
+	return append(result, points[next:]...)
+}
+
+func filled(r model.CurrencyRate, rate float64, d time.Time) model.CurrencyRate {
+	r.Rate = rate
+	r.Date = d
+	r.Filled = true
+	return r
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,92 @@
+package gaps
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func oct(d int) time.Time {
+	return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC)
+}
+
+func usd(d int, rate float64) model.CurrencyRate {
+	return model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: rate, Date: oct(d)}
+}
+
+func eur(d int, rate float64) model.CurrencyRate {
+	return model.CurrencyRate{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: rate, Date: oct(d)}
+}
+
+func TestPolicy_Apply_Leave(t *testing.T) {
+	rates := []model.CurrencyRate{usd(1, 80), usd(4, 83)}
+	got, dropped := Policy{}.Apply(rates, oct(1), oct(5))
+	if len(got) != 2 || len(dropped) != 0 {
+		t.Errorf("Leave must not change rates: %+v %+v", got, dropped)
+	}
+}
+
+func TestPolicy_Apply_CarryForward(t *testing.T) {
+	rates := []model.CurrencyRate{usd(4, 83), usd(1, 80)}
+	got, _ := Policy{Fill: CarryForward}.Apply(rates, oct(1), oct(5))
+
+	// 2, 3 — курс 1-го, 5 — курс 4-го
+	want := []float64{80, 80, 80, 83, 83}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d points, got %+v", len(want), got)
+	}
+	for i, r := range got {
+		if r.Rate != want[i] || !r.Date.Equal(oct(i+1)) {
+			t.Errorf("Point %d: %+v, expected %v on %v", i, r, want[i], oct(i+1))
+		}
+		if filled := i != 0 && i != 3; r.Filled != filled {
+			t.Errorf("Point %d: Filled=%v, expected %v", i, r.Filled, filled)
+		}
+	}
+}
+
+func TestPolicy_Apply_Linear(t *testing.T) {
+	// Валюта появилась посреди периода: до первого курса не заполняется, после
+	// последнего тоже
+	rates := []model.CurrencyRate{usd(2, 80), usd(5, 83)}
+	got, _ := Policy{Fill: Linear}.Apply(rates, oct(1), oct(6))
+
+	want := map[time.Time]float64{oct(2): 80, oct(3): 81, oct(4): 82, oct(5): 83}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d points, got %+v", len(want), got)
+	}
+	for _, r := range got {
+		if math.Abs(r.Rate-want[r.Date]) > 1e-9 {
+			t.Errorf("Unexpected point %+v, expected %v", r, want[r.Date])
+		}
+		if r.Filled != (r.Date.Equal(oct(3)) || r.Date.Equal(oct(4))) {
+			t.Errorf("Unexpected Filled: %+v", r)
+		}
+	}
+}
+
+func TestPolicy_Apply_MinCoverage(t *testing.T) {
+	// Выходные 4–5 октября не считаются: курсы есть на 4 даты, у EUR — на 1
+	rates := []model.CurrencyRate{
+		usd(1, 80), usd(2, 81), usd(3, 82), usd(6, 83),
+		eur(6, 95),
+	}
+	got, dropped := Policy{MinCoverage: 0.5}.Apply(rates, oct(1), oct(6))
+	if len(got) != 4 || len(dropped) != 1 || dropped[0].CharCode != "EUR" {
+		t.Errorf("EUR must be dropped: %+v %+v", got, dropped)
+	}
+}
+
+func TestParseFill(t *testing.T) {
+	for _, f := range []Fill{Leave, CarryForward, Linear} {
+		got, err := ParseFill(f.String())
+		if err != nil || got != f {
+			t.Errorf("ParseFill(%q) = %v, %v", f.String(), got, err)
+		}
+	}
+	if _, err := ParseFill("spline"); err == nil {
+		t.Error("Expected error for unknown fill")
+	}
+}
@@ -0,0 +1,216 @@
+package i18n
+
+func init() {
+	Register(&Locale{
+		Lang:       "ru",
+		Decimal:    ",",
+		Thousands:  " ",
+		DateLayout: "2006-01-02",
+		Messages: Catalog{
+			"unit.rub":                   "руб.",
+			"report.max":                 "Максимум: %s — %s %s на %s",
+			"report.min":                 "Минимум: %s — %s %s на %s",
+			"report.avg":                 "Среднее значение курса: %s %s",
+			"series.line":                "%s: %s%s на %s (минимум %s%s на %s, максимум %s%s на %s)",
+			"series.empty":               "%s: нет данных за период",
+			"diagnostics.title":          "Пропущено записей: %d",
+			"diagnostics.line":           "  %s #%d %s: %s=%q — %s",
+			"report.source":              "Источник: %s",
+			"report.missing":             "Даты без данных: %d",
+			"report.as_of":               "Данные по состоянию на %s %s UTC",
+			"gaps.filled":                "Заполнено пропусков: %d (%s)",
+			"gaps.carry":                 "перенос последнего курса",
+			"gaps.linear":                "линейная интерполяция",
+			"gaps.dropped":               "Исключены из-за покрытия ниже %s: %s",
+			"table.filled":               "Заполнено",
+			"corr.title":                 "Корреляция дневных лог-доходностей (%s)",
+			"corr.pearson":               "Пирсон",
+			"corr.spearman":              "Спирмен",
+			"corr.observations":          "Общих доходностей на пару: от %d до %d",
+			"corr.legend":                "Шкала |r|: %s",
+			"corr.rolling":               "Скользящая корреляция %s/%s (окно %d): последняя %s на %s, минимум %s, максимум %s",
+			"corr.rolling_empty":         "Скользящая корреляция %s/%s (окно %d): недостаточно данных",
+			"forecast.title":             "Прогноз %s (%s) на %d рабочих дней, интервал %s",
+			"forecast.last":              "Последний курс %s: %s %s, курсов в истории: %d",
+			"forecast.model":             "%s (%s)",
+			"forecast.point":             "  %s  %s  [%s — %s]",
+			"forecast.linear":            "Линейный тренд",
+			"forecast.holt":              "Хольт",
+			"forecast.arima":             "ARIMA(1,1,0)",
+			"forecast.backtest":          "Бэктест на последних %d курсах:",
+			"forecast.errors":            "  %s: MAE %s %s, MAPE %s, в интервале %s",
+			"portfolio.title":            "Портфель на %s: %s %s",
+			"portfolio.gain":             "Стоимость покупки %s %s, нереализованная курсовая разница %s %s (%s)",
+			"portfolio.positions":        "Позиции:",
+			"portfolio.position":         "  %s: %s ед., стоимость %s %s (%s портфеля), курсовая разница %s %s (%s)",
+			"portfolio.drawdown":         "Максимальная просадка без учёта покупок: %s с %s по %s",
+			"portfolio.no_drawdown":      "Просадок не было",
+			"portfolio.series":           "Стоимость по датам:",
+			"portfolio.point":            "  %s  %s",
+			"moves.line":                 "%s: %s, %s",
+			"moves.drawdown":             "просадка %s с %s по %s (%s → %s)",
+			"moves.no_drawdown":          "просадки не было",
+			"moves.run_up":               "рост %s с %s по %s (%s → %s)",
+			"moves.no_run_up":            "роста не было",
+			"moves.gains":                "  крупнейшие дневные росты: %s",
+			"moves.losses":               "  крупнейшие дневные падения: %s",
+			"moves.up":                   "  самая длинная серия роста — %d, с %s по %s (%s)",
+			"moves.down":                 "  самая длинная серия падения — %d, с %s по %s (%s)",
+			"compare.title":              "Сравнение с периодом %s — %s:",
+			"compare.line":               "  %s: среднее %s → %s %s (%s), максимум %s → %s (%s), минимум %s → %s (%s), волатильность %s → %s (%s)",
+			"compare.only_base":          "  %s: нет курсов в текущем периоде",
+			"compare.only_current":       "  %s: нет курсов в базовом периоде",
+			"compare.movers":             "Наибольшие изменения среднего: %s",
+			"compare.pp":                 "%s п.п.",
+			"table.base_avg":             "Среднее (база)",
+			"table.avg_change":           "Δ среднего",
+			"table.max_change":           "Δ максимума",
+			"table.min_change":           "Δ минимума",
+			"table.base_volatility":      "Волатильность (база)",
+			"table.volatility_change":    "Δ волатильности",
+			"xlsx.compare":               "Сравнение",
+			"revisions.summary":          "Проверено дней: %d, изменилось: %d",
+			"revisions.title":            "Исправления курсов: %d",
+			"revisions.changed":          "  %s %s: %s → %s (обнаружено %s)",
+			"revisions.added":            "  %s %s: добавлен курс %s (обнаружено %s)",
+			"revisions.removed":          "  %s %s: курс %s удалён (обнаружено %s)",
+			"report.title":               "Курсы за %s — %s",
+			"table.date":                 "Дата",
+			"table.currency":             "Валюта",
+			"table.name":                 "Название",
+			"table.first":                "Начало",
+			"table.last":                 "Конец",
+			"table.change":               "Изменение",
+			"table.min":                  "Минимум",
+			"table.min_date":             "Дата минимума",
+			"table.max":                  "Максимум",
+			"table.max_date":             "Дата максимума",
+			"table.avg":                  "Среднее",
+			"table.count":                "Курсов",
+			"table.volatility":           "Волатильность",
+			"table.trend":                "Динамика",
+			"html.charts":                "Графики",
+			"xlsx.rates":                 "Курсы",
+			"xlsx.stats":                 "Статистика",
+			"xlsx.anomalies":             "Аномалии",
+			"anomaly.title":              "Аномалии: %d",
+			"anomaly.line":               "  %s %s: %s → %s (%s) — %s",
+			"anomaly.zscore":             "скачок %sσ",
+			"anomaly.hampel":             "выброс %s MAD от медианы соседних дней",
+			"anomaly.nominal":            "курс изменился в %s раза — похоже на смену номинала или ошибку разбора",
+			"table.prev":                 "Прежний курс",
+			"table.rate":                 "Курс",
+			"table.reason":               "Причина",
+			"name:Gold":                  "Золото",
+			"name:Silver":                "Серебро",
+			"name:Platinum":              "Платина",
+			"name:Palladium":             "Палладий",
+			"name:Key rate":              "Ключевая ставка",
+			"name:Bank of Russia":        "Банк России",
+			"name:European Central Bank": "Европейский центральный банк",
+		},
+	})
+
+	Register(&Locale{
+		Lang:       "en",
+		Decimal:    ".",
+		Thousands:  ",",
+		DateLayout: "2006-01-02",
+		Messages: Catalog{
+			"unit.rub":                "RUB",
+			"report.max":              "Maximum: %s — %s %s on %s",
+			"report.min":              "Minimum: %s — %s %s on %s",
+			"report.avg":              "Average rate: %s %s",
+			"series.line":             "%s: %s%s on %s (min %s%s on %s, max %s%s on %s)",
+			"series.empty":            "%s: no data for the period",
+			"diagnostics.title":       "Skipped entries: %d",
+			"diagnostics.line":        "  %s #%d %s: %s=%q — %s",
+			"report.source":           "Source: %s",
+			"report.missing":          "Dates without data: %d",
+			"report.as_of":            "Data as of %s %s UTC",
+			"gaps.filled":             "Filled gaps: %d (%s)",
+			"gaps.carry":              "last rate carried forward",
+			"gaps.linear":             "linear interpolation",
+			"gaps.dropped":            "Dropped for coverage below %s: %s",
+			"table.filled":            "Filled",
+			"corr.title":              "Correlation of daily log returns (%s)",
+			"corr.pearson":            "Pearson",
+			"corr.spearman":           "Spearman",
+			"corr.observations":       "Common returns per pair: %d to %d",
+			"corr.legend":             "Scale |r|: %s",
+			"corr.rolling":            "Rolling correlation %s/%s (window %d): last %s on %s, min %s, max %s",
+			"corr.rolling_empty":      "Rolling correlation %s/%s (window %d): not enough data",
+			"forecast.title":          "%s (%s) forecast for %d business days, %s interval",
+			"forecast.last":           "Last rate %s: %s %s, rates in history: %d",
+			"forecast.model":          "%s (%s)",
+			"forecast.point":          "  %s  %s  [%s — %s]",
+			"forecast.linear":         "Linear trend",
+			"forecast.holt":           "Holt",
+			"forecast.arima":          "ARIMA(1,1,0)",
+			"forecast.backtest":       "Backtest on the last %d rates:",
+			"forecast.errors":         "  %s: MAE %s %s, MAPE %s, within interval %s",
+			"portfolio.title":         "Portfolio on %s: %s %s",
+			"portfolio.gain":          "Cost %s %s, unrealized FX gain %s %s (%s)",
+			"portfolio.positions":     "Positions:",
+			"portfolio.position":      "  %s: %s units, value %s %s (%s of portfolio), FX gain %s %s (%s)",
+			"portfolio.drawdown":      "Max drawdown excluding purchases: %s from %s to %s",
+			"portfolio.no_drawdown":   "No drawdowns",
+			"portfolio.series":        "Value by date:",
+			"portfolio.point":         "  %s  %s",
+			"moves.line":              "%s: %s, %s",
+			"moves.drawdown":          "drawdown %s from %s to %s (%s → %s)",
+			"moves.no_drawdown":       "no drawdown",
+			"moves.run_up":            "run-up %s from %s to %s (%s → %s)",
+			"moves.no_run_up":         "no run-up",
+			"moves.gains":             "  largest daily gains: %s",
+			"moves.losses":            "  largest daily losses: %s",
+			"moves.up":                "  longest up streak — %d, from %s to %s (%s)",
+			"moves.down":              "  longest down streak — %d, from %s to %s (%s)",
+			"compare.title":           "Compared with %s — %s:",
+			"compare.line":            "  %s: average %s → %s %s (%s), max %s → %s (%s), min %s → %s (%s), volatility %s → %s (%s)",
+			"compare.only_base":       "  %s: no rates in the current period",
+			"compare.only_current":    "  %s: no rates in the base period",
+			"compare.movers":          "Biggest movers by average: %s",
+			"compare.pp":              "%s pp",
+			"table.base_avg":          "Average (base)",
+			"table.avg_change":        "Δ average",
+			"table.max_change":        "Δ max",
+			"table.min_change":        "Δ min",
+			"table.base_volatility":   "Volatility (base)",
+			"table.volatility_change": "Δ volatility",
+			"xlsx.compare":            "Comparison",
+			"revisions.summary":       "Checked %d days, changed %d",
+			"revisions.title":         "Revised rates: %d",
+			"revisions.changed":       "  %s %s: %s → %s (detected %s)",
+			"revisions.added":         "  %s %s: rate %s added (detected %s)",
+			"revisions.removed":       "  %s %s: rate %s removed (detected %s)",
+			"report.title":            "Rates for %s — %s",
+			"table.date":              "Date",
+			"table.currency":          "Currency",
+			"table.name":              "Name",
+			"table.first":             "First",
+			"table.last":              "Last",
+			"table.change":            "Change",
+			"table.min":               "Min",
+			"table.min_date":          "Min date",
+			"table.max":               "Max",
+			"table.max_date":          "Max date",
+			"table.avg":               "Average",
+			"table.count":             "Count",
+			"table.volatility":        "Volatility",
+			"table.trend":             "Trend",
+			"html.charts":             "Charts",
+			"xlsx.rates":              "Rates",
+			"xlsx.stats":              "Statistics",
+			"xlsx.anomalies":          "Anomalies",
+			"anomaly.title":           "Anomalies: %d",
+			"anomaly.line":            "  %s %s: %s → %s (%s) — %s",
+			"anomaly.zscore":          "%sσ move",
+			"anomaly.hampel":          "%s MAD off the median of neighbouring days",
+			"anomaly.nominal":         "rate changed %s× — looks like a nominal change or a parse error",
+			"table.prev":              "Previous rate",
+			"table.rate":              "Rate",
+			"table.reason":            "Reason",
+		},
+	})
+}
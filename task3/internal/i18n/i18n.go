@@ -0,0 +1,106 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Catalog — сообщения локали по ключу в формате fmt. Ключи с префиксом
+// "name:" переводят названия из данных (металлы, показатели).
+type Catalog map[string]string
+
+// Locale — каталог сообщений и правила форматирования чисел и дат.
+type Locale struct {
+	Lang       string
+	Decimal    string
+	Thousands  string
+	DateLayout string
+	Messages   Catalog
+}
+
+var (
+	mu      sync.RWMutex
+	locales = map[string]*Locale{}
+)
+
+// Register добавляет или заменяет локаль; встроенные — "ru" и "en".
+func Register(l *Locale) {
+	mu.Lock()
+	defer mu.Unlock()
+	locales[l.Lang] = l
+}
+
+func Get(lang string) (*Locale, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	l, ok := locales[strings.ToLower(lang)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q, available: %s", lang, strings.Join(langs(), ", "))
+	}
+	return l, nil
+}
+
+func langs() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// T форматирует сообщение key; отсутствующий ключ возвращается как есть,
+// чтобы пробел в каталоге был заметен в выводе, а не ронял программу.
+func (l *Locale) T(key string, args ...any) string {
+	msg, ok := l.Messages[key]
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Name переводит название из данных, например "Gold" или "Key rate".
+func (l *Locale) Name(name string) string {
+	if msg, ok := l.Messages["name:"+name]; ok {
+		return msg
+	}
+	return name
+}
+
+// Number форматирует число с decimals знаками после запятой и разделителями локали.
+func (l *Locale) Number(v float64, decimals int) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	for i, d := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(l.Thousands)
+		}
+		b.WriteRune(d)
+	}
+	if frac != "" {
+		b.WriteString(l.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+func (l *Locale) Date(t time.Time) string {
+	return t.Format(l.DateLayout)
+}
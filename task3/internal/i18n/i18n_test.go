@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	for _, lang := range []string{"ru", "en", "EN"} {
+		if _, err := Get(lang); err != nil {
+			t.Errorf("Get(%q): unexpected error: %v", lang, err)
+		}
+	}
+	if _, err := Get("de"); err == nil {
+		t.Error("Expected error for unsupported language, got nil")
+	}
+}
+
+func TestNumber(t *testing.T) {
+	ru, _ := Get("ru")
+	en, _ := Get("en")
+
+	tests := []struct {
+		v        float64
+		decimals int
+		ru, en   string
+	}{
+		{95.5, 4, "95,5000", "95.5000"},
+		{0.0058, 4, "0,0058", "0.0058"},
+		{10535.97, 2, "10 535,97", "10,535.97"},
+		{1234567.891, 1, "1 234 567,9", "1,234,567.9"},
+		{-1234.5, 2, "-1 234,50", "-1,234.50"},
+		{-0.00001, 2, "0,00", "0.00"},
+		{999, 0, "999", "999"},
+		{1000, 0, "1 000", "1,000"},
+	}
+	for _, tt := range tests {
+		if got := ru.Number(tt.v, tt.decimals); got != tt.ru {
+			t.Errorf("ru.Number(%v, %d): expected %q, got %q", tt.v, tt.decimals, tt.ru, got)
+		}
+		if got := en.Number(tt.v, tt.decimals); got != tt.en {
+			t.Errorf("en.Number(%v, %d): expected %q, got %q", tt.v, tt.decimals, tt.en, got)
+		}
+	}
+
+	if got := en.Number(math.NaN(), 2); got != "NaN" {
+		t.Errorf("Expected NaN, got %q", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	ru, _ := Get("ru")
+
+	if got := ru.T("report.avg", "42,1234", "руб."); got != "Среднее значение курса: 42,1234 руб." {
+		t.Errorf("Unexpected message: %q", got)
+	}
+	if got := ru.T("missing.key"); got != "missing.key" {
+		t.Errorf("Expected missing key to be returned as is, got %q", got)
+	}
+}
+
+func TestName(t *testing.T) {
+	ru, _ := Get("ru")
+	en, _ := Get("en")
+
+	if got := ru.Name("Gold"); got != "Золото" {
+		t.Errorf("Expected Золото, got %q", got)
+	}
+	if got := en.Name("Gold"); got != "Gold" {
+		t.Errorf("Expected Gold, got %q", got)
+	}
+	if got := ru.Name("US Dollar"); got != "US Dollar" {
+		t.Errorf("Expected untranslated name as is, got %q", got)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(&Locale{Lang: "xx", Decimal: "'", Thousands: "_", DateLayout: "2006", Messages: Catalog{"hi": "hello %s"}})
+
+	l, err := Get("xx")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := l.Number(12345.6, 1); got != "12_345'6" {
+		t.Errorf("Unexpected number: %q", got)
+	}
+	if got := l.T("hi", "there"); got != "hello there" {
+		t.Errorf("Unexpected message: %q", got)
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+// Причины аномалии.
+const (
+	// AnomalyZScore — дневное изменение далеко от обычного разброса за
+	// предыдущие дни.
+	AnomalyZScore = "zscore"
+	// AnomalyHampel — изменение выбивается из медианы соседних дней
+	// (фильтр Хампеля по MAD).
+	AnomalyHampel = "hampel"
+	// AnomalyNominal — курс изменился почти ровно в 10, 100, ... раз: похоже
+	// на смену номинала или ошибку разбора, а не на движение рынка.
+	AnomalyNominal = "nominal"
+)
+
+// Anomaly — подозрительное дневное изменение курса валюты.
+type Anomaly struct {
+	Prev CurrencyRate
+	Rate CurrencyRate
+	// Return — логарифмическая доходность Prev → Rate.
+	Return float64
+	Reason string
+	// Score — насколько изменение необычно: z-оценка, отклонение в MAD или
+	// для AnomalyNominal — во сколько раз изменился курс.
+	Score float64
+}
+
+// Code — буквенный код валюты, а если его нет — код ЦБ или название.
+func (a Anomaly) Code() string {
+	switch {
+	case a.Rate.CharCode != "":
+		return a.Rate.CharCode
+	case a.Rate.ID != "":
+		return a.Rate.ID
+	default:
+		return a.Rate.Name
+	}
+}
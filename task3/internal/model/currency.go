@@ -3,7 +3,12 @@ package model
 import "time"
 
 type CurrencyRate struct {
-	Name string
-	Rate float64
-	Date time.Time
+	ID       string
+	CharCode string
+	Name     string
+	Rate     float64
+	Date     time.Time
+	// Filled — курс не опубликован, а восстановлен политикой пропусков
+	// (gaps.Policy).
+	Filled bool
 }
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Diagnostic описывает запись Valute, пропущенную при нестрогом разборе.
+type Diagnostic struct {
+	Date     time.Time
+	Index    int
+	Currency string
+	Field    string
+	Raw      string
+	Reason   string
+}
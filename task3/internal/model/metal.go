@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Коды драгоценных металлов в xml_metall.asp.
+const (
+	MetalGold      = 1
+	MetalSilver    = 2
+	MetalPlatinum  = 3
+	MetalPalladium = 4
+)
+
+var metalNames = map[int]string{
+	MetalGold:      "Gold",
+	MetalSilver:    "Silver",
+	MetalPlatinum:  "Platinum",
+	MetalPalladium: "Palladium",
+}
+
+// MetalQuote — учётная цена металла в рублях за грамм.
+type MetalQuote struct {
+	Code int
+	Name string
+	Buy  float64
+	Sell float64
+	Date time.Time
+}
+
+func MetalName(code int) string {
+	return metalNames[code]
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Revision — исправление уже опубликованного курса: источник отдал другое
+// значение при повторной загрузке дня.
+type Revision struct {
+	// Day — день запроса к источнику.
+	Day time.Time
+	// Old — прежнее значение; пустое, если валюта появилась в дне позже.
+	Old CurrencyRate
+	// New — новое значение; пустое, если валюта из дня пропала.
+	New CurrencyRate
+	// Detected — когда исправление обнаружено.
+	Detected time.Time
+}
+
+// Added — валюты в дне раньше не было.
+func (r Revision) Added() bool {
+	return r.Old.Date.IsZero()
+}
+
+// Removed — валюта пропала из дня.
+func (r Revision) Removed() bool {
+	return r.New.Date.IsZero()
+}
+
+// Rate — курс, к которому относится исправление: новый, а для пропавшей
+// валюты — прежний.
+func (r Revision) Rate() CurrencyRate {
+	if r.Removed() {
+		return r.Old
+	}
+	return r.New
+}
+
+// Code — буквенный код валюты, а если его нет — код ЦБ.
+func (r Revision) Code() string {
+	rate := r.Rate()
+	if rate.CharCode != "" {
+		return rate.CharCode
+	}
+	return rate.ID
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type Point struct {
+	Date  time.Time
+	Value float64
+	// Filled — значение восстановлено политикой пропусков.
+	Filled bool
+}
+
+// Series — ряд показателя (ключевая ставка, RUONIA и т.п.) в порядке дат.
+type Series struct {
+	Name   string
+	Unit   string
+	Points []Point
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"task3/internal/model"
+)
+
+const euro = "EUR"
+
+// ECBEnvelope описывает документы eurofxref (daily и hist): курсы к евро,
+// сгруппированные по дням во вложенных элементах Cube.
+type ECBEnvelope struct {
+	XMLName xml.Name `xml:"Envelope"`
+	Days    []ECBDay `xml:"Cube>Cube"`
+}
+
+type ECBDay struct {
+	Time  string    `xml:"time,attr"`
+	Rates []ECBRate `xml:"Cube"`
+}
+
+type ECBRate struct {
+	Currency string `xml:"currency,attr"`
+	RateStr  string `xml:"rate,attr"`
+}
+
+// ParseECBRates разбирает eurofxref-документ и пересчитывает курсы в базовую
+// валюту base: Rate — стоимость одной единицы валюты в base.
+func ParseECBRates(xmlData []byte, base string) ([]model.CurrencyRate, error) {
+	var env ECBEnvelope
+	decoder := xml.NewDecoder(bytes.NewReader(xmlData))
+	if err := decoder.Decode(&env); err != nil {
+		return nil, xmlError(err)
+	}
+
+	base = strings.ToUpper(base)
+	var result []model.CurrencyRate
+	for _, day := range env.Days {
+		date, err := time.Parse("2006-01-02", day.Time)
+		if err != nil {
+			return nil, &ParseError{Field: "time", Raw: day.Time, Err: err}
+		}
+
+		perEuro := map[string]float64{euro: 1}
+		codes := make([]string, 0, len(day.Rates)+1)
+		for _, r := range day.Rates {
+			rate, err := strconv.ParseFloat(r.RateStr, 64)
+			if err != nil {
+				return nil, &ParseError{Date: date, Currency: r.Currency, Field: "rate", Raw: r.RateStr, Err: err}
+			}
+			if rate == 0 {
+				return nil, &ParseError{Date: date, Currency: r.Currency, Field: "rate", Raw: r.RateStr, Err: errors.New("rate is zero")}
+			}
+			perEuro[r.Currency] = rate
+			codes = append(codes, r.Currency)
+		}
+		codes = append(codes, euro)
+
+		baseRate, ok := perEuro[base]
+		if !ok {
+			return nil, fmt.Errorf("base currency %s is not quoted on %s", base, day.Time)
+		}
+
+		for _, code := range codes {
+			if code == base {
+				continue
+			}
+			result = append(result, model.CurrencyRate{
+				CharCode: code,
+				Name:     code,
+				Rate:     baseRate / perEuro[code],
+				Date:     date,
+			})
+		}
+	}
+
+	return result, nil
+}
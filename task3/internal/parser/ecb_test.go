@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func TestParseECBRates_DailyEURBase(t *testing.T) {
+	rates, err := ParseECBRates(readFixture(t, "eurofxref-daily.xml"), "EUR")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 5 валют из фикстуры, сам EUR при базе EUR не выводится
+	if len(rates) != 5 {
+		t.Fatalf("Expected 5 rates, got %d", len(rates))
+	}
+
+	expectedDate := time.Date(2025, time.October, 17, 0, 0, 0, 0, time.UTC)
+	if rates[0].Name != "USD" || !rates[0].Date.Equal(expectedDate) {
+		t.Errorf("Unexpected first rate: %+v", rates[0])
+	}
+	// 1 USD = 1/1.1681 EUR
+	if math.Abs(rates[0].Rate-1/1.1681) > 1e-12 {
+		t.Errorf("Expected USD rate %.6f, got %.6f", 1/1.1681, rates[0].Rate)
+	}
+}
+
+func TestParseECBRates_HistUSDBase(t *testing.T) {
+	rates, err := ParseECBRates(readFixture(t, "eurofxref-hist.xml"), "usd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 3 дня × (JPY, GBP, EUR)
+	if len(rates) != 9 {
+		t.Fatalf("Expected 9 rates, got %d", len(rates))
+	}
+
+	byKey := make(map[string]float64)
+	for _, r := range rates {
+		if r.Name == "USD" {
+			t.Errorf("Base currency must not be reported: %+v", r)
+		}
+		byKey[r.Date.Format("2006-01-02")+r.Name] = r.Rate
+	}
+
+	// 1 EUR = 1.1685 USD на 16.10.2025
+	if got := byKey["2025-10-16EUR"]; math.Abs(got-1.1685) > 1e-12 {
+		t.Errorf("Expected EUR rate 1.1685, got %.6f", got)
+	}
+	// 1 GBP = 1.1631/0.8708 USD на 15.10.2025
+	if got, want := byKey["2025-10-15GBP"], 1.1631/0.8708; math.Abs(got-want) > 1e-12 {
+		t.Errorf("Expected GBP rate %.6f, got %.6f", want, got)
+	}
+}
+
+func TestParseECBRates_UnknownBase(t *testing.T) {
+	_, err := ParseECBRates(readFixture(t, "eurofxref-daily.xml"), "RUB")
+	if err == nil {
+		t.Fatal("Expected error for unknown base currency, got nil")
+	}
+}
+
+func TestParseECBRates_InvalidRate(t *testing.T) {
+	xmlData := []byte(`<Envelope><Cube><Cube time="2025-10-17"><Cube currency="USD" rate="abc"/></Cube></Cube></Envelope>`)
+
+	_, err := ParseECBRates(xmlData, "EUR")
+	if err == nil {
+		t.Fatal("Expected rate parsing error, got nil")
+	}
+}
+
+func TestParseECBRates_InvalidDate(t *testing.T) {
+	xmlData := []byte(`<Envelope><Cube><Cube time="17.10.2025"><Cube currency="USD" rate="1.1"/></Cube></Cube></Envelope>`)
+
+	_, err := ParseECBRates(xmlData, "EUR")
+	if err == nil {
+		t.Fatal("Expected date parsing error, got nil")
+	}
+}
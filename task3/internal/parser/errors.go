@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrZeroNominal = errors.New("nominal is zero")
+
+// ErrNoRoot — документ кончился раньше корневого элемента, например ответ
+// пустой.
+var ErrNoRoot = errors.New("root element not found")
+
+// ParseError описывает поле документа, которое не удалось разобрать.
+// Для ошибок разметки Field = "XML", а Date и Raw пусты.
+type ParseError struct {
+	Date     time.Time
+	Currency string
+	Field    string
+	Raw      string
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("invalid %s", e.Field)
+	if e.Raw != "" {
+		msg += fmt.Sprintf(" %q", e.Raw)
+	}
+	if e.Currency != "" {
+		msg += " for currency " + e.Currency
+	}
+	if !e.Date.IsZero() {
+		msg += " on " + e.Date.Format("2006-01-02")
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func xmlError(err error) error {
+	return &ParseError{Field: "XML", Err: err}
+}
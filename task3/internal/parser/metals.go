@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"task3/internal/model"
+
+	"golang.org/x/net/html/charset"
+)
+
+type Metall struct {
+	Records []MetallRecord `xml:"Record"`
+}
+
+type MetallRecord struct {
+	Date    string `xml:"Date,attr"`
+	Code    int    `xml:"Code,attr"`
+	BuyStr  string `xml:"Buy"`
+	SellStr string `xml:"Sell"`
+}
+
+func ParseMetals(xmlData []byte) ([]model.MetalQuote, error) {
+	var metall Metall
+	decoder := xml.NewDecoder(bytes.NewReader(xmlData))
+	decoder.CharsetReader = charset.NewReaderLabel
+	if err := decoder.Decode(&metall); err != nil {
+		return nil, xmlError(err)
+	}
+
+	var result []model.MetalQuote
+	for _, record := range metall.Records {
+		date, err := time.Parse("02.01.2006", record.Date)
+		if err != nil {
+			return nil, &ParseError{Field: "Date", Raw: record.Date, Err: err}
+		}
+		name := model.MetalName(record.Code)
+		if name == "" {
+			return nil, &ParseError{Date: date, Field: "Code", Raw: strconv.Itoa(record.Code), Err: errors.New("unknown metal code")}
+		}
+		buy, err := strconv.ParseFloat(strings.Replace(record.BuyStr, ",", ".", -1), 64)
+		if err != nil {
+			return nil, &ParseError{Date: date, Currency: name, Field: "Buy", Raw: record.BuyStr, Err: err}
+		}
+		sell, err := strconv.ParseFloat(strings.Replace(record.SellStr, ",", ".", -1), 64)
+		if err != nil {
+			return nil, &ParseError{Date: date, Currency: name, Field: "Sell", Raw: record.SellStr, Err: err}
+		}
+		result = append(result, model.MetalQuote{
+			Code: record.Code,
+			Name: name,
+			Buy:  buy,
+			Sell: sell,
+			Date: date,
+		})
+	}
+
+	return result, nil
+}
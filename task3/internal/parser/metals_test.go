@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func TestParseMetals_Success(t *testing.T) {
+	quotes, err := ParseMetals(readFixture(t, "xml_metall.xml"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(quotes) != 8 {
+		t.Fatalf("Expected 8 quotes, got %d", len(quotes))
+	}
+
+	expected := model.MetalQuote{
+		Code: model.MetalGold,
+		Name: "Gold",
+		Buy:  10535.97,
+		Sell: 10535.97,
+		Date: time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if quotes[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, quotes[0])
+	}
+	if quotes[7].Name != "Palladium" || quotes[7].Buy != 3575.40 {
+		t.Errorf("Unexpected last quote: %+v", quotes[7])
+	}
+}
+
+func TestParseMetals_Empty(t *testing.T) {
+	quotes, err := ParseMetals([]byte(`<Metall FromDate="20251004" ToDate="20251005"></Metall>`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(quotes) != 0 {
+		t.Errorf("Expected 0 quotes, got %d", len(quotes))
+	}
+}
+
+func TestParseMetals_UnknownCode(t *testing.T) {
+	xmlData := []byte(`<Metall><Record Date="01.10.2025" Code="7"><Buy>1,00</Buy><Sell>1,00</Sell></Record></Metall>`)
+
+	_, err := ParseMetals(xmlData)
+	if err == nil {
+		t.Fatal("Expected error for unknown metal code, got nil")
+	}
+}
+
+func TestParseMetals_InvalidValue(t *testing.T) {
+	xmlData := []byte(`<Metall><Record Date="01.10.2025" Code="1"><Buy>n/a</Buy><Sell>1,00</Sell></Record></Metall>`)
+
+	_, err := ParseMetals(xmlData)
+	if err == nil {
+		t.Fatal("Expected value parsing error, got nil")
+	}
+}
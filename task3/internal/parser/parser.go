@@ -3,7 +3,6 @@ package parser
 import (
 	"bytes"
 	"encoding/xml"
-	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -19,42 +18,110 @@ type ValCurs struct {
 }
 
 type Valute struct {
+	ID       string `xml:"ID,attr"`
+	CharCode string `xml:"CharCode"`
 	Name     string `xml:"Name"`
-	Nominal  int    `xml:"Nominal"`
-	ValueStr string `xml:"Value"`
+	// Nominal разбирается вручную, чтобы нестрогий режим пропускал только
+	// сломанную запись, а не весь документ.
+	NominalStr string `xml:"Nominal"`
+	ValueStr   string `xml:"Value"`
 }
 
 func ParseRates(xmlData []byte) ([]model.CurrencyRate, error) {
+	rates, _, err := parseRates(xmlData, false)
+	return rates, err
+}
+
+// ParseRatesLenient пропускает записи Valute с некорректным Value или нулевым
+// Nominal и возвращает их в диагностике вместо ошибки на весь день.
+func ParseRatesLenient(xmlData []byte) ([]model.CurrencyRate, []model.Diagnostic, error) {
+	return parseRates(xmlData, true)
+}
+
+func parseRates(xmlData []byte, lenient bool) ([]model.CurrencyRate, []model.Diagnostic, error) {
 	var vals ValCurs
 	reader := bytes.NewReader(xmlData)
 	decoder := xml.NewDecoder(reader)
 	decoder.CharsetReader = charset.NewReaderLabel
 	err := decoder.Decode(&vals)
 	if err != nil {
-		return nil, err
+		return nil, nil, xmlError(err)
 	}
 
 	var result []model.CurrencyRate
+	var diagnostics []model.Diagnostic
 	date, err := time.Parse("02.01.2006", vals.Date)
 	if err != nil {
-		return nil, err
+		return nil, nil, &ParseError{Field: "Date", Raw: vals.Date, Err: err}
 	}
-	for _, valute := range vals.Valutes {
-		valuteStrFloat64 := strings.Replace(valute.ValueStr, ",", ".", -1)
-		if valute.Nominal == 0 {
-			return nil, fmt.Errorf("nominal is zero for currency %s", valute.Name)
-		}
-		valuteFloat64, err := strconv.ParseFloat(valuteStrFloat64, 64)
+	for i, valute := range vals.Valutes {
+		rate, diag, err := rateFromValute(date, i, valute)
 		if err != nil {
-			return nil, err
+			if !lenient {
+				return nil, nil, err
+			}
+			diagnostics = append(diagnostics, diag)
+			continue
 		}
-		valuteRate := valuteFloat64 / float64(valute.Nominal)
-		result = append(result, model.CurrencyRate{
-			Name: valute.Name,
-			Rate: valuteRate,
-			Date: date,
-		})
+		result = append(result, rate)
 	}
 
-	return result, nil
+	return result, diagnostics, nil
+}
+
+// rateFromValute пересчитывает запись в курс за единицу валюты. При ошибке
+// возвращает и диагностику для нестрогого режима, и ошибку для строгого.
+func rateFromValute(date time.Time, index int, valute Valute) (model.CurrencyRate, model.Diagnostic, error) {
+	valuteStrFloat64 := strings.Replace(valute.ValueStr, ",", ".", -1)
+	nominal, err := parseNominal(valute.NominalStr)
+	if err != nil {
+		return model.CurrencyRate{}, diagnostic(date, index, valute, "Nominal", valute.NominalStr, "invalid number"),
+			valuteError(date, valute, "Nominal", valute.NominalStr, err)
+	}
+	if nominal == 0 {
+		return model.CurrencyRate{}, diagnostic(date, index, valute, "Nominal", valute.NominalStr, "nominal is zero"),
+			valuteError(date, valute, "Nominal", valute.NominalStr, ErrZeroNominal)
+	}
+	valuteFloat64, err := strconv.ParseFloat(valuteStrFloat64, 64)
+	if err != nil {
+		return model.CurrencyRate{}, diagnostic(date, index, valute, "Value", valute.ValueStr, "invalid number"),
+			valuteError(date, valute, "Value", valute.ValueStr, err)
+	}
+	return model.CurrencyRate{
+		ID:       valute.ID,
+		CharCode: valute.CharCode,
+		Name:     valute.Name,
+		Rate:     valuteFloat64 / float64(nominal),
+		Date:     date,
+	}, model.Diagnostic{}, nil
+}
+
+func parseNominal(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func valuteCurrency(valute Valute) string {
+	if valute.CharCode != "" {
+		return valute.CharCode
+	}
+	return valute.Name
+}
+
+func valuteError(date time.Time, valute Valute, field, raw string, err error) error {
+	return &ParseError{Date: date, Currency: valuteCurrency(valute), Field: field, Raw: raw, Err: err}
+}
+
+func diagnostic(date time.Time, index int, valute Valute, field, raw, reason string) model.Diagnostic {
+	return model.Diagnostic{
+		Date:     date,
+		Index:    index,
+		Currency: valuteCurrency(valute),
+		Field:    field,
+		Raw:      raw,
+		Reason:   reason,
+	}
 }
@@ -1,8 +1,11 @@
 package parser
 
 import (
+	"errors"
 	"testing"
 	"time"
+
+	"task3/internal/model"
 )
 
 func TestParseRates_Success(t *testing.T) {
@@ -36,6 +39,9 @@ func TestParseRates_Success(t *testing.T) {
 	expectedDate := time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)
 
 	// Проверяем USD
+	if rates[0].ID != "R01235" || rates[0].CharCode != "USD" {
+		t.Errorf("Expected ID R01235 and CharCode USD, got %q %q", rates[0].ID, rates[0].CharCode)
+	}
 	if rates[0].Name != "US Dollar" {
 		t.Errorf("Expected name 'US Dollar', got %q", rates[0].Name)
 	}
@@ -77,6 +83,10 @@ func TestParseRates_InvalidXML(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected XML parsing error, got nil")
 	}
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Field != "XML" {
+		t.Errorf("Expected *ParseError for XML, got %v", err)
+	}
 }
 
 func TestParseRates_InvalidDate(t *testing.T) {
@@ -95,6 +105,10 @@ func TestParseRates_InvalidValueFormat(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected value parsing error, got nil")
 	}
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Field != "Value" || parseErr.Raw != "not_a_number" {
+		t.Errorf("Expected *ParseError for Value, got %v", err)
+	}
 }
 
 func TestParseRates_ZeroNominal(t *testing.T) {
@@ -104,8 +118,18 @@ func TestParseRates_ZeroNominal(t *testing.T) {
 	if err == nil {
 		t.Fatal("Expected error for zero nominal, got nil")
 	}
-	if err.Error() != "nominal is zero for currency Test" {
-		t.Errorf("Unexpected error message: %v", err)
+	if !errors.Is(err, ErrZeroNominal) {
+		t.Errorf("Expected ErrZeroNominal, got %v", err)
+	}
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Expected *ParseError, got %T", err)
+	}
+	if parseErr.Currency != "Test" || parseErr.Field != "Nominal" || parseErr.Raw != "0" {
+		t.Errorf("Unexpected parse error: %+v", parseErr)
+	}
+	if !parseErr.Date.Equal(time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected parse error date: %v", parseErr.Date)
 	}
 }
 
@@ -139,3 +163,57 @@ func TestParseRates_MissingOptionalFields(t *testing.T) {
 		t.Errorf("Unexpected result: %+v", rates)
 	}
 }
+
+func TestParseRates_InvalidNominal(t *testing.T) {
+	xmlData := []byte(`<ValCurs Date="22.10.2025"><Valute><Nominal>ten</Nominal><Name>Test</Name><Value>75,50</Value></Valute></ValCurs>`)
+
+	_, err := ParseRates(xmlData)
+	if err == nil {
+		t.Fatal("Expected nominal parsing error, got nil")
+	}
+}
+
+func TestParseRatesLenient_SkipsInvalidValutes(t *testing.T) {
+	xmlData := []byte(`<ValCurs Date="22.10.2025">
+	<Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>80,00</Value></Valute>
+	<Valute ID="R01239"><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Euro</Name><Value>-</Value></Valute>
+	<Valute ID="R01375"><CharCode>CNY</CharCode><Nominal>0</Nominal><Name>Yuan</Name><Value>11,00</Value></Valute>
+	<Valute ID="R01820"><Nominal>x</Nominal><Name>Yen</Name><Value>52,00</Value></Valute>
+	<Valute ID="R01035"><CharCode>GBP</CharCode><Nominal>1</Nominal><Name>Pound</Name><Value>105,00</Value></Valute>
+</ValCurs>`)
+
+	rates, diagnostics, err := ParseRatesLenient(xmlData)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rates) != 2 || rates[0].CharCode != "USD" || rates[1].CharCode != "GBP" {
+		t.Fatalf("Expected USD and GBP, got %+v", rates)
+	}
+
+	date := time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)
+	expected := []model.Diagnostic{
+		{Date: date, Index: 1, Currency: "EUR", Field: "Value", Raw: "-", Reason: "invalid number"},
+		{Date: date, Index: 2, Currency: "CNY", Field: "Nominal", Raw: "0", Reason: "nominal is zero"},
+		// Без CharCode в диагностике остаётся название
+		{Date: date, Index: 3, Currency: "Yen", Field: "Nominal", Raw: "x", Reason: "invalid number"},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %+v", len(expected), diagnostics)
+	}
+	for i := range expected {
+		if diagnostics[i] != expected[i] {
+			t.Errorf("Diagnostic %d: expected %+v, got %+v", i, expected[i], diagnostics[i])
+		}
+	}
+}
+
+func TestParseRatesLenient_BrokenDocument(t *testing.T) {
+	// Нестрогий режим не спасает документ без даты или с битой разметкой
+	if _, _, err := ParseRatesLenient([]byte(`<ValCurs Date="bad"></ValCurs>`)); err == nil {
+		t.Error("Expected date parsing error, got nil")
+	}
+	if _, _, err := ParseRatesLenient([]byte(`<ValCurs><Valute>`)); err == nil {
+		t.Error("Expected XML parsing error, got nil")
+	}
+}
@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"task3/internal/model"
+
+	"golang.org/x/net/html/charset"
+)
+
+// DynamicRecord — запись XML_dynamic.asp: курс одной валюты на одну дату.
+type DynamicRecord struct {
+	Date       string `xml:"Date,attr"`
+	ID         string `xml:"Id,attr"`
+	NominalStr string `xml:"Nominal"`
+	ValueStr   string `xml:"Value"`
+}
+
+// StreamRates разбирает суточный ValCurs из r по токенам и передаёт курсы в fn
+// по мере чтения: в памяти держится только текущая запись Valute. Проверки —
+// как у ParseRates; ошибка fn прерывает разбор и возвращается как есть.
+func StreamRates(r io.Reader, fn func(model.CurrencyRate) error) error {
+	var date time.Time
+	var haveDate bool
+	index := 0
+
+	return walk(r, func(decoder *xml.Decoder, el xml.StartElement) error {
+		switch el.Name.Local {
+		case "ValCurs":
+			d, err := time.Parse("02.01.2006", attr(el, "Date"))
+			if err != nil {
+				return &ParseError{Field: "Date", Raw: attr(el, "Date"), Err: err}
+			}
+			date, haveDate = d, true
+		case "Valute":
+			if !haveDate {
+				return xmlError(errors.New("valute element outside of ValCurs"))
+			}
+			var valute Valute
+			if err := decoder.DecodeElement(&valute, &el); err != nil {
+				return xmlError(err)
+			}
+			rate, _, err := rateFromValute(date, index, valute)
+			if err != nil {
+				return err
+			}
+			index++
+			return fn(rate)
+		}
+		return nil
+	}, "ValCurs")
+}
+
+// StreamDynamic разбирает ответ XML_dynamic.asp (курсы одной валюты за период)
+// по токенам. Название валюты в этом формате не передаётся — только ID.
+func StreamDynamic(r io.Reader, fn func(model.CurrencyRate) error) error {
+	return walk(r, func(decoder *xml.Decoder, el xml.StartElement) error {
+		if el.Name.Local != "Record" {
+			return nil
+		}
+		var record DynamicRecord
+		if err := decoder.DecodeElement(&record, &el); err != nil {
+			return xmlError(err)
+		}
+		date, err := time.Parse("02.01.2006", record.Date)
+		if err != nil {
+			return &ParseError{Currency: record.ID, Field: "Date", Raw: record.Date, Err: err}
+		}
+		nominal, err := parseNominal(record.NominalStr)
+		if err != nil {
+			return &ParseError{Date: date, Currency: record.ID, Field: "Nominal", Raw: record.NominalStr, Err: err}
+		}
+		if nominal == 0 {
+			return &ParseError{Date: date, Currency: record.ID, Field: "Nominal", Raw: record.NominalStr, Err: ErrZeroNominal}
+		}
+		value, err := strconv.ParseFloat(strings.Replace(record.ValueStr, ",", ".", -1), 64)
+		if err != nil {
+			return &ParseError{Date: date, Currency: record.ID, Field: "Value", Raw: record.ValueStr, Err: err}
+		}
+		return fn(model.CurrencyRate{
+			ID:   record.ID,
+			Rate: value / float64(nominal),
+			Date: date,
+		})
+	}, "ValCurs")
+}
+
+// walk вызывает fn для каждого открывающего тега документа. Если fn прочитала
+// элемент целиком (DecodeElement), его вложенные теги в walk не попадают.
+func walk(r io.Reader, fn func(*xml.Decoder, xml.StartElement) error, root string) error {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	seenRoot := false
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			if !seenRoot {
+				return xmlError(fmt.Errorf("%s: %w", root, ErrNoRoot))
+			}
+			return nil
+		}
+		if err != nil {
+			return xmlError(err)
+		}
+
+		el, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if el.Name.Local == root {
+			seenRoot = true
+		}
+		if err := fn(decoder, el); err != nil {
+			return err
+		}
+	}
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+
+	"golang.org/x/net/html/charset"
+)
+
+const dailyXML = `<?xml version="1.0" encoding="windows-1251"?>
+<ValCurs Date="22.10.2025" name="Foreign Currency Market">
+	<Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>75,50</Value></Valute>
+	<Valute ID="R01239"><NumCode>978</NumCode><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Euro</Name><Value>82,30</Value></Valute>
+	<Valute ID="R01820"><NumCode>392</NumCode><CharCode>JPY</CharCode><Nominal>100</Nominal><Name>Japanese Yen</Name><Value>52,10</Value></Valute>
+</ValCurs>`
+
+func collect(t *testing.T, data string) ([]model.CurrencyRate, error) {
+	t.Helper()
+	var rates []model.CurrencyRate
+	err := StreamRates(strings.NewReader(data), func(r model.CurrencyRate) error {
+		rates = append(rates, r)
+		return nil
+	})
+	return rates, err
+}
+
+func TestStreamRates_MatchesParseRates(t *testing.T) {
+	expected, err := ParseRates([]byte(dailyXML))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rates, err := collect(t, dailyXML)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rates) != len(expected) {
+		t.Fatalf("Expected %d rates, got %d", len(expected), len(rates))
+	}
+	for i := range expected {
+		if rates[i] != expected[i] {
+			t.Errorf("Rate %d: expected %+v, got %+v", i, expected[i], rates[i])
+		}
+	}
+}
+
+func TestStreamRates_Errors(t *testing.T) {
+	tests := map[string]string{
+		"empty":         ``,
+		"not xml":       `invalid xml`,
+		"broken markup": `<ValCurs Date="22.10.2025"><Valute><Value>75,50</Valu></ValCurs>`,
+		"invalid date":  `<ValCurs Date="2025-10-22"></ValCurs>`,
+		"invalid value": `<ValCurs Date="22.10.2025"><Valute><Nominal>1</Nominal><Name>Test</Name><Value>abc</Value></Valute></ValCurs>`,
+		"zero nominal":  `<ValCurs Date="22.10.2025"><Valute><Nominal>0</Nominal><Name>Test</Name><Value>1,00</Value></Valute></ValCurs>`,
+	}
+	for name, data := range tests {
+		if _, err := collect(t, data); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestStreamRates_EmptyDocument(t *testing.T) {
+	for _, data := range []string{``, `<?xml version="1.0" encoding="windows-1251"?>`} {
+		if _, err := collect(t, data); !errors.Is(err, ErrNoRoot) {
+			t.Errorf("%q: expected ErrNoRoot, got %v", data, err)
+		}
+	}
+	if _, err := collect(t, `<ValCurs Date="22.10.2025"><Valute><Value>75,50</Valu></ValCurs>`); errors.Is(err, ErrNoRoot) {
+		t.Errorf("Broken document must not be reported as empty: %v", err)
+	}
+}
+
+func TestStreamRates_CallbackError(t *testing.T) {
+	stop := fmt.Errorf("enough")
+	calls := 0
+	err := StreamRates(strings.NewReader(dailyXML), func(model.CurrencyRate) error {
+		calls++
+		return stop
+	})
+	if err != stop {
+		t.Fatalf("Expected callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected parsing to stop after first rate, got %d calls", calls)
+	}
+}
+
+func TestStreamDynamic(t *testing.T) {
+	var rates []model.CurrencyRate
+	err := StreamDynamic(bytes.NewReader(readFixture(t, "XML_dynamic.xml")), func(r model.CurrencyRate) error {
+		rates = append(rates, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(rates) != 3 {
+		t.Fatalf("Expected 3 rates, got %d", len(rates))
+	}
+	expected := model.CurrencyRate{ID: "R01235", Rate: 81.7504, Date: time.Date(2025, time.October, 2, 0, 0, 0, 0, time.UTC)}
+	if rates[1] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, rates[1])
+	}
+}
+
+func TestStreamDynamic_ZeroNominal(t *testing.T) {
+	data := `<ValCurs ID="R01235"><Record Date="01.10.2025" Id="R01235"><Nominal>0</Nominal><Value>1,0</Value></Record></ValCurs>`
+	err := StreamDynamic(strings.NewReader(data), func(model.CurrencyRate) error { return nil })
+	if err == nil {
+		t.Fatal("Expected error for zero nominal, got nil")
+	}
+}
+
+// benchmarkDaily — суточный документ с 43 валютами, как у ЦБ.
+func benchmarkDaily() []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="windows-1251"?><ValCurs Date="22.10.2025" name="Foreign Currency Market">`)
+	for i := 0; i < 43; i++ {
+		fmt.Fprintf(&b, `<Valute ID="R%05d"><NumCode>%03d</NumCode><CharCode>C%02d</CharCode><Nominal>1</Nominal><Name>Currency %d</Name><Value>%d,%04d</Value><VunitRate>%d,%04d</VunitRate></Valute>`, i, i, i, i, 10+i, i, 10+i, i)
+	}
+	b.WriteString(`</ValCurs>`)
+	return b.Bytes()
+}
+
+// benchmarkDynamic — динамика одной валюты примерно за 30 лет.
+func benchmarkDynamic() []byte {
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="windows-1251"?><ValCurs ID="R01235" DateRange1="01.07.1992" DateRange2="22.10.2025" name="Foreign Currency Market Dynamic">`)
+	day := time.Date(1995, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7500; i++ {
+		fmt.Fprintf(&b, `<Record Date="%s" Id="R01235"><Nominal>1</Nominal><Value>%d,%04d</Value><VunitRate>%d,%04d</VunitRate></Record>`, day.AddDate(0, 0, i).Format("02.01.2006"), 30+i%60, i%10000, 30+i%60, i%10000)
+	}
+	b.WriteString(`</ValCurs>`)
+	return b.Bytes()
+}
+
+func BenchmarkParseRates(b *testing.B) {
+	data := benchmarkDaily()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		if _, err := ParseRates(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamRates(b *testing.B) {
+	data := benchmarkDaily()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		err := StreamRates(bytes.NewReader(data), func(model.CurrencyRate) error { return nil })
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamDynamic(b *testing.B) {
+	data := benchmarkDynamic()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		err := StreamDynamic(bytes.NewReader(data), func(model.CurrencyRate) error { return nil })
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecodeDynamic — буферизованный разбор динамики целиком в структуру,
+// для сравнения с BenchmarkStreamDynamic.
+func BenchmarkDecodeDynamic(b *testing.B) {
+	data := benchmarkDynamic()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	for b.Loop() {
+		var doc struct {
+			Records []DynamicRecord `xml:"Record"`
+		}
+		decoder := xml.NewDecoder(bytes.NewReader(data))
+		decoder.CharsetReader = charset.NewReaderLabel
+		if err := decoder.Decode(&doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package portfolio
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateFormat = "2006-01-02"
+
+// Holding — остаток в одной валюте. Currency — буквенный код (USD), код ЦБ
+// (R01235) или RUB. Acquired — дата покупки; нулевая — остаток был до
+// начала периода, и переоценка считается от первой даты оценки.
+type Holding struct {
+	Currency string
+	Amount   float64
+	Acquired time.Time
+}
+
+// Load читает остатки из файла .csv или .json.
+func Load(path string) ([]Holding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var holdings []Holding
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		holdings, err = ParseCSV(f)
+	case ".json":
+		holdings, err = ParseJSON(f)
+	default:
+		return nil, fmt.Errorf("unknown holdings format %q: use .csv or .json", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return holdings, nil
+}
+
+// ParseCSV читает таблицу с заголовком currency,amount[,acquired]; порядок
+// столбцов любой, пустая дата — остаток до начала периода.
+func ParseCSV(r io.Reader) ([]Holding, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("no holdings")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{"currency": -1, "amount": -1, "acquired": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	if columns["currency"] < 0 || columns["amount"] < 0 {
+		return nil, fmt.Errorf("header must name currency and amount columns, got %q", strings.Join(header, ","))
+	}
+	field := func(record []string, name string) string {
+		if i := columns[name]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var holdings []Holding
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		amount, err := strconv.ParseFloat(field(record, "amount"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount %q", line, field(record, "amount"))
+		}
+		h, err := newHolding(field(record, "currency"), amount, field(record, "acquired"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		holdings = append(holdings, h)
+	}
+	if len(holdings) == 0 {
+		return nil, fmt.Errorf("no holdings")
+	}
+	return holdings, nil
+}
+
+type jsonHolding struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+	Acquired string  `json:"acquired"`
+}
+
+// ParseJSON читает массив [{"currency": "USD", "amount": 1000,
+// "acquired": "2025-01-15"}]; acquired необязателен.
+func ParseJSON(r io.Reader) ([]Holding, error) {
+	var items []jsonHolding
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no holdings")
+	}
+	holdings := make([]Holding, 0, len(items))
+	for i, item := range items {
+		h, err := newHolding(item.Currency, item.Amount, item.Acquired)
+		if err != nil {
+			return nil, fmt.Errorf("holding %d: %w", i+1, err)
+		}
+		holdings = append(holdings, h)
+	}
+	return holdings, nil
+}
+
+func newHolding(currency string, amount float64, acquired string) (Holding, error) {
+	currency = strings.TrimSpace(currency)
+	if currency == "" {
+		return Holding{}, fmt.Errorf("currency is empty")
+	}
+	if amount <= 0 {
+		return Holding{}, fmt.Errorf("amount of %s must be positive, got %v", currency, amount)
+	}
+	h := Holding{Currency: currency, Amount: amount}
+	if acquired = strings.TrimSpace(acquired); acquired != "" {
+		date, err := time.Parse(dateFormat, acquired)
+		if err != nil {
+			return Holding{}, fmt.Errorf("invalid acquisition date of %s: %w", currency, err)
+		}
+		h.Acquired = date
+	}
+	return h, nil
+}
+
+// Codes — валюты остатков без повторов в порядке появления, кроме RUB.
+func Codes(holdings []Holding) []string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, h := range holdings {
+		code := strings.ToUpper(h.Currency)
+		if code == RUB || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, h.Currency)
+	}
+	return codes
+}
+
+// Earliest — самая ранняя дата покупки; нулевая, если дат нет.
+func Earliest(holdings []Holding) time.Time {
+	var earliest time.Time
+	for _, h := range holdings {
+		if !h.Acquired.IsZero() && (earliest.IsZero() || h.Acquired.Before(earliest)) {
+			earliest = h.Acquired
+		}
+	}
+	return earliest
+}
@@ -0,0 +1,252 @@
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+// RUB — рубль: курсы ЦБ даны к нему, поэтому его курс всегда 1.
+const RUB = "RUB"
+
+// Position — остатки в одной валюте, оценённые в базовой валюте.
+type Position struct {
+	Code   string
+	Name   string
+	Amount float64
+	// Value — стоимость на последнюю дату оценки.
+	Value float64
+	// Cost — стоимость по курсам дат покупки, для остатков без даты — по
+	// курсу первой даты оценки.
+	Cost float64
+	// Gain — нереализованная курсовая разница: Value - Cost.
+	Gain float64
+	// Share — доля в стоимости портфеля на последнюю дату.
+	Share float64
+	// Points — стоимость позиции на каждую дату оценки; до покупки 0.
+	Points []model.Point
+}
+
+// Valuation — стоимость портфеля в базовой валюте по датам.
+type Valuation struct {
+	Base      string
+	Points    []model.Point
+	Positions []Position
+	Cost      float64
+	Gain      float64
+	// Drawdown — максимальная просадка. Глубина считается по доходности
+	// без учёта покупок, Peak и Trough содержат стоимость портфеля.
+	Drawdown stats.Drawdown
+}
+
+// Value — стоимость портфеля на последнюю дату оценки.
+func (v Valuation) Value() float64 {
+	if len(v.Points) == 0 {
+		return 0
+	}
+	return v.Points[len(v.Points)-1].Value
+}
+
+// series — курсы одной валюты к рублю по возрастанию дат; без курсов — рубль.
+type series struct {
+	code  string
+	name  string
+	rates []model.CurrencyRate
+}
+
+// at — последний курс на дату или раньше.
+func (s series) at(date time.Time) (float64, bool) {
+	if s.rates == nil {
+		return 1, true
+	}
+	i := sort.Search(len(s.rates), func(i int) bool { return s.rates[i].Date.After(date) })
+	if i == 0 {
+		return 0, false
+	}
+	return s.rates[i-1].Rate, true
+}
+
+// cost — курс на дату покупки; если она раньше загруженных курсов —
+// первый известный.
+func (s series) cost(date time.Time) float64 {
+	if rate, ok := s.at(date); ok {
+		return rate
+	}
+	return s.rates[0].Rate
+}
+
+// Value оценивает остатки в валюте base по курсам currencies на каждую дату
+// с from, на которую известны курсы всех купленных к ней валют и базовой
+// валюты; пропущенные дни берут последний известный курс. Курсы до from
+// нужны только для стоимости покупки.
+func Value(holdings []Holding, currencies []stats.Currency, base string, from time.Time) (Valuation, error) {
+	if len(holdings) == 0 {
+		return Valuation{}, fmt.Errorf("no holdings")
+	}
+	baseSeries, err := find(currencies, base)
+	if err != nil {
+		return Valuation{}, fmt.Errorf("base currency: %w", err)
+	}
+	held := make([]series, len(holdings))
+	for i, h := range holdings {
+		if held[i], err = find(currencies, h.Currency); err != nil {
+			return Valuation{}, err
+		}
+	}
+
+	dates := valuationDates(append([]series{baseSeries}, held...), from)
+	rates := make([]float64, len(holdings))
+	var valued []time.Time
+	var values [][]float64
+	for _, date := range dates {
+		baseRate, ok := baseSeries.at(date)
+		if !ok {
+			continue
+		}
+		known := true
+		for i, h := range holdings {
+			rate, ok := held[i].at(date)
+			if !ok && active(h, date) {
+				known = false
+				break
+			}
+			rates[i] = rate / baseRate
+		}
+		if !known {
+			continue
+		}
+		row := make([]float64, len(holdings))
+		for i, h := range holdings {
+			if active(h, date) {
+				row[i] = h.Amount * rates[i]
+			}
+		}
+		valued = append(valued, date)
+		values = append(values, row)
+	}
+	if len(valued) == 0 {
+		return Valuation{}, fmt.Errorf("no common rates for holdings and %s", base)
+	}
+	last := valued[len(valued)-1]
+
+	v := Valuation{Base: strings.ToUpper(base)}
+	positions := make(map[string]int)
+	for i, h := range holdings {
+		if h.Acquired.After(last) {
+			return Valuation{}, fmt.Errorf("%s acquired on %s, after the last rate %s", h.Currency, h.Acquired.Format(dateFormat), last.Format(dateFormat))
+		}
+		var cost float64
+		if h.Acquired.IsZero() {
+			cost = values[0][i]
+		} else {
+			cost = h.Amount * held[i].cost(h.Acquired) / baseSeries.cost(h.Acquired)
+		}
+
+		j, ok := positions[held[i].code]
+		if !ok {
+			j = len(v.Positions)
+			positions[held[i].code] = j
+			v.Positions = append(v.Positions, Position{Code: held[i].code, Name: held[i].name, Points: make([]model.Point, len(valued))})
+		}
+		p := &v.Positions[j]
+		p.Amount += h.Amount
+		p.Value += values[len(values)-1][i]
+		p.Cost += cost
+		for k, date := range valued {
+			p.Points[k].Date = date
+			p.Points[k].Value += values[k][i]
+		}
+	}
+
+	for k, date := range valued {
+		var total float64
+		for _, value := range values[k] {
+			total += value
+		}
+		v.Points = append(v.Points, model.Point{Date: date, Value: total})
+	}
+	for i := range v.Positions {
+		p := &v.Positions[i]
+		p.Gain = p.Value - p.Cost
+		if total := v.Value(); total != 0 {
+			p.Share = p.Value / total
+		}
+		v.Cost += p.Cost
+		v.Gain += p.Gain
+	}
+	sort.SliceStable(v.Positions, func(i, j int) bool { return v.Positions[i].Code < v.Positions[j].Code })
+	v.Drawdown = drawdown(holdings, valued, values, v.Points)
+	return v, nil
+}
+
+// drawdown считает просадку по индексу доходности: изменение за день — это
+// стоимость на этот день остатков, купленных раньше, к стоимости портфеля
+// накануне, поэтому покупки не выглядят ростом.
+func drawdown(holdings []Holding, dates []time.Time, values [][]float64, totals []model.Point) stats.Drawdown {
+	index := []model.Point{{Date: dates[0], Value: 1}}
+	for k := 1; k < len(dates); k++ {
+		var held float64
+		for i, h := range holdings {
+			if active(h, dates[k-1]) {
+				held += values[k][i]
+			}
+		}
+		value := index[k-1].Value
+		if prev := totals[k-1].Value; prev != 0 {
+			value *= held / prev
+		}
+		index = append(index, model.Point{Date: dates[k], Value: value})
+	}
+
+	d := stats.MaxDrawdown(index)
+	if d.Depth == 0 {
+		return stats.Drawdown{}
+	}
+	for _, p := range totals {
+		switch {
+		case p.Date.Equal(d.Peak.Date):
+			d.Peak = p
+		case p.Date.Equal(d.Trough.Date):
+			d.Trough = p
+		}
+	}
+	return d
+}
+
+// active — остаток уже куплен к дате.
+func active(h Holding, date time.Time) bool {
+	return !h.Acquired.After(date)
+}
+
+// find ищет ряд валюты по буквенному коду или коду ЦБ.
+func find(currencies []stats.Currency, code string) (series, error) {
+	if strings.EqualFold(code, RUB) {
+		return series{code: RUB}, nil
+	}
+	for _, c := range currencies {
+		if strings.EqualFold(c.Code(), code) || strings.EqualFold(c.ID, code) {
+			return series{code: c.Code(), name: c.Name, rates: c.Points}, nil
+		}
+	}
+	return series{}, fmt.Errorf("no rates for %s", code)
+}
+
+// valuationDates — даты курсов всех рядов начиная с from, без повторов.
+func valuationDates(all []series, from time.Time) []time.Time {
+	seen := make(map[time.Time]bool)
+	var dates []time.Time
+	for _, s := range all {
+		for _, r := range s.rates {
+			if !r.Date.Before(from) && !seen[r.Date] {
+				seen[r.Date] = true
+				dates = append(dates, r.Date)
+			}
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
@@ -0,0 +1,168 @@
+package portfolio
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+func day(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+
+// testCurrencies — USD 80, 84, 78, 82 с 1 по 4 октября; у EUR нет курса за
+// 2 октября.
+func testCurrencies() []stats.Currency {
+	var rates []model.CurrencyRate
+	for i, r := range []float64{80, 84, 78, 82} {
+		rates = append(rates, model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: r, Date: day(i + 1)})
+	}
+	for _, d := range []int{1, 3, 4} {
+		rates = append(rates, model.CurrencyRate{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: 100, Date: day(d)})
+	}
+	return stats.ByCurrency(rates)
+}
+
+func TestValue(t *testing.T) {
+	holdings := []Holding{
+		{Currency: "usd", Amount: 100},
+		{Currency: "R01239", Amount: 10, Acquired: day(3)},
+		{Currency: "RUB", Amount: 1000},
+		{Currency: "USD", Amount: 50, Acquired: day(2)},
+	}
+	v, err := Value(holdings, testCurrencies(), "RUB", day(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Пропуск EUR за 2 октября не мешает: евро куплены только 3-го
+	want := []float64{100*80 + 1000, 150*84 + 1000, 150*78 + 1000 + 1000, 150*82 + 1000 + 1000}
+	if len(v.Points) != len(want) {
+		t.Fatalf("Expected %d points, got %+v", len(want), v.Points)
+	}
+	for i, p := range v.Points {
+		if !p.Date.Equal(day(i+1)) || math.Abs(p.Value-want[i]) > 1e-9 {
+			t.Errorf("Point %d: got %+v, want %v", i, p, want[i])
+		}
+	}
+
+	if len(v.Positions) != 3 || v.Positions[0].Code != "EUR" || v.Positions[1].Code != RUB || v.Positions[2].Code != "USD" {
+		t.Fatalf("Unexpected positions: %+v", v.Positions)
+	}
+	usd := v.Positions[2]
+	// Себестоимость: 100 по курсу первой даты оценки и 50 по курсу покупки
+	if usd.Amount != 150 || usd.Value != 150*82 || usd.Cost != 100*80+50*84 || usd.Gain != 150*82-(100*80+50*84) {
+		t.Errorf("Unexpected USD position: %+v", usd)
+	}
+	if usd.Points[0].Value != 8000 || usd.Points[1].Value != 150*84 {
+		t.Errorf("Unexpected USD points: %+v", usd.Points)
+	}
+	if math.Abs(usd.Share-12300.0/14300) > 1e-12 {
+		t.Errorf("Unexpected USD share: %v", usd.Share)
+	}
+	if v.Gain != usd.Gain || v.Cost != 1000+1000+100*80+50*84 {
+		t.Errorf("Unexpected totals: cost %v, gain %v", v.Cost, v.Gain)
+	}
+
+	// Просадка 2→3 октября только от курса доллара: покупка евро не в счёт
+	prev := 150*84 + 1000.0
+	depth := 1 - (150*78+1000)/prev
+	if math.Abs(v.Drawdown.Depth-depth) > 1e-12 || !v.Drawdown.Peak.Date.Equal(day(2)) || !v.Drawdown.Trough.Date.Equal(day(3)) {
+		t.Errorf("Unexpected drawdown: %+v, want depth %v", v.Drawdown, depth)
+	}
+	if v.Drawdown.Peak.Value != prev || v.Drawdown.Trough.Value != want[2] {
+		t.Errorf("Drawdown must carry portfolio values: %+v", v.Drawdown)
+	}
+}
+
+func TestValue_CrossRate(t *testing.T) {
+	holdings := []Holding{{Currency: "USD", Amount: 100}, {Currency: "RUB", Amount: 8000}}
+	v, err := Value(holdings, testCurrencies(), "eur", day(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Оценка с 3 октября: USD 78/100, рубль 1/100
+	if v.Base != "EUR" || len(v.Points) != 2 || v.Points[0].Value != 78+80 || v.Points[1].Value != 82+80 {
+		t.Errorf("Unexpected valuation: %+v", v.Points)
+	}
+}
+
+func TestValue_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		holdings []Holding
+		base     string
+		err      string
+	}{
+		{"unknown currency", []Holding{{Currency: "GBP", Amount: 1}}, "RUB", "no rates for GBP"},
+		{"unknown base", []Holding{{Currency: "USD", Amount: 1}}, "CNY", "base currency: no rates for CNY"},
+		{"future purchase", []Holding{{Currency: "USD", Amount: 1, Acquired: day(10)}}, "RUB", "after the last rate"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Value(tt.holdings, testCurrencies(), tt.base, day(1))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "amount,currency,acquired\n1000.5,USD,2025-01-15\n200,eur,\n"
+	holdings, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holdings) != 2 {
+		t.Fatalf("Expected 2 holdings, got %+v", holdings)
+	}
+	if h := holdings[0]; h.Currency != "USD" || h.Amount != 1000.5 || !h.Acquired.Equal(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected first holding: %+v", h)
+	}
+	if h := holdings[1]; h.Currency != "eur" || !h.Acquired.IsZero() {
+		t.Errorf("Unexpected second holding: %+v", h)
+	}
+
+	for input, want := range map[string]string{
+		"currency,value\nUSD,1\n":                      "header must name currency and amount",
+		"currency,amount\nUSD,abc\n":                   "line 2: invalid amount",
+		"currency,amount\nUSD,-5\n":                    "line 2: amount of USD must be positive",
+		"currency,amount,acquired\n":                   "no holdings",
+		"currency,amount,acquired\nUSD,1,15.01.2025\n": "invalid acquisition date",
+	} {
+		if _, err := ParseCSV(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error %q, got %v", input, want, err)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	input := `[{"currency": "USD", "amount": 1000, "acquired": "2025-01-15"}, {"currency": "CNY", "amount": 5000}]`
+	holdings, err := ParseJSON(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holdings) != 2 || holdings[0].Acquired.IsZero() || holdings[1].Currency != "CNY" || !holdings[1].Acquired.IsZero() {
+		t.Errorf("Unexpected holdings: %+v", holdings)
+	}
+	if _, err := ParseJSON(strings.NewReader(`[{"currency": "", "amount": 1}]`)); err == nil || !strings.Contains(err.Error(), "holding 1: currency is empty") {
+		t.Errorf("Expected empty currency error, got %v", err)
+	}
+}
+
+func TestCodesAndEarliest(t *testing.T) {
+	holdings := []Holding{
+		{Currency: "USD", Acquired: day(5)},
+		{Currency: "rub"},
+		{Currency: "usd", Acquired: day(2)},
+		{Currency: "EUR"},
+	}
+	if got := strings.Join(Codes(holdings), ","); got != "USD,EUR" {
+		t.Errorf("Unexpected codes: %s", got)
+	}
+	if got := Earliest(holdings); !got.Equal(day(2)) {
+		t.Errorf("Unexpected earliest: %v", got)
+	}
+}
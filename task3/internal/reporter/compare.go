@@ -0,0 +1,111 @@
+package reporter
+
+import (
+	"strings"
+
+	"task3/internal/i18n"
+	"task3/internal/stats"
+)
+
+// moversCount — сколько валют с наибольшим изменением среднего перечислять.
+const moversCount = 5
+
+// Comparison — сравнение периода сводки с базовым периодом (-compare): по
+// каждой валюте средние, экстремумы и волатильность в обоих периодах и их
+// изменения.
+type Comparison struct {
+	Base   Period
+	Deltas []stats.Delta
+	// Movers — валюты с наибольшим по модулю изменением среднего курса.
+	Movers []stats.Delta
+}
+
+// NewComparison сопоставляет статистику валют базового и текущего периодов.
+func NewComparison(base Period, baseCurrencies, currencies []stats.Currency) *Comparison {
+	deltas := stats.Compare(baseCurrencies, currencies)
+	return &Comparison{Base: base, Deltas: deltas, Movers: stats.Movers(deltas, moversCount)}
+}
+
+// compareColumns — заголовки таблицы сравнения (ключи каталога); первые два
+// столбца выровнены влево.
+var compareColumns = []string{
+	"table.currency", "table.name", "table.base_avg", "table.avg", "table.avg_change",
+	"table.max_change", "table.min_change", "table.base_volatility", "table.volatility", "table.volatility_change",
+}
+
+func compareHeader(l *i18n.Locale) []string {
+	header := make([]string, len(compareColumns))
+	for i, key := range compareColumns {
+		header[i] = l.T(key)
+	}
+	return header
+}
+
+// compareCells — строка таблицы сравнения; чего нет в одном из периодов,
+// то «—».
+func compareCells(l *i18n.Locale, d stats.Delta) []string {
+	cells := []string{d.Code(), l.Name(d.Name()), "—", "—", "—", "—", "—", "—", "—", "—"}
+	if d.Base != nil {
+		cells[2], cells[7] = l.Number(d.Base.Avg, 4), l.Number(d.Base.Volatility*100, 2)+"%"
+	}
+	if d.Current != nil {
+		cells[3], cells[8] = l.Number(d.Current.Avg, 4), l.Number(d.Current.Volatility*100, 2)+"%"
+	}
+	if d.Both() {
+		cells[4], cells[5], cells[6] = signedPercent(l, d.Avg), signedPercent(l, d.Max), signedPercent(l, d.Min)
+		cells[9] = percentPoints(l, d.Volatility)
+	}
+	return cells
+}
+
+func (r *ConsoleReporter) writeComparison(w *errWriter, c *Comparison) {
+	l := r.locale
+	w.printf("%s\n", l.T("compare.title", l.Date(c.Base.From), l.Date(c.Base.To)))
+	for _, d := range c.Deltas {
+		w.printf("%s\n", compareLine(l, d, r.unit))
+	}
+	if len(c.Movers) > 0 {
+		w.printf("%s\n", moversLine(l, c.Movers))
+	}
+}
+
+func compareLine(l *i18n.Locale, d stats.Delta, unit string) string {
+	switch {
+	case d.Current == nil:
+		return l.T("compare.only_base", d.Code())
+	case d.Base == nil:
+		return l.T("compare.only_current", d.Code())
+	}
+	b, c := d.Base, d.Current
+	return l.T("compare.line", d.Code(),
+		l.Number(b.Avg, 4), l.Number(c.Avg, 4), unit, signedPercent(l, d.Avg),
+		l.Number(b.Max.Rate, 4), l.Number(c.Max.Rate, 4), signedPercent(l, d.Max),
+		l.Number(b.Min.Rate, 4), l.Number(c.Min.Rate, 4), signedPercent(l, d.Min),
+		l.Number(b.Volatility*100, 2)+"%", l.Number(c.Volatility*100, 2)+"%", percentPoints(l, d.Volatility))
+}
+
+func moversLine(l *i18n.Locale, movers []stats.Delta) string {
+	parts := make([]string, 0, len(movers))
+	for _, d := range movers {
+		parts = append(parts, d.Code()+" "+signedPercent(l, d.Avg))
+	}
+	return l.T("compare.movers", strings.Join(parts, ", "))
+}
+
+// signedPercent — доля в процентах со знаком: 0.0234 → "+2,34%".
+func signedPercent(l *i18n.Locale, v float64) string {
+	s := l.Number(v*100, 2) + "%"
+	if v > 0 {
+		s = "+" + s
+	}
+	return s
+}
+
+// percentPoints — разность долей в процентных пунктах со знаком.
+func percentPoints(l *i18n.Locale, v float64) string {
+	s := l.Number(v*100, 2)
+	if v > 0 {
+		s = "+" + s
+	}
+	return l.T("compare.pp", s)
+}
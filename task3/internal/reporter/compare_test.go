@@ -0,0 +1,165 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+// testComparison сравнивает валюты сводки с июлем: USD и EUR были дешевле,
+// CNY в текущем периоде нет.
+func testComparison(currencies []stats.Currency) *Comparison {
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	base := stats.ByCurrency([]model.CurrencyRate{
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 78, Date: day(1)},
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 80, Date: day(2)},
+		{ID: "R01239", CharCode: "EUR", Name: "Euro <EU>", Rate: 92, Date: day(1)},
+		{ID: "R01239", CharCode: "EUR", Name: "Euro <EU>", Rate: 92, Date: day(2)},
+		{ID: "R01375", CharCode: "CNY", Name: "Yuan", Rate: 11, Date: day(1)},
+	})
+	return NewComparison(Period{From: day(1), To: day(31)}, base, currencies)
+}
+
+func comparisonSummary() Summary {
+	summary := testSummary()
+	summary.Comparison = testComparison(summary.Currencies)
+	return summary
+}
+
+func TestConsoleReporter_Report_Comparison(t *testing.T) {
+	var out bytes.Buffer
+	summary := comparisonSummary()
+	summary.Indicators, summary.Diagnostics = nil, nil
+	report(t, NewConsoleReporter(WithWriter(&out)), summary)
+
+	expected := "Максимум: Euro <EU> — 95,0000 руб. на 2025-10-20\n" +
+		"Минимум: US Dollar — 80,0000 руб. на 2025-10-20\n" +
+		"Среднее значение курса: 87,5250 руб.\n" +
+		"Сравнение с периодом 2025-07-01 — 2025-07-31:\n" +
+		"  CNY: нет курсов в текущем периоде\n" +
+		"  EUR: среднее 92,0000 → 94,0500 руб. (+2,23%), максимум 92,0000 → 95,0000 (+3,26%), минимум 92,0000 → 93,1000 (+1,20%), волатильность 0,00% → 0,00% (0,00 п.п.)\n" +
+		"  USD: среднее 79,0000 → 81,0000 руб. (+2,53%), максимум 80,0000 → 82,0000 (+2,50%), минимум 78,0000 → 80,0000 (+2,56%), волатильность 0,00% → 0,00% (0,00 п.п.)\n" +
+		"Наибольшие изменения среднего: USD +2,53%, EUR +2,23%\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestTerminalReporter_Comparison(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewTerminalReporter(WithWriter(&out), WithLocale(locale(t, "en")), WithWidth(80))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, comparisonSummary())
+
+	got := out.String()
+	for _, want := range []string{
+		"\nCompared with 2025-07-01 — 2025-07-31:\n",
+		"Currency  Name       Average (base)  Average  Δ average   Δ max   Δ min  Volatility (base)  Volatility  Δ volatility\n",
+		"CNY       Yuan              11.0000        —          —       —       —              0.00%           —             —\n",
+		"USD       US Dollar         79.0000  81.0000     +2.53%  +2.50%  +2.56%              0.00%       0.00%       0.00 pp\n",
+		"Biggest movers by average: USD +2.53%, EUR +2.23%\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output must contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestMarkdownReporter_Comparison(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewMarkdownReporter(WithWriter(&out), WithLocale(locale(t, "en")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, comparisonSummary())
+
+	got := out.String()
+	for _, want := range []string{
+		"\nCompared with 2025-07-01 — 2025-07-31:\n\n| Currency | Name | Average (base) |",
+		"| :--- | :--- | ---: |",
+		"| USD | US Dollar | 79.0000 | 81.0000 | +2.53% | +2.50% | +2.56% | 0.00% | 0.00% | 0.00 pp |\n",
+		"\nBiggest movers by average: USD +2.53%, EUR +2.23%\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output must contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestHTMLReporter_Comparison(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewHTMLReporter(WithWriter(&out), WithLocale(locale(t, "en")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, comparisonSummary())
+
+	got := out.String()
+	for _, want := range []string{
+		"<h2>Compared with 2025-07-01 — 2025-07-31:</h2>",
+		"<tr><td>EUR</td><td class=\"name\">Euro &lt;EU&gt;</td><td>92.0000</td><td>94.0500</td><td>&#43;2.23%</td>",
+		"<p>Biggest movers by average: USD &#43;2.53%, EUR &#43;2.23%</p>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output must contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestJSONReporter_Comparison(t *testing.T) {
+	var out bytes.Buffer
+	report(t, NewJSONReporter(WithWriter(&out)), comparisonSummary())
+
+	var got struct {
+		Comparison struct {
+			BaseFrom   string `json:"base_from"`
+			BaseTo     string `json:"base_to"`
+			Currencies []struct {
+				Code    string
+				Base    *struct{ Avg float64 }
+				Current *struct{ Avg float64 }
+				Change  *struct{ Avg float64 }
+			}
+			Movers []string
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	c := got.Comparison
+	if c.BaseFrom != "2025-07-01" || c.BaseTo != "2025-07-31" || len(c.Currencies) != 3 || strings.Join(c.Movers, ",") != "USD,EUR" {
+		t.Fatalf("Unexpected comparison: %+v", c)
+	}
+	if cny := c.Currencies[0]; cny.Code != "CNY" || cny.Base == nil || cny.Current != nil || cny.Change != nil {
+		t.Errorf("Unexpected CNY: %+v", cny)
+	}
+	if usd := c.Currencies[2]; usd.Base.Avg != 79 || usd.Current.Avg != 81 || usd.Change == nil || usd.Change.Avg <= 0.025 {
+		t.Errorf("Unexpected USD: %+v", usd)
+	}
+}
+
+func TestXLSXReporter_Comparison(t *testing.T) {
+	var out bytes.Buffer
+	report(t, NewXLSXReporter(WithWriter(&out), WithLocale(locale(t, "en"))), comparisonSummary())
+
+	sheet := readSheet(t, out.Bytes(), "3")
+	if len(sheet.Rows) != 4 {
+		t.Fatalf("Expected header and 3 currencies, got %d rows", len(sheet.Rows))
+	}
+	// У CNY нет текущего периода — остаются только базовые среднее и волатильность
+	cny := sheet.Rows[1].Cells
+	if cny[0].Inline != "CNY" || cny[2].V != "11" || len(cny) != 4 {
+		t.Errorf("Unexpected CNY row: %+v", cny)
+	}
+	usd := sheet.Rows[3].Cells
+	if usd[0].Inline != "USD" || usd[3].V != "81" || usd[4].S != 3 {
+		t.Errorf("Unexpected USD row: %+v", usd)
+	}
+}
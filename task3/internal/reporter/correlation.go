@@ -0,0 +1,244 @@
+package reporter
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+// corrShades — символы тепловой карты по |r|, от слабой связи к сильной.
+var corrShades = []struct {
+	min   float64
+	shade rune
+}{
+	{0.8, '█'},
+	{0.6, '▓'},
+	{0.4, '▒'},
+	{0.2, '░'},
+	{0, ' '},
+}
+
+var corrMethods = []stats.CorrelationMethod{stats.Pearson, stats.Spearman}
+
+// CorrelationReporter выводит вместо статистики курсов корреляции дневных
+// лог-доходностей валют по Пирсону и Спирмену и, если задан WithPair,
+// скользящую корреляцию пары. Форматы: console (тепловая карта), csv и json.
+type CorrelationReporter struct {
+	options
+	format string
+}
+
+func NewCorrelationReporter(format string, opts ...Option) (*CorrelationReporter, error) {
+	switch format {
+	case "console", "csv", "json":
+	default:
+		return nil, fmt.Errorf("unknown correlation format %q: use console, csv or json", format)
+	}
+	return &CorrelationReporter{options: applyOptions(opts), format: format}, nil
+}
+
+func (r *CorrelationReporter) Report(_ context.Context, summary Summary) error {
+	returns := stats.AlignReturns(summary.Currencies)
+	matrices := make([]stats.CorrelationMatrix, 0, len(corrMethods))
+	for _, method := range corrMethods {
+		matrices = append(matrices, returns.Correlation(method))
+	}
+
+	var rolling []model.Point
+	if r.pair[0] != "" {
+		var err error
+		rolling, err = returns.Rolling(r.pair[0], r.pair[1], r.window)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch r.format {
+	case "csv":
+		return r.writeCSV(matrices, rolling)
+	case "json":
+		return r.writeJSON(summary.Period, matrices, rolling)
+	default:
+		return r.writeConsole(matrices, rolling)
+	}
+}
+
+func (r *CorrelationReporter) writeConsole(matrices []stats.CorrelationMatrix, rolling []model.Point) error {
+	l := r.locale
+	w := &errWriter{w: r.out}
+
+	width := 0
+	for _, code := range matrices[0].Codes {
+		width = max(width, len([]rune(code)))
+	}
+	const cellWidth = 8
+
+	for i, m := range matrices {
+		if i > 0 {
+			w.printf("\n")
+		}
+		w.printf("%s\n", l.T("corr.title", l.T("corr."+string(m.Method))))
+		w.printf("%s", strings.Repeat(" ", width))
+		for _, code := range m.Codes {
+			w.printf("%s", pad(code, cellWidth))
+		}
+		w.printf("\n")
+		for i, code := range m.Codes {
+			w.printf("%s", code+strings.Repeat(" ", width-len([]rune(code))))
+			for _, v := range m.R[i] {
+				cell := "—"
+				if !math.IsNaN(v) {
+					cell = string(shade(v)) + " " + l.Number(v, 2)
+				}
+				w.printf("%s", pad(cell, cellWidth))
+			}
+			w.printf("\n")
+		}
+	}
+
+	if lo, hi, ok := observations(matrices[0]); ok {
+		w.printf("%s\n", l.T("corr.observations", lo, hi))
+	}
+	legend := make([]string, 0, len(corrShades)-1)
+	for _, s := range corrShades[:len(corrShades)-1] {
+		legend = append(legend, string(s.shade)+" ≥ "+l.Number(s.min, 1))
+	}
+	w.printf("%s\n", l.T("corr.legend", strings.Join(legend, "  ")))
+
+	if r.pair[0] != "" {
+		a, b := strings.ToUpper(r.pair[0]), strings.ToUpper(r.pair[1])
+		if len(rolling) == 0 {
+			w.printf("%s\n", l.T("corr.rolling_empty", a, b, r.window))
+		} else {
+			last, lo, hi := rolling[len(rolling)-1], minPoint(rolling), maxPoint(rolling)
+			w.printf("%s\n", l.T("corr.rolling", a, b, r.window,
+				l.Number(last.Value, 2), l.Date(last.Date), l.Number(lo.Value, 2), l.Number(hi.Value, 2)))
+			rates := make([]model.CurrencyRate, 0, len(rolling))
+			for _, p := range rolling {
+				rates = append(rates, model.CurrencyRate{Rate: p.Value, Date: p.Date})
+			}
+			w.printf("%s\n", blockSparkline(rates, 60))
+		}
+	}
+	return w.err
+}
+
+// pad выравнивает текст по правому краю ячейки шириной width символов.
+func pad(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return " " + s
+}
+
+func shade(v float64) rune {
+	for _, s := range corrShades {
+		if math.Abs(v) >= s.min {
+			return s.shade
+		}
+	}
+	return ' '
+}
+
+// observations — наименьшее и наибольшее число общих доходностей среди пар
+// разных валют.
+func observations(m stats.CorrelationMatrix) (int, int, bool) {
+	lo, hi, ok := 0, 0, false
+	for i := range m.N {
+		for j := i + 1; j < len(m.N); j++ {
+			n := m.N[i][j]
+			if !ok {
+				lo, hi, ok = n, n, true
+			}
+			lo, hi = min(lo, n), max(hi, n)
+		}
+	}
+	return lo, hi, ok
+}
+
+// writeCSV выводит длинную таблицу kind,date,a,b,value,n: строки матриц
+// (kind — метод, без даты) и скользящей корреляции (kind=rolling, n — окно).
+// Неопределённая корреляция — пустое value.
+func (r *CorrelationReporter) writeCSV(matrices []stats.CorrelationMatrix, rolling []model.Point) error {
+	w := csv.NewWriter(r.out)
+	w.Write([]string{"kind", "date", "a", "b", "value", "n"})
+	for _, m := range matrices {
+		for i, a := range m.Codes {
+			for j, b := range m.Codes {
+				w.Write([]string{string(m.Method), "", a, b, csvValue(m.R[i][j]), strconv.Itoa(m.N[i][j])})
+			}
+		}
+	}
+	a, b := strings.ToUpper(r.pair[0]), strings.ToUpper(r.pair[1])
+	for _, p := range rolling {
+		w.Write([]string{"rolling", p.Date.Format(jsonDate), a, b, csvValue(p.Value), strconv.Itoa(r.window)})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func csvValue(v float64) string {
+	if math.IsNaN(v) {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', 6, 64)
+}
+
+type jsonCorrelation struct {
+	From     string           `json:"from"`
+	To       string           `json:"to"`
+	Matrices []jsonCorrMatrix `json:"matrices"`
+	Rolling  *jsonRollingCorr `json:"rolling,omitempty"`
+}
+
+// jsonCorrMatrix — матрица метода; неопределённая корреляция — null.
+type jsonCorrMatrix struct {
+	Method string       `json:"method"`
+	Codes  []string     `json:"codes"`
+	R      [][]*float64 `json:"r"`
+	N      [][]int      `json:"n"`
+}
+
+type jsonRollingCorr struct {
+	A      string      `json:"a"`
+	B      string      `json:"b"`
+	Window int         `json:"window"`
+	Points []jsonPoint `json:"points"`
+}
+
+func (r *CorrelationReporter) writeJSON(period Period, matrices []stats.CorrelationMatrix, rolling []model.Point) error {
+	jc := jsonCorrelation{From: period.From.Format(jsonDate), To: period.To.Format(jsonDate)}
+	for _, m := range matrices {
+		jm := jsonCorrMatrix{Method: string(m.Method), Codes: m.Codes, N: m.N}
+		for _, row := range m.R {
+			values := make([]*float64, len(row))
+			for j, v := range row {
+				if !math.IsNaN(v) {
+					values[j] = &v
+				}
+			}
+			jm.R = append(jm.R, values)
+		}
+		jc.Matrices = append(jc.Matrices, jm)
+	}
+	if r.pair[0] != "" {
+		jc.Rolling = &jsonRollingCorr{
+			A: strings.ToUpper(r.pair[0]), B: strings.ToUpper(r.pair[1]), Window: r.window,
+			Points: make([]jsonPoint, 0, len(rolling)),
+		}
+		for _, p := range rolling {
+			jc.Rolling.Points = append(jc.Rolling.Points, jsonPoint{Date: p.Date.Format(jsonDate), Value: p.Value})
+		}
+	}
+
+	enc := json.NewEncoder(r.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jc)
+}
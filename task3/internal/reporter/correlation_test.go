@@ -0,0 +1,111 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+func correlationSummary() Summary {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	var rates []model.CurrencyRate
+	usd := []float64{80, 81, 80.5, 82, 81, 83}
+	cny := []float64{11, 11.2, 11.1, 11.4, 11.2, 11.5}
+	for i := range usd {
+		rates = append(rates,
+			model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: usd[i], Date: day(i + 1)},
+			model.CurrencyRate{ID: "R01375", CharCode: "CNY", Name: "Yuan", Rate: cny[i], Date: day(i + 1)},
+		)
+	}
+	// У EUR только два курса — корреляция с ним не определена
+	rates = append(rates,
+		model.CurrencyRate{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: 95, Date: day(1)},
+		model.CurrencyRate{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: 96, Date: day(2)},
+	)
+	return Summary{Period: Period{From: day(1), To: day(6)}, Currencies: stats.ByCurrency(rates)}
+}
+
+func TestCorrelationReporter_Console(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewCorrelationReporter("console", WithWriter(&out), WithPair("usd", "cny", 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, correlationSummary())
+
+	got := out.String()
+	for _, want := range []string{
+		"Корреляция дневных лог-доходностей (Пирсон)\n",
+		"Корреляция дневных лог-доходностей (Спирмен)\n",
+		"     CNY     EUR     USD\n",
+		"CNY  █ 1,00       —  █ 0,99\n",
+		"Общих доходностей на пару: от 1 до 5\n",
+		"Шкала |r|: █ ≥ 0,8  ▓ ≥ 0,6  ▒ ≥ 0,4  ░ ≥ 0,2\n",
+		"Скользящая корреляция USD/CNY (окно 4): последняя ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output must contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCorrelationReporter_CSV(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewCorrelationReporter("csv", WithWriter(&out), WithPair("USD", "CNY", 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, correlationSummary())
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	// Заголовок, 2 матрицы по 3×3 и 3 окна скользящей корреляции
+	if len(records) != 1+18+3 {
+		t.Fatalf("Unexpected number of records: %d\n%v", len(records), records)
+	}
+	if got := records[2]; got[0] != "pearson" || got[2] != "CNY" || got[3] != "EUR" || got[4] != "" || got[5] != "1" {
+		t.Errorf("Undefined correlation must be empty: %v", got)
+	}
+	if got := records[len(records)-1]; got[0] != "rolling" || got[1] != "2025-10-06" || got[5] != "4" {
+		t.Errorf("Unexpected rolling record: %v", got)
+	}
+}
+
+func TestCorrelationReporter_JSON(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewCorrelationReporter("json", WithWriter(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, correlationSummary())
+
+	var got jsonCorrelation
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(got.Matrices) != 2 || got.Matrices[1].Method != "spearman" || got.Rolling != nil {
+		t.Fatalf("Unexpected correlation: %+v", got)
+	}
+	m := got.Matrices[0]
+	if m.R[0][1] != nil || m.R[0][2] == nil || *m.R[0][2] < 0.9 || m.N[0][2] != 5 {
+		t.Errorf("Unexpected matrix: %s", out.String())
+	}
+}
+
+func TestCorrelationReporter_Errors(t *testing.T) {
+	if _, err := NewCorrelationReporter("html"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+	r, _ := NewCorrelationReporter("console", WithWriter(&bytes.Buffer{}), WithPair("USD", "GBP", 4))
+	if err := r.Report(t.Context(), correlationSummary()); err == nil {
+		t.Error("Expected error for unknown pair currency")
+	}
+}
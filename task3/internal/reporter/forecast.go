@@ -0,0 +1,257 @@
+package reporter
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"task3/internal/forecast"
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+// ForecastConfig задаёт для ForecastReporter валюту (буквенный код или код
+// ЦБ), модели, горизонт в рабочих днях и уровень интервала прогноза, например
+// 0.95. Holdout > 0 добавляет проверку моделей: прогноз последних Holdout
+// курсов по остальной истории. Нулевые значения — модели и параметры по
+// умолчанию.
+type ForecastConfig struct {
+	Code    string
+	Methods []forecast.Method
+	Horizon int
+	Level   float64
+	Holdout int
+}
+
+// ForecastReporter выводит вместо статистики курсов прогноз одной валюты на
+// несколько рабочих дней по моделям из ForecastConfig и, если задан Holdout,
+// ошибки тех же моделей на последних курсах истории. Форматы: console, csv и
+// json.
+type ForecastReporter struct {
+	options
+	cfg    ForecastConfig
+	format string
+}
+
+func NewForecastReporter(format string, cfg ForecastConfig, opts ...Option) (*ForecastReporter, error) {
+	switch format {
+	case "console", "csv", "json":
+	default:
+		return nil, fmt.Errorf("unknown forecast format %q: use console, csv or json", format)
+	}
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = forecast.DefaultMethods
+	}
+	if cfg.Horizon == 0 {
+		cfg.Horizon = 10
+	}
+	if cfg.Level == 0 {
+		cfg.Level = 0.95
+	}
+	return &ForecastReporter{options: applyOptions(opts), cfg: cfg, format: format}, nil
+}
+
+func (r *ForecastReporter) Report(_ context.Context, summary Summary) error {
+	c, err := r.currency(summary.Currencies)
+	if err != nil {
+		return err
+	}
+	series := forecast.Official(c.Points)
+	if len(series) == 0 {
+		return fmt.Errorf("no official rates for %s", c.Code())
+	}
+
+	cfg := r.cfg
+	var forecasts []forecast.Forecast
+	var backtests []forecast.Backtest
+	for _, method := range cfg.Methods {
+		f, err := forecast.Predict(method, series, cfg.Horizon, cfg.Level)
+		if err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+		forecasts = append(forecasts, f)
+		if cfg.Holdout > 0 {
+			b, err := forecast.Test(method, series, cfg.Holdout, cfg.Level)
+			if err != nil {
+				return fmt.Errorf("%s backtest: %w", method, err)
+			}
+			backtests = append(backtests, b)
+		}
+	}
+
+	last := series[len(series)-1]
+	switch r.format {
+	case "csv":
+		return r.writeCSV(forecasts, backtests)
+	case "json":
+		return r.writeJSON(c, last, len(series), forecasts, backtests)
+	default:
+		return r.writeConsole(c, last, len(series), forecasts, backtests)
+	}
+}
+
+// currency выбирает валюту по коду из ForecastConfig; без кода подходит
+// только единственная валюта сводки.
+func (r *ForecastReporter) currency(currencies []stats.Currency) (stats.Currency, error) {
+	code := r.cfg.Code
+	if code == "" {
+		if len(currencies) != 1 {
+			return stats.Currency{}, fmt.Errorf("forecast needs one currency, got %d", len(currencies))
+		}
+		return currencies[0], nil
+	}
+	for _, c := range currencies {
+		if strings.EqualFold(c.Code(), code) || strings.EqualFold(c.ID, code) {
+			return c, nil
+		}
+	}
+	return stats.Currency{}, fmt.Errorf("no series for %s", code)
+}
+
+func (r *ForecastReporter) writeConsole(c stats.Currency, last model.CurrencyRate, n int, forecasts []forecast.Forecast, backtests []forecast.Backtest) error {
+	l := r.locale
+	w := &errWriter{w: r.out}
+	level := l.Number(r.cfg.Level*100, 0) + "%"
+
+	w.printf("%s\n", l.T("forecast.title", c.Code(), l.Name(c.Name), r.cfg.Horizon, level))
+	w.printf("%s\n", l.T("forecast.last", l.Date(last.Date), l.Number(last.Rate, 4), r.unit, n))
+	for _, f := range forecasts {
+		w.printf("%s\n", r.modelLine(f))
+		for _, p := range f.Points {
+			w.printf("%s\n", l.T("forecast.point", l.Date(p.Date), l.Number(p.Value, 4), l.Number(p.Lower, 4), l.Number(p.Upper, 4)))
+		}
+	}
+	if len(backtests) > 0 {
+		w.printf("%s\n", l.T("forecast.backtest", r.cfg.Holdout))
+		for _, b := range backtests {
+			w.printf("%s\n", l.T("forecast.errors", l.T("forecast."+string(b.Method)),
+				l.Number(b.MAE, 4), r.unit, l.Number(b.MAPE*100, 2)+"%", l.Number(coverage(b)*100, 0)+"%"))
+		}
+	}
+	return w.err
+}
+
+// modelLine — название модели с оценёнными параметрами и σ остатков.
+func (r *ForecastReporter) modelLine(f forecast.Forecast) string {
+	l := r.locale
+	params := make([]string, 0, len(f.Params)+1)
+	for _, p := range f.Params {
+		params = append(params, p.Name+" "+l.Number(p.Value, 4))
+	}
+	params = append(params, "σ "+l.Number(f.Sigma, 4))
+	return l.T("forecast.model", l.T("forecast."+string(f.Method)), strings.Join(params, ", "))
+}
+
+// coverage — доля спрятанных курсов, попавших в интервал прогноза.
+func coverage(b forecast.Backtest) float64 {
+	var in int
+	for i, p := range b.Points {
+		if b.Actual[i] >= p.Lower && b.Actual[i] <= p.Upper {
+			in++
+		}
+	}
+	return float64(in) / float64(len(b.Points))
+}
+
+// writeCSV выводит таблицу method,kind,date,forecast,lower,upper,actual:
+// строки прогноза (kind=forecast, actual пустой) и бэктеста (kind=backtest).
+func (r *ForecastReporter) writeCSV(forecasts []forecast.Forecast, backtests []forecast.Backtest) error {
+	w := csv.NewWriter(r.out)
+	w.Write([]string{"method", "kind", "date", "forecast", "lower", "upper", "actual"})
+	for _, f := range forecasts {
+		for _, p := range f.Points {
+			w.Write(append(forecastRow(f.Method, "forecast", p), ""))
+		}
+	}
+	for _, b := range backtests {
+		for i, p := range b.Points {
+			w.Write(append(forecastRow(b.Method, "backtest", p), strconv.FormatFloat(b.Actual[i], 'f', 4, 64)))
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func forecastRow(method forecast.Method, kind string, p forecast.Point) []string {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	return []string{string(method), kind, p.Date.Format(jsonDate), format(p.Value), format(p.Lower), format(p.Upper)}
+}
+
+type jsonForecastReport struct {
+	Code      string         `json:"code"`
+	Name      string         `json:"name"`
+	Last      jsonPoint      `json:"last"`
+	Rates     int            `json:"rates"`
+	Horizon   int            `json:"horizon"`
+	Level     float64        `json:"level"`
+	Forecasts []jsonForecast `json:"forecasts"`
+	Backtests []jsonBacktest `json:"backtests,omitempty"`
+}
+
+type jsonForecast struct {
+	Method string              `json:"method"`
+	Params map[string]float64  `json:"params"`
+	Sigma  float64             `json:"sigma"`
+	Points []jsonForecastPoint `json:"points"`
+}
+
+type jsonForecastPoint struct {
+	Date   string   `json:"date"`
+	Value  float64  `json:"value"`
+	Lower  float64  `json:"lower"`
+	Upper  float64  `json:"upper"`
+	Actual *float64 `json:"actual,omitempty"`
+}
+
+type jsonBacktest struct {
+	jsonForecast
+	Holdout  int     `json:"holdout"`
+	MAE      float64 `json:"mae"`
+	MAPE     float64 `json:"mape"`
+	Coverage float64 `json:"coverage"`
+}
+
+func (r *ForecastReporter) writeJSON(c stats.Currency, last model.CurrencyRate, n int, forecasts []forecast.Forecast, backtests []forecast.Backtest) error {
+	report := jsonForecastReport{
+		Code:    c.Code(),
+		Name:    c.Name,
+		Last:    jsonPoint{Date: last.Date.Format(jsonDate), Value: last.Rate},
+		Rates:   n,
+		Horizon: r.cfg.Horizon,
+		Level:   r.cfg.Level,
+	}
+	for _, f := range forecasts {
+		report.Forecasts = append(report.Forecasts, newJSONForecast(f, nil))
+	}
+	for _, b := range backtests {
+		report.Backtests = append(report.Backtests, jsonBacktest{
+			jsonForecast: newJSONForecast(b.Forecast, b.Actual),
+			Holdout:      len(b.Points),
+			MAE:          b.MAE,
+			MAPE:         b.MAPE,
+			Coverage:     coverage(b),
+		})
+	}
+
+	enc := json.NewEncoder(r.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func newJSONForecast(f forecast.Forecast, actual []float64) jsonForecast {
+	jf := jsonForecast{Method: string(f.Method), Params: make(map[string]float64, len(f.Params)), Sigma: f.Sigma}
+	for _, p := range f.Params {
+		jf.Params[p.Name] = p.Value
+	}
+	for i, p := range f.Points {
+		jp := jsonForecastPoint{Date: p.Date.Format(jsonDate), Value: p.Value, Lower: p.Lower, Upper: p.Upper}
+		if actual != nil {
+			jp.Actual = &actual[i]
+		}
+		jf.Points = append(jf.Points, jp)
+	}
+	return jf
+}
@@ -0,0 +1,126 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/forecast"
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+// forecastSummary — USD растёт на рубль в рабочий день, у EUR курс один.
+func forecastSummary() Summary {
+	var dates []time.Time
+	for d := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC); len(dates) < 10; d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			dates = append(dates, d)
+		}
+	}
+	var rates []model.CurrencyRate
+	for i, d := range dates {
+		rates = append(rates, model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "Доллар США", Rate: 80 + float64(i), Date: d})
+	}
+	rates = append(rates, model.CurrencyRate{ID: "R01239", CharCode: "EUR", Name: "Евро", Rate: 95, Date: dates[0]})
+	return Summary{Period: Period{From: dates[0], To: dates[9]}, Currencies: stats.ByCurrency(rates)}
+}
+
+func TestForecastReporter_Console(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewForecastReporter("console", ForecastConfig{Code: "usd", Methods: []forecast.Method{forecast.Linear}, Horizon: 2, Level: 0.9, Holdout: 3}, WithWriter(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, forecastSummary())
+
+	want := `Прогноз USD (Доллар США) на 2 рабочих дней, интервал 90%
+Последний курс 2025-09-12: 89,0000 руб., курсов в истории: 10
+Линейный тренд (slope 1,0000, σ 0,0000)
+  2025-09-15  90,0000  [90,0000 — 90,0000]
+  2025-09-16  91,0000  [91,0000 — 91,0000]
+Бэктест на последних 3 курсах:
+  Линейный тренд: MAE 0,0000 руб., MAPE 0,00%, в интервале 100%
+`
+	if got := out.String(); got != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestForecastReporter_CSV(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewForecastReporter("csv", ForecastConfig{Code: "R01235", Methods: []forecast.Method{forecast.Linear, forecast.Holt}, Horizon: 2, Level: 0.95, Holdout: 2}, WithWriter(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, forecastSummary())
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Заголовок, по 2 строки прогноза и бэктеста на модель
+	if len(rows) != 9 {
+		t.Fatalf("Expected 9 rows, got %d: %v", len(rows), rows)
+	}
+	if got := strings.Join(rows[1], ","); got != "linear,forecast,2025-09-15,90.0000,90.0000,90.0000," {
+		t.Errorf("Unexpected forecast row: %s", got)
+	}
+	if got := strings.Join(rows[5], ","); got != "linear,backtest,2025-09-11,88.0000,88.0000,88.0000,88.0000" {
+		t.Errorf("Unexpected backtest row: %s", got)
+	}
+}
+
+func TestForecastReporter_JSON(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewForecastReporter("json", ForecastConfig{Code: "usd", Methods: []forecast.Method{forecast.Holt}, Horizon: 3, Level: 0.95, Holdout: 2}, WithWriter(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, forecastSummary())
+
+	var got struct {
+		Code      string
+		Rates     int
+		Forecasts []struct {
+			Method string
+			Params map[string]float64
+			Points []struct{ Date string }
+		}
+		Backtests []struct {
+			Holdout int
+			MAE     float64
+			Points  []struct{ Actual *float64 }
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Code != "USD" || got.Rates != 10 || len(got.Forecasts) != 1 || len(got.Forecasts[0].Points) != 3 {
+		t.Fatalf("Unexpected report: %+v", got)
+	}
+	if _, ok := got.Forecasts[0].Params["alpha"]; !ok || got.Forecasts[0].Method != "holt" {
+		t.Errorf("Unexpected forecast: %+v", got.Forecasts[0])
+	}
+	if len(got.Backtests) != 1 || got.Backtests[0].Holdout != 2 || got.Backtests[0].Points[0].Actual == nil {
+		t.Errorf("Unexpected backtests: %+v", got.Backtests)
+	}
+}
+
+func TestForecastReporter_Errors(t *testing.T) {
+	if _, err := NewForecastReporter("xlsx", ForecastConfig{}); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+	r, _ := NewForecastReporter("console", ForecastConfig{Code: "gbp", Horizon: 5, Level: 0.95}, WithWriter(&bytes.Buffer{}))
+	if err := r.Report(t.Context(), forecastSummary()); err == nil {
+		t.Error("Expected error for missing currency")
+	}
+	// У EUR один курс — модель не строится
+	r, _ = NewForecastReporter("console", ForecastConfig{Code: "eur", Horizon: 5, Level: 0.95}, WithWriter(&bytes.Buffer{}))
+	if err := r.Report(t.Context(), forecastSummary()); err == nil {
+		t.Error("Expected error for too short series")
+	}
+}
@@ -0,0 +1,119 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestHTMLReporter_Golden(t *testing.T) {
+	for _, lang := range []string{"ru", "en"} {
+		var out bytes.Buffer
+		r, err := NewHTMLReporter(WithWriter(&out), WithLocale(locale(t, lang)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := r.Report(context.Background(), testSummary()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		golden := filepath.Join("testdata", "report."+lang+".golden.html")
+		if *update {
+			if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("Failed to read golden file (run with -update): %v", err)
+		}
+		if out.String() != string(expected) {
+			t.Errorf("%s: output differs from %s:\n%s", lang, golden, out.String())
+		}
+	}
+}
+
+func TestHTMLReporter_SelfContained(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewHTMLReporter(WithWriter(&out))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), markdownSummary()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	page := out.String()
+	// Без скриптов и внешних ресурсов; ссылка на источник — не ресурс
+	if !strings.Contains(page, `<p>Источник: <a href="http://www.cbr.ru/scripts/XML_daily.asp">Банк России</a></p>`) {
+		t.Errorf("Expected source link, got:\n%s", page)
+	}
+	for _, banned := range []string{"<script", "src=", "<link", "@import"} {
+		if strings.Contains(page, banned) {
+			t.Errorf("Report must be self-contained, found %q", banned)
+		}
+	}
+	// Спарклайн на каждую валюту, график на каждую валюту и показатель
+	if got := strings.Count(page, `class="sparkline"`); got != 2 {
+		t.Errorf("Expected 2 sparklines, got %d", got)
+	}
+	if got := strings.Count(page, `class="chart"`); got != 3 {
+		t.Errorf("Expected 3 charts, got %d", got)
+	}
+	if strings.Contains(page, "Euro <EU>") {
+		t.Error("Currency name is not escaped")
+	}
+}
+
+func TestLineChart_AnnotatesExtremes(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	points := []model.Point{
+		{Date: day(1), Value: 10},
+		{Date: day(2), Value: 5},
+		{Date: day(3), Value: 5, Filled: true},
+		{Date: day(5), Value: 20},
+	}
+	value := func(v float64) string { return coord(v) }
+	date := func(t time.Time) string { return t.Format("02.01") }
+
+	svg := lineChart("A & B", points, value, date)
+
+	// Минимум и максимум — крайние значения по оси Y, X — по дате
+	if !strings.Contains(svg, `<circle cx="620.0" cy="24.0" r="3" fill="`+maxColor+`"/>`) {
+		t.Errorf("Max point is not annotated:\n%s", svg)
+	}
+	if !strings.Contains(svg, `<circle cx="207.5" cy="200.0" r="3" fill="`+minColor+`"/>`) {
+		t.Errorf("Min point is not annotated:\n%s", svg)
+	}
+	if !strings.Contains(svg, ">20.0 (05.10)</text>") || !strings.Contains(svg, ">5.0 (02.10)</text>") {
+		t.Errorf("Extremes are not labeled:\n%s", svg)
+	}
+	// Восстановленная точка отмечена пустым кружком
+	if strings.Count(svg, `class="filled"`) != 1 {
+		t.Errorf("Filled point is not marked:\n%s", svg)
+	}
+	if !strings.Contains(svg, "<title>A &amp; B</title>") {
+		t.Errorf("Title is not escaped:\n%s", svg)
+	}
+}
+
+func TestSparkline_Degenerate(t *testing.T) {
+	if svg := sparkline(nil); svg != "" {
+		t.Errorf("Expected empty sparkline, got %q", svg)
+	}
+
+	// Одна точка — по центру, без деления на ноль
+	svg := sparkline([]model.Point{{Date: time.Now(), Value: 1}})
+	if !strings.Contains(svg, `points="60.0,12.0"`) {
+		t.Errorf("Unexpected single-point sparkline: %s", svg)
+	}
+}
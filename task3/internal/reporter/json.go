@@ -0,0 +1,398 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"task3/internal/fetcher"
+	"task3/internal/gaps"
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+const jsonDate = "2006-01-02"
+
+// Структуры JSON-отчёта: даты в формате 2006-01-02, имена полей в snake_case.
+// Формат общий для JSONReporter и WebhookReporter.
+type jsonSummary struct {
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	Source       *jsonSource      `json:"source,omitempty"`
+	Max          *jsonRate        `json:"max,omitempty"`
+	Min          *jsonRate        `json:"min,omitempty"`
+	Avg          *float64         `json:"avg,omitempty"`
+	Count        int              `json:"count"`
+	PerSeries    bool             `json:"per_series,omitempty"`
+	Currencies   []jsonCurrency   `json:"currencies"`
+	Indicators   []jsonSeries     `json:"indicators,omitempty"`
+	Diagnostics  []jsonDiagnostic `json:"diagnostics,omitempty"`
+	MissingDates []string         `json:"missing_dates,omitempty"`
+	Revisions    []jsonRevision   `json:"revisions,omitempty"`
+	AsOf         *time.Time       `json:"as_of,omitempty"`
+	Gaps         *jsonGaps        `json:"gaps,omitempty"`
+	Anomalies    []jsonAnomaly    `json:"anomalies,omitempty"`
+	Comparison   *jsonComparison  `json:"comparison,omitempty"`
+}
+
+// jsonComparison — сравнение с базовым периодом; movers — коды валют по
+// убыванию модуля изменения среднего.
+type jsonComparison struct {
+	BaseFrom   string      `json:"base_from"`
+	BaseTo     string      `json:"base_to"`
+	Currencies []jsonDelta `json:"currencies"`
+	Movers     []string    `json:"movers"`
+}
+
+// jsonDelta — валюта в двух периодах; change — только если она есть в обоих.
+type jsonDelta struct {
+	Code    string           `json:"code"`
+	Name    string           `json:"name"`
+	Base    *jsonPeriodStats `json:"base,omitempty"`
+	Current *jsonPeriodStats `json:"current,omitempty"`
+	Change  *jsonChange      `json:"change,omitempty"`
+}
+
+type jsonPeriodStats struct {
+	Avg        float64  `json:"avg"`
+	Min        jsonRate `json:"min"`
+	Max        jsonRate `json:"max"`
+	Volatility float64  `json:"volatility"`
+	Count      int      `json:"count"`
+}
+
+// jsonChange — относительные изменения среднего и экстремумов и разность
+// волатильностей.
+type jsonChange struct {
+	Avg        float64 `json:"avg"`
+	Max        float64 `json:"max"`
+	Min        float64 `json:"min"`
+	Volatility float64 `json:"volatility"`
+}
+
+// jsonAnomaly — подозрительное изменение; score для reason=nominal — во
+// сколько раз изменился курс.
+type jsonAnomaly struct {
+	Date   string   `json:"date"`
+	Code   string   `json:"code"`
+	Prev   jsonRate `json:"prev"`
+	Rate   jsonRate `json:"rate"`
+	Return float64  `json:"return"`
+	Reason string   `json:"reason"`
+	Score  float64  `json:"score"`
+}
+
+type jsonGaps struct {
+	Fill        string   `json:"fill"`
+	MinCoverage float64  `json:"min_coverage,omitempty"`
+	Filled      int      `json:"filled"`
+	Dropped     []string `json:"dropped,omitempty"`
+}
+
+type jsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type jsonRate struct {
+	Code string  `json:"code,omitempty"`
+	Name string  `json:"name"`
+	Rate float64 `json:"rate"`
+	Date string  `json:"date"`
+}
+
+type jsonCurrency struct {
+	ID         string      `json:"id,omitempty"`
+	Code       string      `json:"code"`
+	Name       string      `json:"name"`
+	First      jsonRate    `json:"first"`
+	Last       jsonRate    `json:"last"`
+	Min        jsonRate    `json:"min"`
+	Max        jsonRate    `json:"max"`
+	Avg        float64     `json:"avg"`
+	Count      int         `json:"count"`
+	Change     float64     `json:"change"`
+	Volatility float64     `json:"volatility"`
+	Filled     int         `json:"filled,omitempty"`
+	Points     []jsonPoint `json:"points"`
+	Moves      *jsonMoves  `json:"moves,omitempty"`
+}
+
+// jsonMoves — просадка и рост (depth и rise в долях), крупнейшие дневные
+// изменения и самые длинные серии; чего в ряду не было, то опущено.
+type jsonMoves struct {
+	Drawdown *jsonDrawdown `json:"drawdown,omitempty"`
+	RunUp    *jsonRunUp    `json:"run_up,omitempty"`
+	Gains    []jsonMove    `json:"gains"`
+	Losses   []jsonMove    `json:"losses"`
+	Up       *jsonStreak   `json:"up_streak,omitempty"`
+	Down     *jsonStreak   `json:"down_streak,omitempty"`
+}
+
+type jsonRunUp struct {
+	Rise   float64   `json:"rise"`
+	Trough jsonPoint `json:"trough"`
+	Peak   jsonPoint `json:"peak"`
+}
+
+type jsonMove struct {
+	From   jsonPoint `json:"from"`
+	To     jsonPoint `json:"to"`
+	Change float64   `json:"change"`
+}
+
+type jsonStreak struct {
+	Length int       `json:"length"`
+	From   jsonPoint `json:"from"`
+	To     jsonPoint `json:"to"`
+	Change float64   `json:"change"`
+}
+
+type jsonPoint struct {
+	Date   string  `json:"date"`
+	Value  float64 `json:"value"`
+	Filled bool    `json:"filled,omitempty"`
+}
+
+type jsonSeries struct {
+	Name   string      `json:"name"`
+	Unit   string      `json:"unit,omitempty"`
+	Points []jsonPoint `json:"points"`
+}
+
+type jsonDiagnostic struct {
+	Date     string `json:"date"`
+	Index    int    `json:"index"`
+	Currency string `json:"currency"`
+	Field    string `json:"field"`
+	Raw      string `json:"raw"`
+	Reason   string `json:"reason"`
+}
+
+// jsonRevision — исправление курса; у добавленной позже валюты old нет.
+type jsonRevision struct {
+	Day      string    `json:"day"`
+	Code     string    `json:"code"`
+	Name     string    `json:"name"`
+	Old      *jsonRate `json:"old,omitempty"`
+	New      *jsonRate `json:"new,omitempty"`
+	Detected time.Time `json:"detected"`
+}
+
+func newJSONSummary(summary Summary) jsonSummary {
+	js := jsonSummary{
+		From:       summary.Period.From.Format(jsonDate),
+		To:         summary.Period.To.Format(jsonDate),
+		Count:      summary.Count,
+		PerSeries:  summary.PerSeries,
+		Currencies: make([]jsonCurrency, 0, len(summary.Currencies)),
+	}
+	if summary.Source.Name != "" {
+		js.Source = &jsonSource{Name: summary.Source.Name, URL: summary.Source.URL}
+	}
+	// Общие экстремумы для несопоставимых рядов не имеют смысла
+	if !summary.PerSeries {
+		maxRate, minRate, avg := newJSONRate(summary.Max), newJSONRate(summary.Min), summary.Avg
+		js.Max, js.Min, js.Avg = &maxRate, &minRate, &avg
+	}
+	for _, c := range summary.Currencies {
+		js.Currencies = append(js.Currencies, newJSONCurrency(c))
+	}
+	for _, s := range summary.Indicators {
+		series := jsonSeries{Name: s.Name, Unit: s.Unit, Points: make([]jsonPoint, 0, len(s.Points))}
+		for _, p := range s.Points {
+			series.Points = append(series.Points, jsonPoint{Date: p.Date.Format(jsonDate), Value: p.Value})
+		}
+		js.Indicators = append(js.Indicators, series)
+	}
+	for _, d := range summary.Diagnostics {
+		js.Diagnostics = append(js.Diagnostics, jsonDiagnostic{
+			Date: d.Date.Format(jsonDate), Index: d.Index, Currency: d.Currency,
+			Field: d.Field, Raw: d.Raw, Reason: d.Reason,
+		})
+	}
+	for _, d := range summary.MissingDates {
+		js.MissingDates = append(js.MissingDates, d.Format(jsonDate))
+	}
+	for _, r := range summary.Revisions {
+		jr := jsonRevision{
+			Day: r.Day.Format(jsonDate), Code: r.Code(), Name: r.Rate().Name,
+			Detected: r.Detected.UTC(),
+		}
+		if !r.Added() {
+			old := newJSONRate(r.Old)
+			jr.Old = &old
+		}
+		if !r.Removed() {
+			rate := newJSONRate(r.New)
+			jr.New = &rate
+		}
+		js.Revisions = append(js.Revisions, jr)
+	}
+	if summary.Gaps != (gaps.Policy{}) {
+		js.Gaps = &jsonGaps{
+			Fill: summary.Gaps.Fill.String(), MinCoverage: summary.Gaps.MinCoverage,
+			Filled: summary.Filled(), Dropped: summary.Dropped,
+		}
+	}
+	for _, a := range summary.Anomalies {
+		js.Anomalies = append(js.Anomalies, jsonAnomaly{
+			Date: a.Rate.Date.Format(jsonDate), Code: a.Code(),
+			Prev: newJSONRate(a.Prev), Rate: newJSONRate(a.Rate),
+			Return: a.Return, Reason: a.Reason, Score: a.Score,
+		})
+	}
+	if !summary.AsOf.IsZero() {
+		asOf := summary.AsOf.UTC()
+		js.AsOf = &asOf
+	}
+	if c := summary.Comparison; c != nil {
+		js.Comparison = newJSONComparison(c)
+	}
+	return js
+}
+
+func newJSONComparison(c *Comparison) *jsonComparison {
+	jc := &jsonComparison{
+		BaseFrom:   c.Base.From.Format(jsonDate),
+		BaseTo:     c.Base.To.Format(jsonDate),
+		Currencies: make([]jsonDelta, 0, len(c.Deltas)),
+		Movers:     make([]string, 0, len(c.Movers)),
+	}
+	period := func(c *stats.Currency) *jsonPeriodStats {
+		if c == nil {
+			return nil
+		}
+		return &jsonPeriodStats{Avg: c.Avg, Min: newJSONRate(c.Min), Max: newJSONRate(c.Max), Volatility: c.Volatility, Count: c.Count}
+	}
+	for _, d := range c.Deltas {
+		jd := jsonDelta{Code: d.Code(), Name: d.Name(), Base: period(d.Base), Current: period(d.Current)}
+		if d.Both() {
+			jd.Change = &jsonChange{Avg: d.Avg, Max: d.Max, Min: d.Min, Volatility: d.Volatility}
+		}
+		jc.Currencies = append(jc.Currencies, jd)
+	}
+	for _, d := range c.Movers {
+		jc.Movers = append(jc.Movers, d.Code())
+	}
+	return jc
+}
+
+func newJSONRate(r model.CurrencyRate) jsonRate {
+	code := r.CharCode
+	if code == "" {
+		code = r.ID
+	}
+	return jsonRate{Code: code, Name: r.Name, Rate: r.Rate, Date: r.Date.Format(jsonDate)}
+}
+
+func newJSONCurrency(c stats.Currency) jsonCurrency {
+	jc := jsonCurrency{
+		ID:         c.ID,
+		Code:       c.Code(),
+		Name:       c.Name,
+		First:      newJSONRate(c.First),
+		Last:       newJSONRate(c.Last),
+		Min:        newJSONRate(c.Min),
+		Max:        newJSONRate(c.Max),
+		Avg:        c.Avg,
+		Count:      c.Count,
+		Change:     c.Change,
+		Volatility: c.Volatility,
+		Filled:     c.Filled,
+		Points:     make([]jsonPoint, 0, len(c.Points)),
+	}
+	for _, p := range c.Points {
+		jc.Points = append(jc.Points, jsonPoint{Date: p.Date.Format(jsonDate), Value: p.Rate, Filled: p.Filled})
+	}
+	if c.Moves != nil {
+		jc.Moves = newJSONMoves(*c.Moves)
+	}
+	return jc
+}
+
+func newJSONMoves(m stats.Moves) *jsonMoves {
+	point := func(p model.Point) jsonPoint { return jsonPoint{Date: p.Date.Format(jsonDate), Value: p.Value} }
+	rate := func(r model.CurrencyRate) jsonPoint { return jsonPoint{Date: r.Date.Format(jsonDate), Value: r.Rate} }
+	moves := func(moves []stats.Move) []jsonMove {
+		result := make([]jsonMove, 0, len(moves))
+		for _, m := range moves {
+			result = append(result, jsonMove{From: rate(m.From), To: rate(m.To), Change: m.Change})
+		}
+		return result
+	}
+	streak := func(s stats.Streak) *jsonStreak {
+		if s.Length == 0 {
+			return nil
+		}
+		return &jsonStreak{Length: s.Length, From: rate(s.From), To: rate(s.To), Change: s.Change}
+	}
+
+	jm := &jsonMoves{Gains: moves(m.Gains), Losses: moves(m.Losses), Up: streak(m.Up), Down: streak(m.Down)}
+	if d := m.Drawdown; d.Depth > 0 {
+		jm.Drawdown = &jsonDrawdown{Depth: d.Depth, Peak: point(d.Peak), Trough: point(d.Trough)}
+	}
+	if u := m.RunUp; u.Rise > 0 {
+		jm.RunUp = &jsonRunUp{Rise: u.Rise, Trough: point(u.Trough), Peak: point(u.Peak)}
+	}
+	return jm
+}
+
+// JSONReporter записывает сводку одним JSON-документом для обработки
+// другими программами.
+type JSONReporter struct {
+	options
+}
+
+func NewJSONReporter(opts ...Option) *JSONReporter {
+	return &JSONReporter{options: applyOptions(opts)}
+}
+
+func (r *JSONReporter) Report(_ context.Context, summary Summary) error {
+	enc := json.NewEncoder(r.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(newJSONSummary(summary))
+}
+
+// WebhookReporter отправляет сводку POST-запросом в формате JSONReporter.
+type WebhookReporter struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewWebhookReporter(url string) *WebhookReporter {
+	return &WebhookReporter{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (r *WebhookReporter) Report(ctx context.Context, summary Summary) error {
+	body, err := json.Marshal(newJSONSummary(summary))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", r.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &fetcher.HTTPStatusError{StatusCode: resp.StatusCode, URL: r.url}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task3/internal/fetcher"
+	"task3/internal/gaps"
+)
+
+func TestJSONReporter(t *testing.T) {
+	summary := testSummary()
+	summary.Source = Source{Name: "Bank of Russia", URL: "http://www.cbr.ru/scripts/XML_daily.asp"}
+	summary.Revisions = testRevisions()
+	summary.Currencies[1].Points[0].Filled = true
+	summary.Currencies[1].Filled = 1
+	summary.Gaps = gaps.Policy{Fill: gaps.CarryForward}
+	summary.Anomalies = testAnomalies()
+
+	var out bytes.Buffer
+	report(t, NewJSONReporter(WithWriter(&out)), summary)
+
+	var got jsonSummary
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, out.String())
+	}
+	if got.From != "2025-10-20" || got.To != "2025-10-21" || got.Source.Name != "Bank of Russia" {
+		t.Errorf("Unexpected header: %+v", got)
+	}
+	if got.Max == nil || got.Max.Code != "EUR" || got.Max.Rate != 95 || *got.Avg != 87.525 {
+		t.Errorf("Unexpected totals: max=%+v avg=%v", got.Max, got.Avg)
+	}
+	if len(got.Currencies) != 2 || got.Currencies[1].Code != "USD" || len(got.Currencies[1].Points) != 2 {
+		t.Fatalf("Unexpected currencies: %+v", got.Currencies)
+	}
+	if p := got.Currencies[1].Points[1]; p.Date != "2025-10-21" || p.Value != 82 || p.Filled {
+		t.Errorf("Unexpected point: %+v", p)
+	}
+	if !got.Currencies[1].Points[0].Filled || got.Gaps == nil || got.Gaps.Fill != "carry" || got.Gaps.Filled != 1 {
+		t.Errorf("Filled points must be marked: %+v %+v", got.Currencies[1].Points[0], got.Gaps)
+	}
+	if len(got.Indicators) != 1 || len(got.Diagnostics) != 1 || got.Diagnostics[0].Currency != "CNY" {
+		t.Errorf("Unexpected indicators or diagnostics: %+v %+v", got.Indicators, got.Diagnostics)
+	}
+	// У добавленной позже валюты нет прежнего значения, у удалённой — нового
+	if len(got.Revisions) != 3 || got.Revisions[0].Old == nil || got.Revisions[0].Old.Rate != 82 || got.Revisions[1].Old != nil ||
+		got.Revisions[2].New != nil || got.Revisions[2].Code != "EUR" || got.Revisions[2].Name != "Euro" {
+		t.Errorf("Unexpected revisions: %+v", got.Revisions)
+	}
+	if len(got.Anomalies) != 3 || got.Anomalies[1].Reason != "nominal" || got.Anomalies[1].Prev.Rate != 86 || got.Anomalies[1].Code != "USD" {
+		t.Errorf("Unexpected anomalies: %+v", got.Anomalies)
+	}
+	if got.AsOf != nil {
+		t.Errorf("as_of must be omitted when not set: %v", got.AsOf)
+	}
+
+	// Для PerSeries общих экстремумов нет
+	out.Reset()
+	summary.PerSeries = true
+	report(t, NewJSONReporter(WithWriter(&out)), summary)
+	got = jsonSummary{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if !got.PerSeries || got.Max != nil || got.Min != nil || got.Avg != nil {
+		t.Errorf("Global extremes must be omitted for per-series summary:\n%s", out.String())
+	}
+}
+
+func TestWebhookReporter(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	report(t, NewWebhookReporter(server.URL), testSummary())
+
+	var got jsonSummary
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, body)
+	}
+	if got.Count != 4 || len(got.Currencies) != 2 {
+		t.Errorf("Unexpected payload: %s", body)
+	}
+}
+
+func TestWebhookReporter_BadStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhookReporter(server.URL).Report(context.Background(), testSummary())
+	var statusErr *fetcher.HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected *fetcher.HTTPStatusError with 502, got %v", err)
+	}
+}
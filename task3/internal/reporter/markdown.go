@@ -0,0 +1,228 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"task3/internal/i18n"
+	"task3/internal/stats"
+)
+
+// column — столбец таблицы по валютам; title — ключ каталога локали.
+type column struct {
+	title string
+	right bool
+	value func(l *i18n.Locale, c stats.Currency) string
+}
+
+var markdownColumns = map[string]column{
+	"code":       {"table.currency", false, func(_ *i18n.Locale, c stats.Currency) string { return c.Code() }},
+	"name":       {"table.name", false, func(l *i18n.Locale, c stats.Currency) string { return l.Name(c.Name) }},
+	"first":      {"table.first", true, func(l *i18n.Locale, c stats.Currency) string { return l.Number(c.First.Rate, 4) }},
+	"last":       {"table.last", true, func(l *i18n.Locale, c stats.Currency) string { return l.Number(c.Last.Rate, 4) }},
+	"change":     {"table.change", true, func(l *i18n.Locale, c stats.Currency) string { return l.Number(c.Change*100, 2) + "%" }},
+	"min":        {"table.min", true, func(l *i18n.Locale, c stats.Currency) string { return l.Number(c.Min.Rate, 4) }},
+	"max":        {"table.max", true, func(l *i18n.Locale, c stats.Currency) string { return l.Number(c.Max.Rate, 4) }},
+	"avg":        {"table.avg", true, func(l *i18n.Locale, c stats.Currency) string { return l.Number(c.Avg, 4) }},
+	"volatility": {"table.volatility", true, func(l *i18n.Locale, c stats.Currency) string { return l.Number(c.Volatility*100, 2) + "%" }},
+	"count":      {"table.count", true, func(_ *i18n.Locale, c stats.Currency) string { return fmt.Sprint(c.Count) }},
+	"filled":     {"table.filled", true, func(_ *i18n.Locale, c stats.Currency) string { return fmt.Sprint(c.Filled) }},
+}
+
+// DefaultMarkdownColumns — столбцы Markdown-таблицы, если WithColumns не задан.
+var DefaultMarkdownColumns = []string{"code", "name", "last", "change", "min", "max", "avg"}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"*", `\*`,
+	"_", `\_`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"<", "&lt;",
+	">", "&gt;",
+	"\n", " ",
+)
+
+// MarkdownReporter выводит сводку в GitHub-flavored Markdown для вики и
+// описаний merge request: заголовок с периодом и источником, таблицу по
+// валютам и сноски с пропущенными датами и диагностикой разбора.
+type MarkdownReporter struct {
+	options
+	columns []column
+}
+
+func NewMarkdownReporter(opts ...Option) (*MarkdownReporter, error) {
+	o := applyOptions(opts)
+	if err := checkSort(o.sortBy); err != nil {
+		return nil, err
+	}
+
+	names := o.columns
+	if len(names) == 0 {
+		names = DefaultMarkdownColumns
+	}
+	r := &MarkdownReporter{options: o}
+	for _, name := range names {
+		col, ok := markdownColumns[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q: use %s", name, strings.Join(columnNames(), ", "))
+		}
+		r.columns = append(r.columns, col)
+	}
+	return r, nil
+}
+
+func columnNames() []string {
+	names := make([]string, 0, len(markdownColumns))
+	for name := range markdownColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *MarkdownReporter) Report(_ context.Context, summary Summary) error {
+	l := r.locale
+	w := &errWriter{w: r.out}
+
+	w.printf("## %s\n\n", l.T("report.title", l.Date(summary.Period.From), l.Date(summary.Period.To)))
+	if src := summary.Source; src.Name != "" {
+		name := md(l.Name(src.Name))
+		if src.URL != "" {
+			name = fmt.Sprintf("[%s](%s)", name, src.URL)
+		}
+		w.printf("%s\n\n", l.T("report.source", name))
+	}
+	if !summary.AsOf.IsZero() {
+		w.printf("%s\n\n", md(asOfLine(l, summary.AsOf)))
+	}
+
+	// Для PerSeries общие экстремумы не имеют смысла — остаётся таблица
+	if !summary.PerSeries {
+		w.printf("- %s\n", md(l.T("report.max", l.Name(summary.Max.Name), l.Number(summary.Max.Rate, 4), r.unit, l.Date(summary.Max.Date))))
+		w.printf("- %s\n", md(l.T("report.min", l.Name(summary.Min.Name), l.Number(summary.Min.Rate, 4), r.unit, l.Date(summary.Min.Date))))
+		w.printf("- %s\n\n", md(l.T("report.avg", l.Number(summary.Avg, 4), r.unit)))
+	}
+
+	if len(summary.Currencies) > 0 {
+		r.table(w, sortCurrencies(summary.Currencies, r.sortBy))
+	}
+
+	if len(summary.Indicators) > 0 {
+		w.printf("\n")
+	}
+	for _, s := range summary.Indicators {
+		w.printf("- %s\n", md(seriesLine(l, s)))
+	}
+	if c := summary.Comparison; c != nil {
+		w.printf("\n%s\n\n", md(l.T("compare.title", l.Date(c.Base.From), l.Date(c.Base.To))))
+		r.compareTable(w, c.Deltas)
+		if len(c.Movers) > 0 {
+			w.printf("\n%s\n", md(moversLine(l, c.Movers)))
+		}
+	}
+	if lines := gapLines(l, summary); len(lines) > 0 {
+		w.printf("\n")
+		for _, line := range lines {
+			w.printf("- %s\n", md(line))
+		}
+	}
+
+	if len(summary.Anomalies) > 0 {
+		w.printf("\n%s\n\n", md(l.T("anomaly.title", len(summary.Anomalies))))
+		for _, a := range summary.Anomalies {
+			w.printf("- %s\n", md(strings.TrimSpace(anomalyLine(l, a))))
+		}
+	}
+
+	if len(summary.Revisions) > 0 {
+		w.printf("\n%s\n\n", md(l.T("revisions.title", len(summary.Revisions))))
+		for _, rev := range summary.Revisions {
+			w.printf("- %s\n", md(strings.TrimSpace(RevisionLine(l, rev))))
+		}
+	}
+
+	// Сноски: ссылки в тексте, определения в конце документа
+	var notes []string
+	if len(summary.MissingDates) > 0 || len(summary.Diagnostics) > 0 {
+		w.printf("\n")
+	}
+	if len(summary.MissingDates) > 0 {
+		dates := make([]string, 0, len(summary.MissingDates))
+		for _, d := range summary.MissingDates {
+			dates = append(dates, l.Date(d))
+		}
+		w.printf("%s[^missing]\n", md(l.T("report.missing", len(summary.MissingDates))))
+		notes = append(notes, "[^missing]: "+strings.Join(dates, ", "))
+	}
+	if len(summary.Diagnostics) > 0 {
+		if len(summary.MissingDates) > 0 {
+			w.printf("\n")
+		}
+		refs := make([]string, 0, len(summary.Diagnostics))
+		for i, d := range summary.Diagnostics {
+			ref := fmt.Sprintf("[^d%d]", i+1)
+			refs = append(refs, ref)
+			line := l.T("diagnostics.line", l.Date(d.Date), d.Index, d.Currency, d.Field, d.Raw, d.Reason)
+			notes = append(notes, ref+": "+md(strings.TrimSpace(line)))
+		}
+		w.printf("%s%s\n", md(l.T("diagnostics.title", len(summary.Diagnostics))), strings.Join(refs, ""))
+	}
+	if len(notes) > 0 {
+		w.printf("\n%s\n", strings.Join(notes, "\n"))
+	}
+	return w.err
+}
+
+func (r *MarkdownReporter) table(w *errWriter, currencies []stats.Currency) {
+	l := r.locale
+	header := make([]string, len(r.columns))
+	align := make([]string, len(r.columns))
+	for i, col := range r.columns {
+		header[i] = md(l.T(col.title))
+		align[i] = ":---"
+		if col.right {
+			align[i] = "---:"
+		}
+	}
+	w.printf("| %s |\n", strings.Join(header, " | "))
+	w.printf("| %s |\n", strings.Join(align, " | "))
+
+	for _, c := range currencies {
+		cells := make([]string, len(r.columns))
+		for i, col := range r.columns {
+			cells[i] = md(col.value(l, c))
+		}
+		w.printf("| %s |\n", strings.Join(cells, " | "))
+	}
+}
+
+func (r *MarkdownReporter) compareTable(w *errWriter, deltas []stats.Delta) {
+	l := r.locale
+	header := compareHeader(l)
+	align := make([]string, len(header))
+	for i, h := range header {
+		header[i] = md(h)
+		align[i] = "---:"
+		if i < 2 {
+			align[i] = ":---"
+		}
+	}
+	w.printf("| %s |\n", strings.Join(header, " | "))
+	w.printf("| %s |\n", strings.Join(align, " | "))
+	for _, d := range deltas {
+		cells := compareCells(l, d)
+		for i, c := range cells {
+			cells[i] = md(c)
+		}
+		w.printf("| %s |\n", strings.Join(cells, " | "))
+	}
+}
+
+func md(s string) string {
+	return markdownEscaper.Replace(s)
+}
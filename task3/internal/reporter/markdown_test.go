@@ -0,0 +1,84 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func markdownSummary() Summary {
+	summary := testSummary()
+	summary.Source = Source{Name: "Bank of Russia", URL: "http://www.cbr.ru/scripts/XML_daily.asp"}
+	summary.MissingDates = []time.Time{
+		time.Date(2025, 10, 18, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC),
+	}
+	return summary
+}
+
+func TestMarkdownReporter_Golden(t *testing.T) {
+	for _, lang := range []string{"ru", "en"} {
+		var out bytes.Buffer
+		r, err := NewMarkdownReporter(WithWriter(&out), WithLocale(locale(t, lang)), WithSort(SortByChange))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := r.Report(context.Background(), markdownSummary()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		golden := filepath.Join("testdata", "report."+lang+".golden.md")
+		if *update {
+			if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("Failed to read golden file (run with -update): %v", err)
+		}
+		if out.String() != string(expected) {
+			t.Errorf("%s: output differs from %s:\n%s", lang, golden, out.String())
+		}
+	}
+}
+
+func TestMarkdownReporter_Columns(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewMarkdownReporter(WithWriter(&out), WithLocale(locale(t, "en")), WithColumns("code", "volatility", "count"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), testSummary()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "| Currency | Volatility | Count |\n| :--- | ---: | ---: |\n| EUR | 0.00% | 2 |\n| USD | 0.00% | 2 |\n"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected table:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	if _, err := NewMarkdownReporter(WithColumns("code", "spread")); err == nil {
+		t.Error("Expected error for unknown column, got nil")
+	}
+	if _, err := NewMarkdownReporter(WithSort("name")); err == nil {
+		t.Error("Expected error for unknown sort, got nil")
+	}
+}
+
+func TestMarkdownReporter_Escape(t *testing.T) {
+	summary := testSummary()
+	summary.Currencies[0].Name = "A|B *C*"
+
+	var out bytes.Buffer
+	r, _ := NewMarkdownReporter(WithWriter(&out), WithColumns("name"))
+	r.Report(context.Background(), summary)
+
+	if !strings.Contains(out.String(), `| A\|B \*C\* |`) {
+		t.Errorf("Cell is not escaped:\n%s", out.String())
+	}
+}
@@ -0,0 +1,59 @@
+package reporter
+
+import (
+	"strings"
+
+	"task3/internal/i18n"
+	"task3/internal/stats"
+)
+
+func (r *ConsoleReporter) writeMoves(w *errWriter, currencies []stats.Currency) {
+	for _, c := range currencies {
+		for _, line := range movesLines(r.locale, c) {
+			w.printf("%s\n", line)
+		}
+	}
+}
+
+// movesLines — просадка и рост валюты, крупнейшие дневные изменения и
+// самые длинные серии; без анализа (-moves) строк нет.
+func movesLines(l *i18n.Locale, c stats.Currency) []string {
+	m := c.Moves
+	if m == nil {
+		return nil
+	}
+	drawdown := l.T("moves.no_drawdown")
+	if d := m.Drawdown; d.Depth > 0 {
+		drawdown = l.T("moves.drawdown", signedPercent(l, -d.Depth), l.Date(d.Peak.Date), l.Date(d.Trough.Date),
+			l.Number(d.Peak.Value, 4), l.Number(d.Trough.Value, 4))
+	}
+	runUp := l.T("moves.no_run_up")
+	if u := m.RunUp; u.Rise > 0 {
+		runUp = l.T("moves.run_up", signedPercent(l, u.Rise), l.Date(u.Trough.Date), l.Date(u.Peak.Date),
+			l.Number(u.Trough.Value, 4), l.Number(u.Peak.Value, 4))
+	}
+
+	lines := []string{l.T("moves.line", c.Code(), drawdown, runUp)}
+	if len(m.Gains) > 0 {
+		lines = append(lines, l.T("moves.gains", dayMoves(l, m.Gains)))
+	}
+	if len(m.Losses) > 0 {
+		lines = append(lines, l.T("moves.losses", dayMoves(l, m.Losses)))
+	}
+	if s := m.Up; s.Length > 0 {
+		lines = append(lines, l.T("moves.up", s.Length, l.Date(s.From.Date), l.Date(s.To.Date), signedPercent(l, s.Change)))
+	}
+	if s := m.Down; s.Length > 0 {
+		lines = append(lines, l.T("moves.down", s.Length, l.Date(s.From.Date), l.Date(s.To.Date), signedPercent(l, s.Change)))
+	}
+	return lines
+}
+
+// dayMoves — дневные изменения через запятую: дата курса и изменение.
+func dayMoves(l *i18n.Locale, moves []stats.Move) string {
+	parts := make([]string, 0, len(moves))
+	for _, m := range moves {
+		parts = append(parts, l.Date(m.To.Date)+" "+signedPercent(l, m.Change))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,92 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"task3/internal/stats"
+)
+
+// addMoves добавляет к валютам анализ движений, как app.WithMoves.
+func addMoves(currencies []stats.Currency) {
+	for i := range currencies {
+		moves := stats.ComputeMoves(currencies[i].Points, 3)
+		currencies[i].Moves = &moves
+	}
+}
+
+func TestConsoleReporter_Report_Moves(t *testing.T) {
+	var out bytes.Buffer
+	summary := testSummary()
+	summary.Indicators, summary.Diagnostics = nil, nil
+	addMoves(summary.Currencies)
+	report(t, NewConsoleReporter(WithWriter(&out)), summary)
+
+	expected := "Максимум: Euro <EU> — 95,0000 руб. на 2025-10-20\n" +
+		"Минимум: US Dollar — 80,0000 руб. на 2025-10-20\n" +
+		"Среднее значение курса: 87,5250 руб.\n" +
+		"EUR: просадка -2,00% с 2025-10-20 по 2025-10-21 (95,0000 → 93,1000), роста не было\n" +
+		"  крупнейшие дневные падения: 2025-10-21 -2,00%\n" +
+		"  самая длинная серия падения — 1, с 2025-10-20 по 2025-10-21 (-2,00%)\n" +
+		"USD: просадки не было, рост +2,50% с 2025-10-20 по 2025-10-21 (80,0000 → 82,0000)\n" +
+		"  крупнейшие дневные росты: 2025-10-21 +2,50%\n" +
+		"  самая длинная серия роста — 1, с 2025-10-20 по 2025-10-21 (+2,50%)\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestJSONReporter_Moves(t *testing.T) {
+	var out bytes.Buffer
+	summary := testSummary()
+	addMoves(summary.Currencies)
+	report(t, NewJSONReporter(WithWriter(&out)), summary)
+
+	type point struct {
+		Date  string
+		Value float64
+	}
+	var got struct {
+		Currencies []struct {
+			Code  string
+			Moves *struct {
+				Drawdown *struct {
+					Depth        float64
+					Peak, Trough point
+				}
+				RunUp  *struct{ Rise float64 } `json:"run_up"`
+				Gains  []struct{ Change float64 }
+				Losses []struct {
+					From, To point
+					Change   float64
+				}
+				Up   *struct{ Length int } `json:"up_streak"`
+				Down *struct{ Length int } `json:"down_streak"`
+			}
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Currencies) != 2 || got.Currencies[0].Moves == nil {
+		t.Fatalf("Expected moves for 2 currencies: %s", out.String())
+	}
+	eur := got.Currencies[0].Moves
+	if eur.Drawdown == nil || eur.Drawdown.Peak != (point{"2025-10-20", 95}) || eur.Drawdown.Trough.Date != "2025-10-21" || eur.RunUp != nil {
+		t.Errorf("Unexpected EUR drawdown and run-up: %+v", eur)
+	}
+	if len(eur.Gains) != 0 || len(eur.Losses) != 1 || eur.Losses[0].To != (point{"2025-10-21", 93.1}) || eur.Up != nil || eur.Down.Length != 1 {
+		t.Errorf("Unexpected EUR moves: %+v", eur)
+	}
+
+	var plain struct{ Currencies []map[string]any }
+	out.Reset()
+	report(t, NewJSONReporter(WithWriter(&out)), testSummary())
+	if err := json.Unmarshal(out.Bytes(), &plain); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := plain.Currencies[0]["moves"]; ok {
+		t.Error("Moves must be omitted unless requested")
+	}
+}
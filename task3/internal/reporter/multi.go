@@ -0,0 +1,153 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Policy определяет, что делает MultiReporter при ошибке одного из репортеров.
+type Policy int
+
+const (
+	// BestEffort доводит отчёт до всех репортеров и возвращает все ошибки
+	// разом (errors.Join).
+	BestEffort Policy = iota
+	// FailFast прекращает рассылку на первой ошибке: последовательная
+	// рассылка не вызывает оставшихся, параллельная отменяет их контекст.
+	FailFast
+)
+
+// Target — репортер в составе MultiReporter. Name попадает в текст ошибки;
+// Timeout, если задан, заменяет общий таймаут WithTimeout.
+type Target struct {
+	Name     string
+	Reporter Reporter
+	Timeout  time.Duration
+}
+
+// MultiReporter рассылает одну сводку нескольким репортерам: консоль, файл,
+// webhook и т.п.
+type MultiReporter struct {
+	targets    []Target
+	concurrent bool
+	policy     Policy
+	timeout    time.Duration
+}
+
+type MultiOption func(*MultiReporter)
+
+// Concurrently вызывает репортеры одновременно, а не по очереди.
+func Concurrently() MultiOption {
+	return func(m *MultiReporter) {
+		m.concurrent = true
+	}
+}
+
+func WithPolicy(policy Policy) MultiOption {
+	return func(m *MultiReporter) {
+		m.policy = policy
+	}
+}
+
+// WithTimeout ограничивает время каждого репортера; 0 — без ограничения.
+func WithTimeout(timeout time.Duration) MultiOption {
+	return func(m *MultiReporter) {
+		m.timeout = timeout
+	}
+}
+
+func NewMultiReporter(targets []Target, opts ...MultiOption) *MultiReporter {
+	m := &MultiReporter{targets: targets}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (m *MultiReporter) Report(ctx context.Context, summary Summary) error {
+	if m.concurrent {
+		return m.reportConcurrently(ctx, summary)
+	}
+
+	var errs []error
+	for _, t := range m.targets {
+		if err := m.report(ctx, t, summary); err != nil {
+			if m.policy == FailFast {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *MultiReporter) reportConcurrently(ctx context.Context, summary Summary) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+	errs := make([]error, len(m.targets))
+	for i, t := range m.targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := m.report(ctx, t, summary)
+			errs[i] = err
+			if err != nil && m.policy == FailFast {
+				once.Do(func() {
+					first = err
+					cancel()
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if m.policy == FailFast {
+		return first
+	}
+	// Ошибки в порядке репортеров, а не их завершения
+	return errors.Join(errs...)
+}
+
+// report вызывает репортер с его таймаутом. По истечении таймаута контекст
+// репортера отменяется и рассылка получает ошибку таймаута, но report
+// дожидается возврата репортера: иначе он писал бы в файл, который вызывающий
+// уже закрыл.
+func (m *MultiReporter) report(ctx context.Context, t Target, summary Summary) error {
+	timeout := m.timeout
+	if t.Timeout > 0 {
+		timeout = t.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", t.Name, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- t.Reporter.Report(ctx, summary)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("%s: %w", t.Name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		<-done
+		return fmt.Errorf("%s: %w", t.Name, ctx.Err())
+	}
+}
@@ -0,0 +1,160 @@
+package reporter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// funcReporter — репортер из функции для проверки рассылки.
+type funcReporter func(ctx context.Context, summary Summary) error
+
+func (f funcReporter) Report(ctx context.Context, summary Summary) error {
+	return f(ctx, summary)
+}
+
+type recorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *recorder) target(name string, err error) Target {
+	return Target{Name: name, Reporter: funcReporter(func(context.Context, Summary) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.names = append(r.names, name)
+		return err
+	})}
+}
+
+func TestMultiReporter_Sequential(t *testing.T) {
+	rec := &recorder{}
+	m := NewMultiReporter([]Target{rec.target("console", nil), rec.target("json", nil)})
+
+	if err := m.Report(context.Background(), testSummary()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(rec.names, ",") != "console,json" {
+		t.Errorf("Unexpected order: %v", rec.names)
+	}
+}
+
+func TestMultiReporter_BestEffort(t *testing.T) {
+	errA, errB := errors.New("disk full"), errors.New("bad status code: 500")
+	for _, concurrent := range []bool{false, true} {
+		rec := &recorder{}
+		opts := []MultiOption{WithPolicy(BestEffort)}
+		if concurrent {
+			opts = append(opts, Concurrently())
+		}
+		m := NewMultiReporter([]Target{rec.target("file", errA), rec.target("console", nil), rec.target("webhook", errB)}, opts...)
+
+		// Все репортеры вызваны, ошибки собраны вместе с именами
+		err := m.Report(context.Background(), testSummary())
+		if len(rec.names) != 3 {
+			t.Errorf("concurrent=%v: expected 3 calls, got %v", concurrent, rec.names)
+		}
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Fatalf("concurrent=%v: expected both errors, got %v", concurrent, err)
+		}
+		if err.Error() != "file: disk full\nwebhook: bad status code: 500" {
+			t.Errorf("concurrent=%v: unexpected error text: %q", concurrent, err.Error())
+		}
+	}
+}
+
+func TestMultiReporter_FailFastSequential(t *testing.T) {
+	rec := &recorder{}
+	failure := errors.New("disk full")
+	m := NewMultiReporter([]Target{rec.target("file", failure), rec.target("console", nil)}, WithPolicy(FailFast))
+
+	err := m.Report(context.Background(), testSummary())
+	if !errors.Is(err, failure) || err.Error() != "file: disk full" {
+		t.Errorf("Expected file error, got %v", err)
+	}
+	if len(rec.names) != 1 {
+		t.Errorf("Reporters after the failed one must not be called: %v", rec.names)
+	}
+}
+
+func TestMultiReporter_FailFastConcurrent(t *testing.T) {
+	failure := errors.New("disk full")
+	slow := Target{Name: "webhook", Reporter: funcReporter(func(ctx context.Context, _ Summary) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})}
+	m := NewMultiReporter([]Target{slow, {Name: "file", Reporter: funcReporter(func(context.Context, Summary) error {
+		return failure
+	})}}, Concurrently(), WithPolicy(FailFast))
+
+	// Ошибка файла отменяет ожидающий webhook
+	err := m.Report(context.Background(), testSummary())
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected file error, got %v", err)
+	}
+}
+
+func TestMultiReporter_Timeout(t *testing.T) {
+	rec := &recorder{}
+	// Репортер следит за контекстом — таймаут его прерывает
+	slow := Target{Name: "slow", Timeout: 10 * time.Millisecond, Reporter: funcReporter(func(ctx context.Context, _ Summary) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	})}
+	m := NewMultiReporter([]Target{slow, rec.target("console", nil)}, WithTimeout(time.Hour))
+
+	start := time.Now()
+	err := m.Report(context.Background(), testSummary())
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.HasPrefix(err.Error(), "slow: ") {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Timeout was not applied: %v", time.Since(start))
+	}
+	if len(rec.names) != 1 {
+		t.Errorf("Next reporter must still be called: %v", rec.names)
+	}
+}
+
+func TestMultiReporter_TimeoutWaitsForReporter(t *testing.T) {
+	// Репортер не следит за контекстом: после таймаута рассылка ждёт его
+	// возврата, чтобы вызывающий не закрыл файл посреди записи
+	var finished atomic.Bool
+	stuck := Target{Name: "stuck", Reporter: funcReporter(func(context.Context, Summary) error {
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+		return nil
+	})}
+	for _, opts := range [][]MultiOption{{WithTimeout(time.Millisecond)}, {WithTimeout(time.Millisecond), Concurrently()}} {
+		finished.Store(false)
+		err := NewMultiReporter([]Target{stuck}, opts...).Report(context.Background(), testSummary())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected timeout error, got %v", err)
+		}
+		if !finished.Load() {
+			t.Error("Report returned before the timed out reporter")
+		}
+	}
+}
+
+func TestMultiReporter_CancelledContext(t *testing.T) {
+	rec := &recorder{}
+	m := NewMultiReporter([]Target{rec.target("console", nil)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Report(ctx, testSummary()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context error, got %v", err)
+	}
+	if len(rec.names) != 0 {
+		t.Errorf("Reporter must not be called: %v", rec.names)
+	}
+}
@@ -0,0 +1,182 @@
+package reporter
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"task3/internal/i18n"
+	"task3/internal/portfolio"
+)
+
+// PortfolioConfig задаёт для PortfolioReporter остатки и валюту оценки (RUB,
+// буквенный код или код ЦБ; пустая — RUB). Стоимость считается с From, более
+// ранние курсы нужны только для стоимости покупки; нулевое From — с начала
+// периода сводки.
+type PortfolioConfig struct {
+	Holdings []portfolio.Holding
+	Base     string
+	From     time.Time
+}
+
+// PortfolioReporter выводит вместо статистики курсов стоимость остатков из
+// PortfolioConfig по датам периода: итог, вклад каждой валюты,
+// нереализованную курсовую разницу и максимальную просадку. Форматы:
+// console, csv и json.
+type PortfolioReporter struct {
+	options
+	cfg    PortfolioConfig
+	format string
+}
+
+func NewPortfolioReporter(format string, cfg PortfolioConfig, opts ...Option) (*PortfolioReporter, error) {
+	switch format {
+	case "console", "csv", "json":
+	default:
+		return nil, fmt.Errorf("unknown portfolio format %q: use console, csv or json", format)
+	}
+	if cfg.Base == "" {
+		cfg.Base = portfolio.RUB
+	}
+	return &PortfolioReporter{options: applyOptions(opts), cfg: cfg, format: format}, nil
+}
+
+func (r *PortfolioReporter) Report(_ context.Context, summary Summary) error {
+	from := r.cfg.From
+	if from.IsZero() {
+		from = summary.Period.From
+	}
+	v, err := portfolio.Value(r.cfg.Holdings, summary.Currencies, r.cfg.Base, from)
+	if err != nil {
+		return err
+	}
+	switch r.format {
+	case "csv":
+		return r.writeCSV(v)
+	case "json":
+		return r.writeJSON(v)
+	default:
+		return r.writeConsole(v)
+	}
+}
+
+func (r *PortfolioReporter) writeConsole(v portfolio.Valuation) error {
+	l := r.locale
+	w := &errWriter{w: r.out}
+	last := v.Points[len(v.Points)-1]
+
+	w.printf("%s\n", l.T("portfolio.title", l.Date(last.Date), l.Number(v.Value(), 2), v.Base))
+	w.printf("%s\n", l.T("portfolio.gain", l.Number(v.Cost, 2), v.Base, signedNumber(l, v.Gain, 2), v.Base, signedPercent(l, ratio(v.Gain, v.Cost))))
+	w.printf("%s\n", l.T("portfolio.positions"))
+	for _, p := range v.Positions {
+		w.printf("%s\n", l.T("portfolio.position", p.Code, l.Number(p.Amount, 2),
+			l.Number(p.Value, 2), v.Base, l.Number(p.Share*100, 2)+"%",
+			signedNumber(l, p.Gain, 2), v.Base, signedPercent(l, ratio(p.Gain, p.Cost))))
+	}
+	if d := v.Drawdown; d.Depth > 0 {
+		w.printf("%s\n", l.T("portfolio.drawdown", l.Number(d.Depth*100, 2)+"%", l.Date(d.Peak.Date), l.Date(d.Trough.Date)))
+	} else {
+		w.printf("%s\n", l.T("portfolio.no_drawdown"))
+	}
+	w.printf("%s\n", l.T("portfolio.series"))
+	for _, p := range v.Points {
+		w.printf("%s\n", l.T("portfolio.point", l.Date(p.Date), l.Number(p.Value, 2)))
+	}
+	return w.err
+}
+
+// writeCSV выводит таблицу date,total и стоимость каждой позиции по датам.
+func (r *PortfolioReporter) writeCSV(v portfolio.Valuation) error {
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	w := csv.NewWriter(r.out)
+	header := []string{"date", "total"}
+	for _, p := range v.Positions {
+		header = append(header, p.Code)
+	}
+	w.Write(header)
+	for i, point := range v.Points {
+		row := []string{point.Date.Format(jsonDate), format(point.Value)}
+		for _, p := range v.Positions {
+			row = append(row, format(p.Points[i].Value))
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
+
+type jsonPortfolioReport struct {
+	Base      string         `json:"base"`
+	Date      string         `json:"date"`
+	Value     float64        `json:"value"`
+	Cost      float64        `json:"cost"`
+	Gain      float64        `json:"gain"`
+	Drawdown  *jsonDrawdown  `json:"drawdown,omitempty"`
+	Positions []jsonPosition `json:"positions"`
+	Series    []jsonPoint    `json:"series"`
+}
+
+type jsonPosition struct {
+	Code   string  `json:"code"`
+	Name   string  `json:"name,omitempty"`
+	Amount float64 `json:"amount"`
+	Value  float64 `json:"value"`
+	Cost   float64 `json:"cost"`
+	Gain   float64 `json:"gain"`
+	Share  float64 `json:"share"`
+}
+
+type jsonDrawdown struct {
+	Depth  float64   `json:"depth"`
+	Peak   jsonPoint `json:"peak"`
+	Trough jsonPoint `json:"trough"`
+}
+
+func (r *PortfolioReporter) writeJSON(v portfolio.Valuation) error {
+	last := v.Points[len(v.Points)-1]
+	report := jsonPortfolioReport{
+		Base:  v.Base,
+		Date:  last.Date.Format(jsonDate),
+		Value: v.Value(),
+		Cost:  v.Cost,
+		Gain:  v.Gain,
+	}
+	if d := v.Drawdown; d.Depth > 0 {
+		report.Drawdown = &jsonDrawdown{
+			Depth:  d.Depth,
+			Peak:   jsonPoint{Date: d.Peak.Date.Format(jsonDate), Value: d.Peak.Value},
+			Trough: jsonPoint{Date: d.Trough.Date.Format(jsonDate), Value: d.Trough.Value},
+		}
+	}
+	for _, p := range v.Positions {
+		report.Positions = append(report.Positions, jsonPosition{
+			Code: p.Code, Name: p.Name, Amount: p.Amount, Value: p.Value, Cost: p.Cost, Gain: p.Gain, Share: p.Share,
+		})
+	}
+	for _, p := range v.Points {
+		report.Series = append(report.Series, jsonPoint{Date: p.Date.Format(jsonDate), Value: p.Value})
+	}
+
+	enc := json.NewEncoder(r.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// signedNumber — число со знаком плюс для положительных значений.
+func signedNumber(l *i18n.Locale, v float64, precision int) string {
+	s := l.Number(v, precision)
+	if v > 0 {
+		s = "+" + s
+	}
+	return s
+}
+
+func ratio(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return a / b
+}
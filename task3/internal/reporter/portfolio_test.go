@@ -0,0 +1,115 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/portfolio"
+)
+
+// testHoldings — евро до начала периода и доллары, купленные 21 октября.
+func testHoldings() []portfolio.Holding {
+	return []portfolio.Holding{
+		{Currency: "EUR", Amount: 100},
+		{Currency: "usd", Amount: 10, Acquired: time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestPortfolioReporter_Console(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewPortfolioReporter("console", PortfolioConfig{Holdings: testHoldings()}, WithWriter(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, testSummary())
+
+	want := `Портфель на 2025-10-21: 10 130,00 RUB
+Стоимость покупки 10 320,00 RUB, нереализованная курсовая разница -190,00 RUB (-1,84%)
+Позиции:
+  EUR: 100,00 ед., стоимость 9 310,00 RUB (91,91% портфеля), курсовая разница -190,00 RUB (-2,00%)
+  USD: 10,00 ед., стоимость 820,00 RUB (8,09% портфеля), курсовая разница 0,00 RUB (0,00%)
+Максимальная просадка без учёта покупок: 2,00% с 2025-10-20 по 2025-10-21
+Стоимость по датам:
+  2025-10-20  9 500,00
+  2025-10-21  10 130,00
+`
+	if got := out.String(); got != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPortfolioReporter_CSV(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewPortfolioReporter("csv", PortfolioConfig{Holdings: testHoldings(), Base: "RUB"}, WithWriter(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, testSummary())
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date,total,EUR,USD\n2025-10-20,9500.00,9500.00,0.00\n2025-10-21,10130.00,9310.00,820.00"
+	var got []string
+	for _, row := range rows {
+		got = append(got, strings.Join(row, ","))
+	}
+	if strings.Join(got, "\n") != want {
+		t.Errorf("Unexpected rows:\n%s\nwant:\n%s", strings.Join(got, "\n"), want)
+	}
+}
+
+func TestPortfolioReporter_JSON(t *testing.T) {
+	var out bytes.Buffer
+	// Оценка в долларах со второго дня: EUR/USD = 93,1/82
+	r, err := NewPortfolioReporter("json", PortfolioConfig{
+		Holdings: []portfolio.Holding{{Currency: "EUR", Amount: 82}},
+		Base:     "usd",
+		From:     time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC),
+	}, WithWriter(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report(t, r, testSummary())
+
+	var got struct {
+		Base      string
+		Date      string
+		Value     float64
+		Gain      float64
+		Drawdown  *struct{ Depth float64 }
+		Positions []struct {
+			Code  string
+			Share float64
+		}
+		Series []struct{ Value float64 }
+	}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Base != "USD" || got.Date != "2025-10-21" || len(got.Series) != 1 || got.Gain != 0 || got.Drawdown != nil {
+		t.Errorf("Unexpected report: %+v", got)
+	}
+	if got.Value < 93.0999 || got.Value > 93.1001 || len(got.Positions) != 1 || got.Positions[0].Code != "EUR" || got.Positions[0].Share != 1 {
+		t.Errorf("Unexpected value: %+v", got)
+	}
+}
+
+func TestPortfolioReporter_Errors(t *testing.T) {
+	if _, err := NewPortfolioReporter("xlsx", PortfolioConfig{}); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+	r, err := NewPortfolioReporter("console", PortfolioConfig{Holdings: []portfolio.Holding{{Currency: "GBP", Amount: 1}}}, WithWriter(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Report(context.Background(), testSummary()); err == nil || !strings.Contains(err.Error(), "no rates for GBP") {
+		t.Errorf("Expected missing rates error, got %v", err)
+	}
+}
@@ -1,25 +1,300 @@
 package reporter
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"task3/internal/i18n"
 	"task3/internal/model"
+	"time"
 )
 
-const dateLayout = "2006-01-02"
-
+// Reporter выводит сводку за период. Ошибка значит, что отчёт не доставлен:
+// не удалась запись в файл, отправка и т.п.
 type Reporter interface {
+	Report(ctx context.Context, summary Summary) error
+}
+
+// LegacyReporter — прежний интерфейс репортера: только экстремумы и среднее,
+// без контекста и ошибки.
+type LegacyReporter interface {
 	Report(max, min model.CurrencyRate, avg float64)
 }
 
+// FromLegacy приспосабливает LegacyReporter к Reporter. Для сводки по
+// отдельным рядам (PerSeries) Report вызывается по разу на ряд.
+func FromLegacy(r LegacyReporter) Reporter {
+	return legacyReporter{r}
+}
+
+type legacyReporter struct {
+	r LegacyReporter
+}
+
+func (l legacyReporter) Report(ctx context.Context, summary Summary) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if summary.PerSeries {
+		for _, c := range summary.Currencies {
+			l.r.Report(c.Max, c.Min, c.Avg)
+		}
+		return nil
+	}
+	l.r.Report(summary.Max, summary.Min, summary.Avg)
+	return nil
+}
+
+type options struct {
+	out    io.Writer
+	locale *i18n.Locale
+	unit   string
+
+	// Только для TerminalReporter и MarkdownReporter
+	width   int
+	color   bool
+	sortBy  string
+	charts  []string
+	columns []string
+
+	// Только для CorrelationReporter
+	pair   [2]string
+	window int
+}
+
+// Option настраивает вывод репортеров: куда писать, язык и единицу курса.
+type Option func(*options)
+
+func WithWriter(w io.Writer) Option {
+	return func(o *options) {
+		o.out = w
+	}
+}
+
+func WithLocale(locale *i18n.Locale) Option {
+	return func(o *options) {
+		o.locale = locale
+	}
+}
+
+// WithUnit задаёт единицу курса вместо рубля, например базовую валюту ЕЦБ.
+func WithUnit(unit string) Option {
+	return func(o *options) {
+		o.unit = unit
+	}
+}
+
+// WithWidth задаёт ширину вывода в символах для TerminalReporter.
+func WithWidth(width int) Option {
+	return func(o *options) {
+		o.width = width
+	}
+}
+
+// WithColor включает ANSI-цвета в TerminalReporter.
+func WithColor(color bool) Option {
+	return func(o *options) {
+		o.color = color
+	}
+}
+
+// WithSort задаёт порядок строк таблицы по валютам: SortByChange,
+// SortByVolatility или SortByCode.
+func WithSort(by string) Option {
+	return func(o *options) {
+		o.sortBy = by
+	}
+}
+
+// WithCharts выбирает валюты (буквенный код или код ЦБ), для которых
+// TerminalReporter рисует график.
+func WithCharts(codes ...string) Option {
+	return func(o *options) {
+		o.charts = append(o.charts, codes...)
+	}
+}
+
+// WithColumns выбирает столбцы таблицы MarkdownReporter, например "code",
+// "change", "volatility".
+func WithColumns(columns ...string) Option {
+	return func(o *options) {
+		o.columns = append(o.columns, columns...)
+	}
+}
+
+// WithPair добавляет к CorrelationReporter скользящую корреляцию валют a и b
+// в окне из window дат.
+func WithPair(a, b string, window int) Option {
+	return func(o *options) {
+		o.pair = [2]string{a, b}
+		o.window = window
+	}
+}
+
+func applyOptions(opts []Option) options {
+	ru, _ := i18n.Get("ru")
+	o := options{
+		out:    os.Stdout,
+		locale: ru,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.unit == "" {
+		o.unit = o.locale.T("unit.rub")
+	}
+	return o
+}
+
 type ConsoleReporter struct {
+	options
+}
+
+func NewConsoleReporter(opts ...Option) *ConsoleReporter {
+	return &ConsoleReporter{options: applyOptions(opts)}
+}
+
+func (r *ConsoleReporter) Report(_ context.Context, summary Summary) error {
+	w := &errWriter{w: r.out}
+	if !summary.AsOf.IsZero() {
+		w.printf("%s\n", asOfLine(r.locale, summary.AsOf))
+	}
+	r.writeExtremes(w, summary)
+	for _, s := range summary.Indicators {
+		w.printf("%s\n", seriesLine(r.locale, s))
+	}
+	r.writeMoves(w, summary.Currencies)
+	if summary.Comparison != nil {
+		r.writeComparison(w, summary.Comparison)
+	}
+	r.writeGaps(w, summary)
+	if len(summary.Anomalies) > 0 {
+		r.writeAnomalies(w, summary.Anomalies)
+	}
+	if len(summary.Diagnostics) > 0 {
+		r.writeDiagnostics(w, summary.Diagnostics)
+	}
+	if len(summary.Revisions) > 0 {
+		r.writeRevisions(w, summary.Revisions)
+	}
+	return w.err
+}
+
+// writeExtremes выводит максимум, минимум и среднее — по всем валютам или,
+// для PerSeries, по каждому ряду.
+func (r *ConsoleReporter) writeExtremes(w *errWriter, summary Summary) {
+	if !summary.PerSeries {
+		r.writeStats(w, summary.Max, summary.Min, summary.Avg)
+		return
+	}
+	for _, c := range summary.Currencies {
+		r.writeStats(w, c.Max, c.Min, c.Avg)
+	}
+}
+
+func (r *ConsoleReporter) writeStats(w *errWriter, maxRate, minRate model.CurrencyRate, avg float64) {
+	l := r.locale
+	w.printf("%s\n", l.T("report.max", l.Name(maxRate.Name), l.Number(maxRate.Rate, 4), r.unit, l.Date(maxRate.Date)))
+	w.printf("%s\n", l.T("report.min", l.Name(minRate.Name), l.Number(minRate.Rate, 4), r.unit, l.Date(minRate.Date)))
+	w.printf("%s\n", l.T("report.avg", l.Number(avg, 4), r.unit))
+}
+
+func (r *ConsoleReporter) writeDiagnostics(w *errWriter, diagnostics []model.Diagnostic) {
+	l := r.locale
+	w.printf("%s\n", l.T("diagnostics.title", len(diagnostics)))
+	for _, d := range diagnostics {
+		w.printf("%s\n", l.T("diagnostics.line", l.Date(d.Date), d.Index, d.Currency, d.Field, d.Raw, d.Reason))
+	}
+}
+
+// writeGaps сообщает, сколько курсов восстановлено и какие валюты
+// исключены политикой пропусков.
+func (r *ConsoleReporter) writeGaps(w *errWriter, summary Summary) {
+	for _, line := range gapLines(r.locale, summary) {
+		w.printf("%s\n", line)
+	}
+}
+
+func gapLines(l *i18n.Locale, summary Summary) []string {
+	var lines []string
+	if n := summary.Filled(); n > 0 {
+		lines = append(lines, l.T("gaps.filled", n, l.T("gaps."+summary.Gaps.Fill.String())))
+	}
+	if len(summary.Dropped) > 0 {
+		lines = append(lines, l.T("gaps.dropped", l.Number(summary.Gaps.MinCoverage*100, 0)+"%", strings.Join(summary.Dropped, ", ")))
+	}
+	return lines
+}
+
+func (r *ConsoleReporter) writeAnomalies(w *errWriter, anomalies []model.Anomaly) {
+	w.printf("%s\n", r.locale.T("anomaly.title", len(anomalies)))
+	for _, a := range anomalies {
+		w.printf("%s\n", anomalyLine(r.locale, a))
+	}
+}
+
+func anomalyLine(l *i18n.Locale, a model.Anomaly) string {
+	change := l.Number((math.Exp(a.Return)-1)*100, 2) + "%"
+	if a.Return > 0 {
+		change = "+" + change
+	}
+	return l.T("anomaly.line", l.Date(a.Rate.Date), a.Code(), l.Number(a.Prev.Rate, 4), l.Number(a.Rate.Rate, 4), change, anomalyReason(l, a))
+}
+
+func anomalyReason(l *i18n.Locale, a model.Anomaly) string {
+	if a.Reason == model.AnomalyNominal {
+		return l.T("anomaly.nominal", l.Number(a.Score, 2))
+	}
+	return l.T("anomaly."+a.Reason, l.Number(math.Abs(a.Score), 1))
+}
+
+func (r *ConsoleReporter) writeRevisions(w *errWriter, revisions []model.Revision) {
+	w.printf("%s\n", r.locale.T("revisions.title", len(revisions)))
+	for _, rev := range revisions {
+		w.printf("%s\n", RevisionLine(r.locale, rev))
+	}
+}
+
+// RevisionLine — строка исправления курса, общая для отчётов и подкоманды
+// revisions.
+func RevisionLine(l *i18n.Locale, r model.Revision) string {
+	switch {
+	case r.Removed():
+		return l.T("revisions.removed", l.Date(r.Day), r.Code(), l.Number(r.Old.Rate, 4), l.Date(r.Detected))
+	case r.Added():
+		return l.T("revisions.added", l.Date(r.Day), r.Code(), l.Number(r.New.Rate, 4), l.Date(r.Detected))
+	}
+	return l.T("revisions.changed", l.Date(r.Day), r.Code(), l.Number(r.Old.Rate, 4), l.Number(r.New.Rate, 4), l.Date(r.Detected))
+}
+
+func asOfLine(l *i18n.Locale, asOf time.Time) string {
+	return l.T("report.as_of", l.Date(asOf.UTC()), asOf.UTC().Format("15:04"))
+}
+
+// seriesLine — строка показателя: последнее значение, минимум и максимум.
+func seriesLine(l *i18n.Locale, s model.Series) string {
+	if len(s.Points) == 0 {
+		return l.T("series.empty", l.Name(s.Name))
+	}
+	last, minP, maxP := s.Points[len(s.Points)-1], minPoint(s.Points), maxPoint(s.Points)
+	return l.T("series.line",
+		l.Name(s.Name), l.Number(last.Value, 2), s.Unit, l.Date(last.Date),
+		l.Number(minP.Value, 2), s.Unit, l.Date(minP.Date),
+		l.Number(maxP.Value, 2), s.Unit, l.Date(maxP.Date))
 }
 
-func NewConsoleReporter() *ConsoleReporter {
-	return &ConsoleReporter{}
+// errWriter запоминает первую ошибку записи, чтобы не проверять каждую строку.
+type errWriter struct {
+	w   io.Writer
+	err error
 }
 
-func (r *ConsoleReporter) Report(maxRate, minRate model.CurrencyRate, avg float64) {
-	fmt.Printf("Максимум: %s — %.4f руб. на %s\n", maxRate.Name, maxRate.Rate, maxRate.Date.Format(dateLayout))
-	fmt.Printf("Минимум: %s — %.4f руб. на %s\n", minRate.Name, minRate.Rate, minRate.Date.Format(dateLayout))
-	fmt.Printf("Среднее значение курса: %.4f руб.\n", avg)
+func (w *errWriter) printf(format string, args ...any) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.w, format, args...)
 }
@@ -0,0 +1,257 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/gaps"
+	"task3/internal/i18n"
+	"task3/internal/model"
+)
+
+func locale(t *testing.T, lang string) *i18n.Locale {
+	t.Helper()
+	l, err := i18n.Get(lang)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func report(t *testing.T, r Reporter, summary Summary) {
+	t.Helper()
+	if err := r.Report(context.Background(), summary); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+var (
+	maxRate = model.CurrencyRate{Name: "US Dollar", Rate: 95.5, Date: time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)}
+	minRate = model.CurrencyRate{Name: "Indonesian Rupiah", Rate: 0.0058, Date: time.Date(2025, 8, 15, 0, 0, 0, 0, time.UTC)}
+)
+
+func TestConsoleReporter_Report_Russian(t *testing.T) {
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out)), Summary{Max: maxRate, Min: minRate, Avg: 42.1234})
+
+	expected := "Максимум: US Dollar — 95,5000 руб. на 2025-10-20\n" +
+		"Минимум: Indonesian Rupiah — 0,0058 руб. на 2025-08-15\n" +
+		"Среднее значение курса: 42,1234 руб.\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestConsoleReporter_Report_English(t *testing.T) {
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out), WithLocale(locale(t, "en"))), Summary{Max: maxRate, Min: minRate, Avg: 1042.1234})
+
+	expected := "Maximum: US Dollar — 95.5000 RUB on 2025-10-20\n" +
+		"Minimum: Indonesian Rupiah — 0.0058 RUB on 2025-08-15\n" +
+		"Average rate: 1,042.1234 RUB\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestConsoleReporter_Report_Unit(t *testing.T) {
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out), WithLocale(locale(t, "en")), WithUnit("EUR")), Summary{Max: maxRate, Min: minRate, Avg: 1})
+
+	if !bytes.Contains(out.Bytes(), []byte("95.5000 EUR on")) {
+		t.Errorf("Expected EUR unit, got:\n%s", out.String())
+	}
+}
+
+func TestConsoleReporter_Report_Indicators(t *testing.T) {
+	series := model.Series{Name: "Key rate", Unit: "%", Points: []model.Point{
+		{Date: time.Date(2025, 7, 28, 0, 0, 0, 0, time.UTC), Value: 18},
+		{Date: time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC), Value: 17},
+		{Date: time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC), Value: 16.5},
+	}}
+
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out)), Summary{Max: maxRate, Min: minRate, Indicators: []model.Series{series}})
+
+	expected := "Максимум: US Dollar — 95,5000 руб. на 2025-10-20\n" +
+		"Минимум: Indonesian Rupiah — 0,0058 руб. на 2025-08-15\n" +
+		"Среднее значение курса: 0,0000 руб.\n" +
+		"Ключевая ставка: 16,50% на 2025-10-20 (минимум 16,50% на 2025-10-20, максимум 18,00% на 2025-07-28)\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+
+	out.Reset()
+	report(t, NewConsoleReporter(WithWriter(&out), WithLocale(locale(t, "en"))), Summary{Indicators: []model.Series{{Name: "Key rate"}}})
+	if !strings.HasSuffix(out.String(), "\nKey rate: no data for the period\n") {
+		t.Errorf("Unexpected output: %q", out.String())
+	}
+}
+
+func TestConsoleReporter_Report_Diagnostics(t *testing.T) {
+	diagnostics := []model.Diagnostic{
+		{Date: time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC), Index: 3, Currency: "EUR", Field: "Value", Raw: "-", Reason: "invalid number"},
+	}
+
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out)), Summary{Max: maxRate, Min: minRate, Avg: 1, Diagnostics: diagnostics})
+
+	expected := "Максимум: US Dollar — 95,5000 руб. на 2025-10-20\n" +
+		"Минимум: Indonesian Rupiah — 0,0058 руб. на 2025-08-15\n" +
+		"Среднее значение курса: 1,0000 руб.\n" +
+		"Пропущено записей: 1\n  2025-10-22 #3 EUR: Value=\"-\" — invalid number\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func testRevisions() []model.Revision {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	usd := model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "US Dollar", Date: day(21)}
+	old, revised := usd, usd
+	old.Rate, revised.Rate = 82, 81.5
+	return []model.Revision{
+		{Day: day(21), Old: old, New: revised, Detected: day(22).Add(10 * time.Hour)},
+		{Day: day(21), New: model.CurrencyRate{ID: "R01375", CharCode: "CNY", Name: "Yuan", Rate: 11.2, Date: day(21)}, Detected: day(23)},
+		{Day: day(21), Old: model.CurrencyRate{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: 95, Date: day(21)}, Detected: day(24)},
+	}
+}
+
+func TestConsoleReporter_Report_Revisions(t *testing.T) {
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out)), Summary{
+		Max: maxRate, Min: minRate, Avg: 1,
+		Revisions: testRevisions(),
+		AsOf:      time.Date(2025, 10, 22, 9, 30, 0, 0, time.FixedZone("MSK", 3*3600)),
+	})
+
+	expected := "Данные по состоянию на 2025-10-22 06:30 UTC\n" +
+		"Максимум: US Dollar — 95,5000 руб. на 2025-10-20\n" +
+		"Минимум: Indonesian Rupiah — 0,0058 руб. на 2025-08-15\n" +
+		"Среднее значение курса: 1,0000 руб.\n" +
+		"Исправления курсов: 3\n" +
+		"  2025-10-21 USD: 82,0000 → 81,5000 (обнаружено 2025-10-22)\n" +
+		"  2025-10-21 CNY: добавлен курс 11,2000 (обнаружено 2025-10-23)\n" +
+		"  2025-10-21 EUR: курс 95,0000 удалён (обнаружено 2025-10-24)\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestConsoleReporter_Report_Gaps(t *testing.T) {
+	summary := testSummary()
+	summary.Indicators, summary.Diagnostics = nil, nil
+	summary.Gaps = gaps.Policy{Fill: gaps.CarryForward, MinCoverage: 0.75}
+	summary.Dropped = []string{"CNY", "JPY"}
+	summary.Currencies[1].Filled = 3
+
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out)), summary)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	expected := []string{
+		"Заполнено пропусков: 3 (перенос последнего курса)",
+		"Исключены из-за покрытия ниже 75%: CNY, JPY",
+	}
+	if len(lines) != 5 || lines[3] != expected[0] || lines[4] != expected[1] {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
+
+func testAnomalies() []model.Anomaly {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	usd := func(d int, rate float64) model.CurrencyRate {
+		return model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: rate, Date: day(d)}
+	}
+	return []model.Anomaly{
+		{Prev: usd(20, 80), Rate: usd(21, 86), Return: math.Log(86.0 / 80), Reason: model.AnomalyZScore, Score: 6.31},
+		{Prev: usd(21, 86), Rate: usd(22, 8.6), Return: math.Log(0.1), Reason: model.AnomalyNominal, Score: 0.1},
+		{Prev: usd(22, 8.6), Rate: usd(23, 80), Return: math.Log(80 / 8.6), Reason: model.AnomalyHampel, Score: -3.5},
+	}
+}
+
+func TestConsoleReporter_Report_Anomalies(t *testing.T) {
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out)), Summary{Max: maxRate, Min: minRate, Avg: 1, Anomalies: testAnomalies()})
+
+	expected := "Максимум: US Dollar — 95,5000 руб. на 2025-10-20\n" +
+		"Минимум: Indonesian Rupiah — 0,0058 руб. на 2025-08-15\n" +
+		"Среднее значение курса: 1,0000 руб.\n" +
+		"Аномалии: 3\n" +
+		"  2025-10-21 USD: 80,0000 → 86,0000 (+7,50%) — скачок 6,3σ\n" +
+		"  2025-10-22 USD: 86,0000 → 8,6000 (-90,00%) — курс изменился в 0,10 раза — похоже на смену номинала или ошибку разбора\n" +
+		"  2025-10-23 USD: 8,6000 → 80,0000 (+830,23%) — выброс 3,5 MAD от медианы соседних дней\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestConsoleReporter_Report_PerSeries(t *testing.T) {
+	summary := testSummary()
+	summary.PerSeries = true
+
+	var out bytes.Buffer
+	report(t, NewConsoleReporter(WithWriter(&out), WithLocale(locale(t, "en"))), Summary{PerSeries: true, Currencies: summary.Currencies})
+
+	// Экстремумы по каждому ряду вместо общих
+	expected := "Maximum: Euro <EU> — 95.0000 RUB on 2025-10-20\n" +
+		"Minimum: Euro <EU> — 93.1000 RUB on 2025-10-21\n" +
+		"Average rate: 94.0500 RUB\n" +
+		"Maximum: US Dollar — 82.0000 RUB on 2025-10-21\n" +
+		"Minimum: US Dollar — 80.0000 RUB on 2025-10-20\n" +
+		"Average rate: 81.0000 RUB\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestConsoleReporter_Report_WriteError(t *testing.T) {
+	err := NewConsoleReporter(WithWriter(failingWriter{})).Report(context.Background(), testSummary())
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("Expected write error, got %v", err)
+	}
+}
+
+type legacyMock struct {
+	calls []float64
+}
+
+func (m *legacyMock) Report(_, _ model.CurrencyRate, avg float64) {
+	m.calls = append(m.calls, avg)
+}
+
+func TestFromLegacy(t *testing.T) {
+	legacy := &legacyMock{}
+	r := FromLegacy(legacy)
+
+	report(t, r, testSummary())
+	if len(legacy.calls) != 1 || legacy.calls[0] != 87.525 {
+		t.Errorf("Expected one call with global avg, got %v", legacy.calls)
+	}
+
+	// По вызову на ряд для PerSeries
+	legacy.calls = nil
+	summary := testSummary()
+	summary.PerSeries = true
+	report(t, r, summary)
+	if len(legacy.calls) != 2 || legacy.calls[0] != 94.05 || legacy.calls[1] != 81 {
+		t.Errorf("Expected a call per series, got %v", legacy.calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Report(ctx, testSummary()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context error, got %v", err)
+	}
+}
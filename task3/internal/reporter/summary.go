@@ -0,0 +1,107 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"task3/internal/gaps"
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+// Summary — данные отчёта за период, которые получает Reporter. Это же модель
+// данных шаблонов (-template): поля доступны как {{.Max.Rate}},
+// {{range .Currencies}} и т.д.
+type Summary struct {
+	// Period — запрошенный период, включительно.
+	Period Period
+	// Source — откуда получены курсы.
+	Source Source
+	// Max, Min, Avg, Count — экстремумы и среднее по всем валютам и дням.
+	// Не используются, если PerSeries.
+	Max   model.CurrencyRate
+	Min   model.CurrencyRate
+	Avg   float64
+	Count int
+	// Currencies — статистика по каждой валюте, упорядочена по коду.
+	Currencies []stats.Currency
+	// PerSeries — ряды несопоставимы между собой (драгметаллы), и отчёт
+	// выводит экстремумы по каждому ряду вместо общих.
+	PerSeries bool
+	// Indicators — ряды показателей (ключевая ставка и т.п.) за тот же период.
+	Indicators []model.Series
+	// Diagnostics — записи, пропущенные нестрогим разбором.
+	Diagnostics []model.Diagnostic
+	// MissingDates — запрошенные даты, за которые источник не вернул курсов.
+	MissingDates []time.Time
+	// Revisions — исправления курсов за период, найденные повторной
+	// загрузкой (-store).
+	Revisions []model.Revision
+	// AsOf — статистика по состоянию знаний на этот момент; нулевое
+	// значение — по последним данным.
+	AsOf time.Time
+	// Gaps — политика пропусков, по которой дополнены ряды; заполненные
+	// курсы отмечены model.CurrencyRate.Filled.
+	Gaps gaps.Policy
+	// Dropped — коды валют, исключённых из-за неполных данных.
+	Dropped []string
+	// Anomalies — подозрительные дневные изменения курсов по датам.
+	Anomalies []model.Anomaly
+	// Comparison — сравнение с базовым периодом; nil, если оно не
+	// запрошено.
+	Comparison *Comparison
+}
+
+// Filled — сколько курсов во всех валютах восстановлено политикой пропусков.
+func (s Summary) Filled() int {
+	var n int
+	for _, c := range s.Currencies {
+		n += c.Filled
+	}
+	return n
+}
+
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Source описывает источник курсов; Name переводится каталогом локали
+// ("name:Bank of Russia").
+type Source struct {
+	Name string
+	URL  string
+}
+
+const (
+	SortByChange     = "change"
+	SortByVolatility = "volatility"
+	SortByCode       = "code"
+)
+
+func checkSort(by string) error {
+	switch by {
+	case "", SortByChange, SortByVolatility, SortByCode:
+		return nil
+	default:
+		return fmt.Errorf("unknown sort %q: use %s, %s or %s", by, SortByChange, SortByVolatility, SortByCode)
+	}
+}
+
+// sortCurrencies возвращает копию списка валют в порядке by; пустой by —
+// по коду.
+func sortCurrencies(currencies []stats.Currency, by string) []stats.Currency {
+	result := append([]stats.Currency(nil), currencies...)
+	sort.SliceStable(result, func(i, j int) bool {
+		switch by {
+		case SortByChange:
+			return result[i].Change > result[j].Change
+		case SortByVolatility:
+			return result[i].Volatility > result[j].Volatility
+		default:
+			return result[i].Code() < result[j].Code()
+		}
+	})
+	return result
+}
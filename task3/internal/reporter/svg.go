@@ -0,0 +1,166 @@
+package reporter
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"task3/internal/model"
+)
+
+const (
+	sparkWidth  = 120
+	sparkHeight = 24
+
+	chartWidth  = 640
+	chartHeight = 240
+	chartLeft   = 70
+	chartRight  = 20
+	chartTop    = 24
+	chartBottom = 40
+
+	lineColor = "#1f77b4"
+	minColor  = "#d62728"
+	maxColor  = "#2ca02c"
+	gridColor = "#ddd"
+)
+
+// plot переводит точки ряда в координаты прямоугольника. X пропорционален
+// дате, а не номеру точки, чтобы пропуски (выходные, праздники) не сжимали
+// ось времени.
+type plot struct {
+	left, top, width, height float64
+	from, to                 time.Time
+	min, max                 float64
+}
+
+func newPlot(points []model.Point, left, top, width, height float64) plot {
+	p := plot{left: left, top: top, width: width, height: height}
+	p.from, p.to = points[0].Date, points[0].Date
+	p.min, p.max = points[0].Value, points[0].Value
+	for _, pt := range points[1:] {
+		if pt.Date.Before(p.from) {
+			p.from = pt.Date
+		}
+		if pt.Date.After(p.to) {
+			p.to = pt.Date
+		}
+		p.min = min(p.min, pt.Value)
+		p.max = max(p.max, pt.Value)
+	}
+	return p
+}
+
+func (p plot) x(t time.Time) float64 {
+	span := p.to.Sub(p.from)
+	if span <= 0 {
+		return p.left + p.width/2
+	}
+	return p.left + p.width*float64(t.Sub(p.from))/float64(span)
+}
+
+func (p plot) y(v float64) float64 {
+	if p.max == p.min {
+		return p.top + p.height/2
+	}
+	return p.top + p.height*(p.max-v)/(p.max-p.min)
+}
+
+func (p plot) polyline(points []model.Point) string {
+	coords := make([]string, 0, len(points))
+	for _, pt := range points {
+		coords = append(coords, coord(p.x(pt.Date))+","+coord(p.y(pt.Value)))
+	}
+	return strings.Join(coords, " ")
+}
+
+// coord округляет координату до десятых, чтобы разметка была стабильной
+// и компактной.
+func coord(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+// sparkline рисует ряд без осей и подписей для ячейки таблицы; последняя
+// точка отмечена.
+func sparkline(points []model.Point) string {
+	if len(points) == 0 {
+		return ""
+	}
+	p := newPlot(points, 2, 2, sparkWidth-4, sparkHeight-4)
+	last := points[len(points)-1]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" class="sparkline" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		sparkWidth, sparkHeight, sparkWidth, sparkHeight)
+	fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="1" points="%s"/>`, lineColor, p.polyline(points))
+	fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="1.5" fill="%s"/>`, coord(p.x(last.Date)), coord(p.y(last.Value)), lineColor)
+	b.WriteString(`</svg>`)
+	return b.String()
+}
+
+// lineChart рисует ряд с подписями шкалы и отмеченными минимумом и максимумом.
+// value и date форматируют подписи (в отчёте — по локали).
+func lineChart(title string, points []model.Point, value func(float64) string, date func(time.Time) string) string {
+	if len(points) == 0 {
+		return ""
+	}
+	p := newPlot(points, chartLeft, chartTop, chartWidth-chartLeft-chartRight, chartHeight-chartTop-chartBottom)
+	lo, hi := minPoint(points), maxPoint(points)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" class="chart" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		chartWidth, chartHeight, chartWidth, chartHeight, html.EscapeString(title))
+	fmt.Fprintf(&b, "\n<title>%s</title>", html.EscapeString(title))
+
+	// Шкала: линии и подписи максимума и минимума, даты начала и конца
+	for _, v := range []float64{p.max, p.min} {
+		y := coord(p.y(v))
+		fmt.Fprintf(&b, "\n"+`<line x1="%d" y1="%s" x2="%d" y2="%s" stroke="%s"/>`, chartLeft, y, chartWidth-chartRight, y, gridColor)
+		fmt.Fprintf(&b, "\n"+`<text x="%d" y="%s" font-size="11" text-anchor="end" dominant-baseline="middle">%s</text>`,
+			chartLeft-6, y, html.EscapeString(value(v)))
+	}
+	baseline := coord(float64(chartHeight - 6))
+	fmt.Fprintf(&b, "\n"+`<text x="%d" y="%s" font-size="11" text-anchor="start">%s</text>`,
+		chartLeft, baseline, html.EscapeString(date(p.from)))
+	fmt.Fprintf(&b, "\n"+`<text x="%d" y="%s" font-size="11" text-anchor="end">%s</text>`,
+		chartWidth-chartRight, baseline, html.EscapeString(date(p.to)))
+
+	fmt.Fprintf(&b, "\n"+`<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`, lineColor, p.polyline(points))
+	// Восстановленные политикой пропусков значения — пустыми кружками
+	for _, pt := range points {
+		if pt.Filled {
+			fmt.Fprintf(&b, "\n"+`<circle class="filled" cx="%s" cy="%s" r="2" fill="#fff" stroke="%s"/>`, coord(p.x(pt.Date)), coord(p.y(pt.Value)), lineColor)
+		}
+	}
+
+	annotate(&b, p, hi, maxColor, -8, value, date)
+	annotate(&b, p, lo, minColor, 16, value, date)
+
+	b.WriteString("\n</svg>")
+	return b.String()
+}
+
+// annotate отмечает точку и подписывает её значение и дату; dy — смещение
+// подписи (над максимумом, под минимумом).
+func annotate(b *strings.Builder, p plot, pt model.Point, color string, dy float64, value func(float64) string, date func(time.Time) string) {
+	x, y := p.x(pt.Date), p.y(pt.Value)
+	// Подпись у правого края выравнивается по концу, чтобы не уйти за рамку
+	anchor := "start"
+	if x > p.left+p.width/2 {
+		anchor = "end"
+	}
+	fmt.Fprintf(b, "\n"+`<circle cx="%s" cy="%s" r="3" fill="%s"/>`, coord(x), coord(y), color)
+	fmt.Fprintf(b, "\n"+`<text x="%s" y="%s" font-size="11" fill="%s" text-anchor="%s">%s (%s)</text>`,
+		coord(x), coord(y+dy), color, anchor, html.EscapeString(value(pt.Value)), html.EscapeString(date(pt.Date)))
+}
+
+// ratePoints переводит курсы валюты в точки ряда для графиков.
+func ratePoints(rates []model.CurrencyRate) []model.Point {
+	points := make([]model.Point, 0, len(rates))
+	for _, r := range rates {
+		points = append(points, model.Point{Date: r.Date, Value: r.Rate, Filled: r.Filled})
+	}
+	return points
+}
@@ -0,0 +1,164 @@
+package reporter
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// TemplateReporter выводит Summary по пользовательскому шаблону text/template
+// или html/template.
+type TemplateReporter struct {
+	options
+	tmpl executor
+}
+
+// NewTemplateReporter загружает шаблон по пути или по имени встроенного
+// шаблона (console.tmpl, console.html, report.html); файл на диске важнее. Файлы .html и .htm исполняются через
+// html/template с экранированием, остальные — через text/template.
+func NewTemplateReporter(nameOrPath string, opts ...Option) (*TemplateReporter, error) {
+	r := &TemplateReporter{options: applyOptions(opts)}
+
+	src, err := readTemplate(nameOrPath)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(nameOrPath)
+	funcs := r.funcs()
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".html", ".htm":
+		r.tmpl, err = htmltemplate.New(name).Funcs(funcs).Parse(src)
+	default:
+		r.tmpl, err = texttemplate.New(name).Funcs(funcs).Parse(src)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", nameOrPath, err)
+	}
+	return r, nil
+}
+
+// readTemplate читает шаблон с диска; встроенный берётся, только если имя
+// без каталога и такого файла нет — console.tmpl в рабочем каталоге
+// перекрывает встроенный.
+func readTemplate(nameOrPath string) (string, error) {
+	data, err := os.ReadFile(nameOrPath)
+	if err == nil {
+		return string(data), nil
+	}
+	if errors.Is(err, fs.ErrNotExist) && !strings.ContainsAny(nameOrPath, `/\`) {
+		if data, berr := builtinTemplates.ReadFile("templates/" + nameOrPath); berr == nil {
+			return string(data), nil
+		}
+	}
+	return "", fmt.Errorf("failed to read template: %w", err)
+}
+
+func (r *TemplateReporter) Report(_ context.Context, summary Summary) error {
+	return r.tmpl.Execute(r.out, summary)
+}
+
+// funcs — функции, доступные в шаблонах:
+//
+//	tr key args...       сообщение каталога локали (i18n)
+//	name s               перевод названия из данных ("Gold" → "Золото")
+//	number v decimals    число с разделителями локали
+//	percent v decimals   доля как процент: 0.0123 → "1,23%"
+//	date t               дата в формате локали
+//	dateFormat layout t  дата в произвольном формате Go
+//	unit                 единица курса ("руб." или базовая валюта)
+//	lang                 код языка локали
+//	last, minPoint, maxPoint points  точки ряда показателя
+//	add, sub a b         целочисленная арифметика
+//	join list sep        строки через разделитель
+//	anomaly a            строка аномалии, как в ConsoleReporter
+//	revision r           строка исправления курса, как в ConsoleReporter
+//	compare d            строка сравнения валюты, как в ConsoleReporter
+//	movers deltas        строка с наибольшими изменениями среднего
+//	compareHeader        заголовки таблицы сравнения
+//	compareCells d       ячейки строки таблицы сравнения
+//	points rates         курсы валюты как точки ряда
+//	sparkline points     SVG-спарклайн для таблицы
+//	lineChart title points decimals  SVG-график с минимумом и максимумом
+func (r *TemplateReporter) funcs() map[string]any {
+	l := r.locale
+	return map[string]any{
+		"tr":     l.T,
+		"name":   l.Name,
+		"number": l.Number,
+		"percent": func(v float64, decimals int) string {
+			return l.Number(v*100, decimals) + "%"
+		},
+		"date":          l.Date,
+		"dateFormat":    func(layout string, t time.Time) string { return t.Format(layout) },
+		"unit":          func() string { return r.unit },
+		"lang":          func() string { return l.Lang },
+		"last":          func(points []model.Point) model.Point { return points[len(points)-1] },
+		"minPoint":      minPoint,
+		"maxPoint":      maxPoint,
+		"add":           func(a, b int) int { return a + b },
+		"sub":           func(a, b int) int { return a - b },
+		"join":          strings.Join,
+		"anomaly":       func(a model.Anomaly) string { return anomalyLine(l, a) },
+		"revision":      func(r model.Revision) string { return RevisionLine(l, r) },
+		"compare":       func(d stats.Delta) string { return compareLine(l, d, r.unit) },
+		"movers":        func(deltas []stats.Delta) string { return moversLine(l, deltas) },
+		"moves":         func(c stats.Currency) []string { return movesLines(l, c) },
+		"compareHeader": func() []string { return compareHeader(l) },
+		"compareCells":  func(d stats.Delta) []string { return compareCells(l, d) },
+		"points":        ratePoints,
+		"sparkline": func(points []model.Point) htmltemplate.HTML {
+			return htmltemplate.HTML(sparkline(points))
+		},
+		"lineChart": func(title string, points []model.Point, decimals int) htmltemplate.HTML {
+			value := func(v float64) string { return l.Number(v, decimals) }
+			return htmltemplate.HTML(lineChart(title, points, value, l.Date))
+		},
+	}
+}
+
+func minPoint(points []model.Point) model.Point {
+	m := points[0]
+	for _, p := range points {
+		if p.Value < m.Value {
+			m = p
+		}
+	}
+	return m
+}
+
+func maxPoint(points []model.Point) model.Point {
+	m := points[0]
+	for _, p := range points {
+		if p.Value > m.Value {
+			m = p
+		}
+	}
+	return m
+}
+
+// NewHTMLReporter строит самодостаточный HTML-отчёт (-format=html): таблицу
+// по валютам со спарклайнами и SVG-графики с отмеченными минимумом и
+// максимумом.
+func NewHTMLReporter(opts ...Option) (*TemplateReporter, error) {
+	return NewTemplateReporter("report.html", opts...)
+}
@@ -0,0 +1,193 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/gaps"
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+func testSummary() Summary {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	rates := []model.CurrencyRate{
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 80, Date: day(20)},
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 82, Date: day(21)},
+		{ID: "R01239", CharCode: "EUR", Name: "Euro <EU>", Rate: 95, Date: day(20)},
+		{ID: "R01239", CharCode: "EUR", Name: "Euro <EU>", Rate: 93.1, Date: day(21)},
+	}
+	return Summary{
+		Period:     Period{From: day(20), To: day(21)},
+		Max:        rates[2],
+		Min:        rates[0],
+		Avg:        87.525,
+		Count:      4,
+		Currencies: stats.ByCurrency(rates),
+		Indicators: []model.Series{{Name: "Key rate", Unit: "%", Points: []model.Point{{Date: day(20), Value: 16.5}}}},
+		Diagnostics: []model.Diagnostic{
+			{Date: day(21), Index: 2, Currency: "CNY", Field: "Nominal", Raw: "0", Reason: "nominal is zero"},
+		},
+	}
+}
+
+func TestTemplateReporter_BuiltinConsoleMatchesConsoleReporter(t *testing.T) {
+	summary := testSummary()
+	summary.AsOf = time.Date(2025, 10, 22, 9, 30, 0, 0, time.UTC)
+	summary.Revisions = testRevisions()
+	summary.Gaps = gaps.Policy{Fill: gaps.Linear, MinCoverage: 0.8}
+	summary.Dropped = []string{"CNY"}
+	summary.Currencies[0].Filled = 2
+	summary.Anomalies = testAnomalies()
+	summary.Comparison = testComparison(summary.Currencies)
+	addMoves(summary.Currencies)
+
+	for _, lang := range []string{"ru", "en"} {
+		var expected bytes.Buffer
+		console := NewConsoleReporter(WithWriter(&expected), WithLocale(locale(t, lang)))
+		if err := console.Report(context.Background(), summary); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var out bytes.Buffer
+		r, err := NewTemplateReporter("console.tmpl", WithWriter(&out), WithLocale(locale(t, lang)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := r.Report(context.Background(), summary); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if out.String() != expected.String() {
+			t.Errorf("%s: template output differs from ConsoleReporter:\n%s\nexpected:\n%s", lang, out.String(), expected.String())
+		}
+	}
+}
+
+func TestTemplateReporter_BuiltinHTMLEscapes(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewTemplateReporter("console.html", WithWriter(&out), WithLocale(locale(t, "en")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), testSummary()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	html := out.String()
+	if !strings.Contains(html, `<html lang="en">`) {
+		t.Errorf("Expected lang attribute, got:\n%s", html)
+	}
+	if !strings.Contains(html, "Euro &lt;EU&gt;") || strings.Contains(html, "Euro <EU>") {
+		t.Errorf("Expected escaped currency name, got:\n%s", html)
+	}
+	if !strings.Contains(html, "<li>") {
+		t.Errorf("Expected diagnostics list, got:\n%s", html)
+	}
+}
+
+func TestTemplateReporter_CustomFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.txt")
+	tmpl := `{{date .Period.From}}..{{dateFormat "02.01" .Period.To}} ({{.Count}})
+{{range .Currencies}}{{.Code}} {{number .Avg 2}} {{percent .Change 1}} {{len .Points}}
+{{end}}`
+	if err := os.WriteFile(path, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	r, err := NewTemplateReporter(path, WithWriter(&out))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), testSummary()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "2025-10-20..21.10 (4)\nEUR 94,05 -2,0% 2\nUSD 81,00 2,5% 2\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestTemplateReporter_FilePreferredOverBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "console.tmpl"), []byte("custom {{.Count}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Имя без каталога ищется в рабочем каталоге
+	t.Chdir(dir)
+
+	var out bytes.Buffer
+	r, err := NewTemplateReporter("console.tmpl", WithWriter(&out))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), testSummary()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.String() != "custom 4\n" {
+		t.Errorf("Expected file on disk to win over built-in, got:\n%s", out.String())
+	}
+
+	// Встроенный шаблон не подставляется вместо отсутствующего файла в каталоге
+	if _, err := NewTemplateReporter(filepath.Join(dir, "console.html")); err == nil {
+		t.Error("Expected error for missing template with a directory, got nil")
+	}
+	if _, err := NewTemplateReporter("console.html", WithWriter(&bytes.Buffer{})); err != nil {
+		t.Errorf("Expected built-in console.html, got %v", err)
+	}
+}
+
+func TestTemplateReporter_Errors(t *testing.T) {
+	if _, err := NewTemplateReporter(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("Expected error for missing template, got nil")
+	}
+
+	path := filepath.Join(t.TempDir(), "broken.tmpl")
+	if err := os.WriteFile(path, []byte(`{{.Max`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTemplateReporter(path); err == nil {
+		t.Error("Expected parse error, got nil")
+	}
+
+	path = filepath.Join(t.TempDir(), "exec.tmpl")
+	if err := os.WriteFile(path, []byte(`{{.NoSuchField}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewTemplateReporter(path, WithWriter(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), testSummary()); err == nil {
+		t.Error("Expected execution error, got nil")
+	}
+}
+
+func TestTemplateReporter_BuiltinConsolePerSeries(t *testing.T) {
+	summary := testSummary()
+	summary.PerSeries = true
+
+	var expected bytes.Buffer
+	if err := NewConsoleReporter(WithWriter(&expected)).Report(context.Background(), summary); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	r, err := NewTemplateReporter("console.tmpl", WithWriter(&out))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), summary); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.String() != expected.String() {
+		t.Errorf("Template output differs from ConsoleReporter:\n%s\nexpected:\n%s", out.String(), expected.String())
+	}
+}
@@ -0,0 +1,372 @@
+package reporter
+
+import (
+	"context"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+const (
+	defaultWidth = 80
+	chartRows    = 8
+	maxNameWidth = 24
+	minSparkline = 8
+	maxSparkline = 40
+
+	ansiGreen = "\x1b[32m"
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// TerminalReporter выводит сводку для терминала: выровненную таблицу по
+// валютам со спарклайнами и braille-графики выбранных валют. Рост и падение
+// подсвечиваются ANSI-цветом, если он включён (WithColor).
+type TerminalReporter struct {
+	options
+}
+
+func NewTerminalReporter(opts ...Option) (*TerminalReporter, error) {
+	o := applyOptions(opts)
+	if err := checkSort(o.sortBy); err != nil {
+		return nil, err
+	}
+	if o.width <= 0 {
+		o.width = defaultWidth
+	}
+	return &TerminalReporter{options: o}, nil
+}
+
+func (r *TerminalReporter) Report(_ context.Context, summary Summary) error {
+	w := &errWriter{w: r.out}
+	// Итоговые строки, показатели и диагностика — как в обычном выводе
+	console := &ConsoleReporter{options: r.options}
+	if !summary.AsOf.IsZero() {
+		w.printf("%s\n", asOfLine(r.locale, summary.AsOf))
+	}
+	if !summary.PerSeries {
+		console.writeExtremes(w, summary)
+	}
+
+	currencies := sortCurrencies(summary.Currencies, r.sortBy)
+	if len(currencies) > 0 {
+		if !summary.PerSeries {
+			w.printf("\n")
+		}
+		r.table(w, currencies)
+	}
+	for _, c := range currencies {
+		if r.charted(c) {
+			w.printf("\n")
+			r.chart(w, c)
+		}
+	}
+
+	if c := summary.Comparison; c != nil {
+		w.printf("\n%s\n", r.locale.T("compare.title", r.locale.Date(c.Base.From), r.locale.Date(c.Base.To)))
+		r.compareTable(w, c.Deltas)
+		if len(c.Movers) > 0 {
+			w.printf("%s\n", moversLine(r.locale, c.Movers))
+		}
+	}
+
+	gapped := summary.Filled() > 0 || len(summary.Dropped) > 0
+	if len(summary.Indicators) > 0 || gapped || len(summary.Anomalies) > 0 || len(summary.Diagnostics) > 0 || len(summary.Revisions) > 0 {
+		w.printf("\n")
+	}
+	for _, s := range summary.Indicators {
+		w.printf("%s\n", seriesLine(r.locale, s))
+	}
+	console.writeGaps(w, summary)
+	if len(summary.Anomalies) > 0 {
+		console.writeAnomalies(w, summary.Anomalies)
+	}
+	if len(summary.Diagnostics) > 0 {
+		console.writeDiagnostics(w, summary.Diagnostics)
+	}
+	if len(summary.Revisions) > 0 {
+		console.writeRevisions(w, summary.Revisions)
+	}
+	return w.err
+}
+
+func (r *TerminalReporter) charted(c stats.Currency) bool {
+	for _, code := range r.charts {
+		if strings.EqualFold(code, c.Code()) || (c.ID != "" && strings.EqualFold(code, c.ID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// cell — ячейка таблицы; цвет применяется после выравнивания, чтобы
+// escape-коды не влияли на ширину столбца.
+type cell struct {
+	text  string
+	color string
+}
+
+func (r *TerminalReporter) table(w *errWriter, currencies []stats.Currency) {
+	l := r.locale
+	header := []string{
+		l.T("table.currency"), l.T("table.name"), l.T("table.last"), l.T("table.change"),
+		l.T("table.min"), l.T("table.max"), l.T("table.volatility"), l.T("table.trend"),
+	}
+	right := []bool{false, false, true, true, true, true, true, false}
+
+	rows := make([][]cell, 0, len(currencies))
+	for _, c := range currencies {
+		color := r.moveColor(c.Change)
+		rows = append(rows, []cell{
+			{text: c.Code()},
+			{text: truncate(l.Name(c.Name), maxNameWidth)},
+			{text: l.Number(c.Last.Rate, 4)},
+			{text: arrow(c.Change) + " " + l.Number(c.Change*100, 2) + "%", color: color},
+			{text: l.Number(c.Min.Rate, 4)},
+			{text: l.Number(c.Max.Rate, 4)},
+			{text: l.Number(c.Volatility*100, 2) + "%"},
+		})
+	}
+
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, row := range rows {
+		for i, c := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(c.text))
+		}
+	}
+
+	// Спарклайн занимает оставшуюся ширину строки
+	used := 0
+	for _, width := range widths[:len(widths)-1] {
+		used += width + 2
+	}
+	spark := min(max(r.width-used, minSparkline), maxSparkline)
+	widths[len(widths)-1] = max(widths[len(widths)-1], spark)
+	for i, c := range currencies {
+		rows[i] = append(rows[i], cell{text: blockSparkline(c.Points, spark), color: r.moveColor(c.Change)})
+	}
+
+	headerCells := make([]cell, len(header))
+	for i, h := range header {
+		headerCells[i] = cell{text: h}
+	}
+	r.row(w, headerCells, widths, right)
+	for _, row := range rows {
+		r.row(w, row, widths, right)
+	}
+}
+
+// compareTable — таблица сравнения периодов; изменение среднего выделено
+// цветом.
+func (r *TerminalReporter) compareTable(w *errWriter, deltas []stats.Delta) {
+	l := r.locale
+	header := compareHeader(l)
+	right := make([]bool, len(header))
+	for i := 2; i < len(right); i++ {
+		right[i] = true
+	}
+
+	rows := [][]cell{make([]cell, len(header))}
+	for i, h := range header {
+		rows[0][i] = cell{text: h}
+	}
+	for _, d := range deltas {
+		texts := compareCells(l, d)
+		texts[1] = truncate(texts[1], maxNameWidth)
+		row := make([]cell, len(texts))
+		for i, text := range texts {
+			row[i] = cell{text: text}
+		}
+		if d.Both() {
+			row[4].color = r.moveColor(d.Avg)
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, c := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(c.text))
+		}
+	}
+	for _, row := range rows {
+		r.row(w, row, widths, right)
+	}
+}
+
+func (r *TerminalReporter) row(w *errWriter, cells []cell, widths []int, right []bool) {
+	parts := make([]string, len(cells))
+	for i, c := range cells {
+		pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(c.text))
+		text := c.text + pad
+		switch {
+		case right[i]:
+			text = pad + c.text
+		case i == len(cells)-1:
+			text = c.text
+		}
+		parts[i] = r.paint(text, c.color)
+	}
+	w.printf("%s\n", strings.Join(parts, "  "))
+}
+
+func (r *TerminalReporter) chart(w *errWriter, c stats.Currency) {
+	l := r.locale
+	points := ratePoints(c.Points)
+	top, bottom := l.Number(c.Max.Rate, 4), l.Number(c.Min.Rate, 4)
+	margin := max(utf8.RuneCountInString(top), utf8.RuneCountInString(bottom))
+	width := max(r.width-margin-2, minSparkline)
+
+	w.printf("%s — %s, %s\n", c.Code(), l.Name(c.Name), r.unit)
+	lines := brailleChart(points, width, chartRows)
+	for i, line := range lines {
+		label, axis := "", "│"
+		switch i {
+		case 0:
+			label, axis = top, "┤"
+		case len(lines) - 1:
+			label, axis = bottom, "┤"
+		}
+		w.printf("%*s %s%s\n", margin, label, axis, r.paint(line, r.moveColor(c.Change)))
+	}
+
+	from, to := l.Date(c.First.Date), l.Date(c.Last.Date)
+	gap := max(width-utf8.RuneCountInString(from)-utf8.RuneCountInString(to), 1)
+	w.printf("%*s  %s%s%s\n", margin, "", from, strings.Repeat(" ", gap), to)
+}
+
+func (r *TerminalReporter) moveColor(change float64) string {
+	switch {
+	case change > 0:
+		return ansiGreen
+	case change < 0:
+		return ansiRed
+	default:
+		return ""
+	}
+}
+
+func (r *TerminalReporter) paint(text, color string) string {
+	if !r.color || color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+func arrow(change float64) string {
+	switch {
+	case change > 0:
+		return "▲"
+	case change < 0:
+		return "▼"
+	default:
+		return " "
+	}
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// blockSparkline рисует ряд блоками ▁..█ шириной не больше width символов;
+// длинный ряд усредняется по корзинам.
+func blockSparkline(rates []model.CurrencyRate, width int) string {
+	if len(rates) == 0 || width <= 0 {
+		return ""
+	}
+	values := make([]float64, 0, min(len(rates), width))
+	if len(rates) <= width {
+		for _, r := range rates {
+			values = append(values, r.Rate)
+		}
+	} else {
+		for i := 0; i < width; i++ {
+			from, to := i*len(rates)/width, (i+1)*len(rates)/width
+			var sum float64
+			for _, r := range rates[from:to] {
+				sum += r.Rate
+			}
+			values = append(values, sum/float64(to-from))
+		}
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int(math.Round((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// brailleDots — биты точек символа Брайля по столбцу (0, 1) и строке (0..3).
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// brailleChart рисует ряд линией из символов Брайля: каждый символ — сетка
+// 2×4 точки, так что разрешение вдвое выше по X и вчетверо по Y. X
+// пропорционален дате, как и в SVG-графиках.
+func brailleChart(points []model.Point, width, rows int) []string {
+	cols, dotRows := width*2, rows*4
+	grid := make([][]rune, rows)
+	for i := range grid {
+		grid[i] = make([]rune, width)
+	}
+	if len(points) > 0 {
+		p := newPlot(points, 0, 0, float64(cols-1), float64(dotRows-1))
+		set := func(x, y int) {
+			grid[y/4][x/2] |= brailleDots[x%2][y%4]
+		}
+		dot := func(pt model.Point) (int, int) {
+			return int(math.Round(p.x(pt.Date))), int(math.Round(p.y(pt.Value)))
+		}
+
+		x0, y0 := dot(points[0])
+		set(x0, y0)
+		for _, pt := range points[1:] {
+			x1, y1 := dot(pt)
+			steps := max(abs(x1-x0), abs(y1-y0))
+			for s := 1; s <= steps; s++ {
+				set(x0+(x1-x0)*s/steps, y0+(y1-y0)*s/steps)
+			}
+			x0, y0 = x1, y1
+		}
+	}
+
+	lines := make([]string, rows)
+	for i, row := range grid {
+		var b strings.Builder
+		for _, bits := range row {
+			b.WriteRune(0x2800 | bits)
+		}
+		lines[i] = b.String()
+	}
+	return lines
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,144 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func TestTerminalReporter_Table(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewTerminalReporter(WithWriter(&out), WithLocale(locale(t, "en")), WithSort(SortByChange), WithWidth(80))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), testSummary()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := `Maximum: Euro <EU> — 95.0000 RUB on 2025-10-20
+Minimum: US Dollar — 80.0000 RUB on 2025-10-20
+Average rate: 87.5250 RUB
+
+Currency  Name          Last    Change      Min      Max  Volatility  Trend
+USD       US Dollar  82.0000   ▲ 2.50%  80.0000  82.0000       0.00%  ▁█
+EUR       Euro <EU>  93.1000  ▼ -2.00%  93.1000  95.0000       0.00%  █▁
+
+Key rate: 16.50% on 2025-10-20 (min 16.50% on 2025-10-20, max 16.50% on 2025-10-20)
+Skipped entries: 1
+  2025-10-21 #2 CNY: Nominal="0" — nominal is zero
+`
+	if out.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", out.String(), expected)
+	}
+}
+
+func TestTerminalReporter_Color(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewTerminalReporter(WithWriter(&out), WithColor(true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := r.Report(context.Background(), testSummary()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.Contains(out.String(), ansiGreen+"  ▲ 2,50%"+ansiReset) {
+		t.Errorf("Expected green rise, got:\n%q", out.String())
+	}
+	if !strings.Contains(out.String(), ansiRed+" ▼ -2,00%"+ansiReset) {
+		t.Errorf("Expected red fall, got:\n%q", out.String())
+	}
+
+	// Без WithColor escape-кодов нет
+	out.Reset()
+	r, _ = NewTerminalReporter(WithWriter(&out))
+	r.Report(context.Background(), testSummary())
+	if strings.Contains(out.String(), "\x1b[") {
+		t.Errorf("Unexpected ANSI codes:\n%q", out.String())
+	}
+}
+
+func TestTerminalReporter_Sort(t *testing.T) {
+	summary := testSummary()
+	summary.Currencies[0].Volatility = 0.01
+	summary.Currencies[1].Volatility = 0.02
+
+	for by, first := range map[string]string{SortByCode: "EUR", SortByChange: "USD", SortByVolatility: "USD"} {
+		var out bytes.Buffer
+		r, err := NewTerminalReporter(WithWriter(&out), WithSort(by))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		r.Report(context.Background(), summary)
+
+		lines := strings.Split(out.String(), "\n")
+		if !strings.HasPrefix(lines[5], first) {
+			t.Errorf("%s: expected %s first, got %q", by, first, lines[5])
+		}
+	}
+
+	if _, err := NewTerminalReporter(WithSort("name")); err == nil {
+		t.Error("Expected error for unknown sort, got nil")
+	}
+}
+
+func TestTerminalReporter_Chart(t *testing.T) {
+	var out bytes.Buffer
+	r, err := NewTerminalReporter(WithWriter(&out), WithLocale(locale(t, "en")), WithCharts("usd"), WithWidth(40))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	r.Report(context.Background(), testSummary())
+
+	text := out.String()
+	if strings.Contains(text, "EUR — ") {
+		t.Errorf("Chart for EUR was not requested:\n%s", text)
+	}
+	chart := text[strings.Index(text, "USD — US Dollar, RUB"):]
+	lines := strings.Split(chart, "\n")
+	// Заголовок, 8 строк графика, подпись дат
+	if !strings.HasPrefix(lines[1], "82.0000 ┤") || !strings.HasPrefix(lines[8], "80.0000 ┤") {
+		t.Errorf("Unexpected axis labels:\n%s", chart)
+	}
+	if !strings.HasPrefix(lines[9], "         2025-10-20") || !strings.HasSuffix(lines[9], "2025-10-21") {
+		t.Errorf("Unexpected date axis: %q", lines[9])
+	}
+	for _, line := range lines[1:9] {
+		if got := len([]rune(line)); got != 40 {
+			t.Errorf("Expected chart line of 40 runes, got %d: %q", got, line)
+		}
+	}
+}
+
+func TestBrailleChart(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	// Диагональ снизу слева вверх направо на сетке 2×1 символа (4×4 точки)
+	lines := brailleChart([]model.Point{{Date: day(1), Value: 0}, {Date: day(2), Value: 3}}, 2, 1)
+	if len(lines) != 1 || lines[0] != "⡠⠊" {
+		t.Errorf("Unexpected chart: %q", lines)
+	}
+}
+
+func TestBlockSparkline(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	var rates []model.CurrencyRate
+	for i, v := range []float64{1, 2, 3, 4, 5, 6, 7, 8} {
+		rates = append(rates, model.CurrencyRate{Rate: v, Date: day(i + 1)})
+	}
+
+	if got := blockSparkline(rates, 8); got != "▁▂▃▄▅▆▇█" {
+		t.Errorf("Unexpected sparkline: %q", got)
+	}
+	// Длинный ряд усредняется до ширины
+	if got := blockSparkline(rates, 4); got != "▁▃▆█" {
+		t.Errorf("Unexpected resampled sparkline: %q", got)
+	}
+	if got := blockSparkline(rates[:1], 4); got != "▁" {
+		t.Errorf("Unexpected single-point sparkline: %q", got)
+	}
+}
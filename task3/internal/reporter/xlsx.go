@@ -0,0 +1,134 @@
+package reporter
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/xlsx"
+)
+
+// XLSXReporter записывает книгу Excel: лист курсов (дата × валюта) и лист
+// статистики по валютам.
+type XLSXReporter struct {
+	options
+}
+
+func NewXLSXReporter(opts ...Option) *XLSXReporter {
+	return &XLSXReporter{options: applyOptions(opts)}
+}
+
+func (r *XLSXReporter) Report(_ context.Context, summary Summary) error {
+	wb := &xlsx.Workbook{Sheets: []xlsx.Sheet{r.ratesSheet(summary), r.statsSheet(summary)}}
+	if summary.Comparison != nil {
+		wb.Sheets = append(wb.Sheets, r.compareSheet(summary.Comparison))
+	}
+	if len(summary.Anomalies) > 0 {
+		wb.Sheets = append(wb.Sheets, r.anomaliesSheet(summary.Anomalies))
+	}
+	return wb.Write(r.out)
+}
+
+// ratesSheet раскладывает курсы в матрицу: строка на дату, столбец на
+// валюту; даты без курса валюты остаются пустыми, восстановленные курсы
+// выделены курсивом.
+func (r *XLSXReporter) ratesSheet(summary Summary) xlsx.Sheet {
+	l := r.locale
+	header := []xlsx.Cell{xlsx.Header(l.T("table.date"))}
+	byDate := make(map[time.Time][]xlsx.Cell)
+	for i, c := range summary.Currencies {
+		header = append(header, xlsx.Header(c.Code()))
+		for _, p := range c.Points {
+			row, ok := byDate[p.Date]
+			if !ok {
+				row = make([]xlsx.Cell, len(summary.Currencies)+1)
+				row[0] = xlsx.Date(p.Date)
+			}
+			row[i+1] = xlsx.Decimal(p.Rate)
+			if p.Filled {
+				row[i+1] = xlsx.Filled(p.Rate)
+			}
+			byDate[p.Date] = row
+		}
+	}
+
+	dates := make([]time.Time, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	rows := [][]xlsx.Cell{header}
+	for _, d := range dates {
+		rows = append(rows, byDate[d])
+	}
+	return xlsx.Sheet{Name: l.T("xlsx.rates"), Rows: rows, FreezeRows: 1, FreezeCols: 1}
+}
+
+func (r *XLSXReporter) statsSheet(summary Summary) xlsx.Sheet {
+	l := r.locale
+	rows := [][]xlsx.Cell{{
+		xlsx.Header(l.T("table.currency")), xlsx.Header(l.T("table.name")),
+		xlsx.Header(l.T("table.first")), xlsx.Header(l.T("table.last")), xlsx.Header(l.T("table.change")),
+		xlsx.Header(l.T("table.min")), xlsx.Header(l.T("table.min_date")),
+		xlsx.Header(l.T("table.max")), xlsx.Header(l.T("table.max_date")),
+		xlsx.Header(l.T("table.avg")), xlsx.Header(l.T("table.volatility")), xlsx.Header(l.T("table.count")),
+	}}
+	for _, c := range sortCurrencies(summary.Currencies, r.sortBy) {
+		rows = append(rows, []xlsx.Cell{
+			xlsx.Text(c.Code()), xlsx.Text(l.Name(c.Name)),
+			xlsx.Decimal(c.First.Rate), xlsx.Decimal(c.Last.Rate), xlsx.Percent(c.Change),
+			xlsx.Decimal(c.Min.Rate), xlsx.Date(c.Min.Date),
+			xlsx.Decimal(c.Max.Rate), xlsx.Date(c.Max.Date),
+			xlsx.Decimal(c.Avg), xlsx.Percent(c.Volatility), xlsx.Integer(c.Count),
+		})
+	}
+	return xlsx.Sheet{Name: l.T("xlsx.stats"), Rows: rows, FreezeRows: 1}
+}
+
+// anomaliesSheet — лист аномалий, если детектор что-то нашёл.
+func (r *XLSXReporter) anomaliesSheet(anomalies []model.Anomaly) xlsx.Sheet {
+	l := r.locale
+	rows := [][]xlsx.Cell{{
+		xlsx.Header(l.T("table.date")), xlsx.Header(l.T("table.currency")),
+		xlsx.Header(l.T("table.prev")), xlsx.Header(l.T("table.rate")), xlsx.Header(l.T("table.change")),
+		xlsx.Header(l.T("table.reason")),
+	}}
+	for _, a := range anomalies {
+		rows = append(rows, []xlsx.Cell{
+			xlsx.Date(a.Rate.Date), xlsx.Text(a.Code()),
+			xlsx.Decimal(a.Prev.Rate), xlsx.Decimal(a.Rate.Rate), xlsx.Percent(math.Exp(a.Return) - 1),
+			xlsx.Text(anomalyReason(l, a)),
+		})
+	}
+	return xlsx.Sheet{Name: l.T("xlsx.anomalies"), Rows: rows, FreezeRows: 1}
+}
+
+// compareSheet — лист сравнения с базовым периодом; чего нет в одном из
+// периодов, то пусто.
+func (r *XLSXReporter) compareSheet(c *Comparison) xlsx.Sheet {
+	l := r.locale
+	var header []xlsx.Cell
+	for _, h := range compareHeader(l) {
+		header = append(header, xlsx.Header(h))
+	}
+	rows := [][]xlsx.Cell{header}
+	for _, d := range c.Deltas {
+		row := make([]xlsx.Cell, len(header))
+		row[0], row[1] = xlsx.Text(d.Code()), xlsx.Text(l.Name(d.Name()))
+		if d.Base != nil {
+			row[2], row[7] = xlsx.Decimal(d.Base.Avg), xlsx.Percent(d.Base.Volatility)
+		}
+		if d.Current != nil {
+			row[3], row[8] = xlsx.Decimal(d.Current.Avg), xlsx.Percent(d.Current.Volatility)
+		}
+		if d.Both() {
+			row[4], row[5], row[6] = xlsx.Percent(d.Avg), xlsx.Percent(d.Max), xlsx.Percent(d.Min)
+			row[9] = xlsx.Percent(d.Volatility)
+		}
+		rows = append(rows, row)
+	}
+	return xlsx.Sheet{Name: l.T("xlsx.compare"), Rows: rows, FreezeRows: 1}
+}
@@ -0,0 +1,128 @@
+package reporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+type sheetXML struct {
+	Pane struct {
+		XSplit int `xml:"xSplit,attr"`
+		YSplit int `xml:"ySplit,attr"`
+	} `xml:"sheetViews>sheetView>pane"`
+	Rows []struct {
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			S      int    `xml:"s,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readSheet открывает книгу как zip и разбирает лист с номером n.
+func readSheet(t *testing.T, data []byte, n string) sheetXML {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Result is not a zip: %v", err)
+	}
+	f, err := r.Open("xl/worksheets/sheet" + n + ".xml")
+	if err != nil {
+		t.Fatalf("Missing sheet %s: %v", n, err)
+	}
+	defer f.Close()
+	body, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet sheetXML
+	if err := xml.Unmarshal(body, &sheet); err != nil {
+		t.Fatalf("Failed to parse sheet %s: %v", n, err)
+	}
+	return sheet
+}
+
+func TestXLSXReporter(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	summary := testSummary()
+	// Курс CNY есть только за 21-е — 20-е в матрице остаётся пустым
+	summary.Currencies = stats.ByCurrency(append(
+		append(summary.Currencies[0].Points, summary.Currencies[1].Points...),
+		model.CurrencyRate{ID: "R01375", CharCode: "CNY", Name: "Yuan", Rate: 11.25, Date: day(21)},
+	))
+
+	var out bytes.Buffer
+	r := NewXLSXReporter(WithWriter(&out), WithLocale(locale(t, "en")))
+	if err := r.Report(context.Background(), summary); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rates := readSheet(t, out.Bytes(), "1")
+	if rates.Pane.XSplit != 1 || rates.Pane.YSplit != 1 {
+		t.Errorf("Expected frozen header row and date column, got %+v", rates.Pane)
+	}
+	if len(rates.Rows) != 3 {
+		t.Fatalf("Expected header and 2 dates, got %d rows", len(rates.Rows))
+	}
+	header := rates.Rows[0].Cells
+	if header[0].Inline != "Date" || header[1].Inline != "CNY" || header[2].Inline != "EUR" || header[3].Inline != "USD" {
+		t.Errorf("Unexpected header: %+v", header)
+	}
+	first := rates.Rows[1].Cells
+	// 20.10.2025: дата, EUR, USD — ячейки CNY нет
+	if len(first) != 3 || first[0].V != "45950" || first[1].R != "C2" || first[1].V != "95" || first[2].V != "80" {
+		t.Errorf("Unexpected first row: %+v", first)
+	}
+	second := rates.Rows[2].Cells
+	if len(second) != 4 || second[1].R != "B3" || second[1].V != "11.25" {
+		t.Errorf("Unexpected second row: %+v", second)
+	}
+
+	statsSheet := readSheet(t, out.Bytes(), "2")
+	if statsSheet.Pane.YSplit != 1 || statsSheet.Pane.XSplit != 0 {
+		t.Errorf("Expected frozen header row, got %+v", statsSheet.Pane)
+	}
+	usd := statsSheet.Rows[3].Cells
+	change, _ := strconv.ParseFloat(usd[4].V, 64)
+	if usd[0].Inline != "USD" || math.Abs(change-0.025) > 1e-12 || usd[6].V != "45950" || usd[11].V != "2" {
+		t.Errorf("Unexpected USD stats: %+v", usd)
+	}
+	// Изменение — процентный формат, дата минимума — формат даты
+	if usd[4].S != 3 || usd[6].S != 1 {
+		t.Errorf("Unexpected styles: change %d, min date %d", usd[4].S, usd[6].S)
+	}
+}
+
+func TestXLSXReporter_Anomalies(t *testing.T) {
+	summary := testSummary()
+	usd := summary.Currencies[1]
+	summary.Currencies[1].Points[1].Filled = true
+	summary.Anomalies = []model.Anomaly{{Prev: usd.Points[0], Rate: usd.Points[1], Return: math.Log(82.0 / 80), Reason: model.AnomalyZScore, Score: 5.2}}
+
+	var out bytes.Buffer
+	report(t, NewXLSXReporter(WithWriter(&out), WithLocale(locale(t, "en"))), summary)
+
+	// Восстановленный курс выделен стилем
+	if cell := readSheet(t, out.Bytes(), "1").Rows[2].Cells[2]; cell.V != "82" || cell.S != 5 {
+		t.Errorf("Filled rate must be styled: %+v", cell)
+	}
+	anomalies := readSheet(t, out.Bytes(), "3")
+	if len(anomalies.Rows) != 2 {
+		t.Fatalf("Expected header and 1 anomaly, got %d rows", len(anomalies.Rows))
+	}
+	row := anomalies.Rows[1].Cells
+	if row[1].Inline != "USD" || row[3].V != "82" || row[5].Inline != "5.2σ move" {
+		t.Errorf("Unexpected anomaly row: %+v", row)
+	}
+}
@@ -0,0 +1,136 @@
+package revision
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"task3/internal/fetcher"
+	"task3/internal/model"
+	"task3/internal/parser"
+	"task3/internal/stats"
+	"task3/internal/storage"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const workersNum = 4
+
+// Hash — хэш содержимого дня: валюты, даты и значения курсов без названий,
+// которые зависят от языка эндпоинта. Порядок валют не влияет.
+func Hash(rates []model.CurrencyRate) string {
+	lines := make([]string, 0, len(rates))
+	for _, r := range rates {
+		lines = append(lines, stats.Key(r)+"|"+r.Date.Format("2006-01-02")+"|"+strconv.FormatFloat(r.Rate, 'g', -1, 64))
+	}
+	sort.Strings(lines)
+
+	h := sha256.New()
+	for _, l := range lines {
+		h.Write([]byte(l))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// missing — курсы из stored, валют которых нет в rates.
+func missing(stored, rates []model.CurrencyRate) []model.CurrencyRate {
+	keys := make(map[string]bool, len(rates))
+	for _, r := range rates {
+		keys[stats.Key(r)] = true
+	}
+	var result []model.CurrencyRate
+	for _, r := range stored {
+		if !keys[stats.Key(r)] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// Result — итог проверки.
+type Result struct {
+	// Checked — сколько сохранённых дней загружено заново.
+	Checked int
+	// Changed — дни, содержимое которых разошлось с сохранённым.
+	Changed []time.Time
+	// Revisions — исправления, записанные этой проверкой.
+	Revisions []model.Revision
+}
+
+// Checker заново загружает сохранённые дни и записывает исправления курсов в
+// историю хранилища.
+type Checker struct {
+	store   storage.History
+	fetcher fetcher.CurrencyRateFetcher
+	now     func() time.Time
+}
+
+func NewChecker(store storage.History, fetcher fetcher.CurrencyRateFetcher) *Checker {
+	return &Checker{store: store, fetcher: fetcher, now: time.Now}
+}
+
+func (c *Checker) Run(ctx context.Context, from, to time.Time) (Result, error) {
+	stored, err := c.store.Range(ctx, from, to)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read store: %w", err)
+	}
+	started := c.now()
+
+	eg, gCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(workersNum)
+	var mu sync.Mutex
+	var result Result
+	for d, snapshot := range stored {
+		eg.Go(func() error {
+			xml, err := c.fetcher.GetCourseByDate(gCtx, d)
+			if err != nil {
+				return fmt.Errorf("failed to get course by date %v: %w", d, err)
+			}
+			if len(xml) == 0 {
+				return nil
+			}
+			rates, err := parser.ParseRates(xml)
+			if err != nil {
+				return fmt.Errorf("failed to parse rates for date %v: %w", d, err)
+			}
+
+			mu.Lock()
+			result.Checked++
+			mu.Unlock()
+			if Hash(rates) == Hash(snapshot) {
+				return nil
+			}
+
+			mu.Lock()
+			result.Changed = append(result.Changed, d)
+			mu.Unlock()
+			if err := c.store.Put(gCtx, d, rates); err != nil {
+				return err
+			}
+			// Валюты, которых в переопубликованном дне нет, удаляются из
+			// него — иначе день расходился бы с сохранённым при каждой проверке
+			return c.store.Remove(gCtx, d, missing(snapshot, rates))
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return Result{}, err
+	}
+	sort.Slice(result.Changed, func(i, j int) bool { return result.Changed[i].Before(result.Changed[j]) })
+
+	revisions, err := c.store.Revisions(ctx, from, to)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, r := range revisions {
+		if !r.Detected.Before(started) {
+			result.Revisions = append(result.Revisions, r)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package revision
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"task3/internal/fetcher"
+	"task3/internal/model"
+	"task3/internal/storage"
+)
+
+func oct(d int) time.Time {
+	return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestHash(t *testing.T) {
+	a := []model.CurrencyRate{
+		{ID: "R01235", Name: "Доллар США", Rate: 80, Date: oct(20)},
+		{ID: "R01239", Name: "Евро", Rate: 90, Date: oct(20)},
+	}
+	b := []model.CurrencyRate{
+		{ID: "R01239", Name: "Euro", Rate: 90, Date: oct(20)},
+		{ID: "R01235", Name: "US Dollar", Rate: 80, Date: oct(20)},
+	}
+	// Порядок и язык названий не важны
+	if Hash(a) != Hash(b) {
+		t.Error("Expected equal hashes")
+	}
+	b[0].Rate = 90.5
+	if Hash(a) == Hash(b) {
+		t.Error("Expected different hashes for changed rate")
+	}
+}
+
+func TestChecker_Run(t *testing.T) {
+	ctx := context.Background()
+	clock := time.Date(2025, 10, 21, 9, 0, 0, 0, time.UTC)
+	now := func() time.Time { return clock }
+
+	store, err := storage.Open(filepath.Join(t.TempDir(), "rates.jsonl"), storage.WithClock(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for _, d := range []int{20, 21} {
+		store.Put(ctx, oct(d), []model.CurrencyRate{
+			{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 80, Date: oct(d)},
+			{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: 90, Date: oct(d)},
+		})
+	}
+	store.Put(ctx, oct(22), []model.CurrencyRate{
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 81, Date: oct(22)},
+		{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: 91, Date: oct(22)},
+	})
+
+	// ЦБ исправил доллар за 20 октября и добавил юань, а из 22 октября
+	// пропал евро
+	usd := map[int]string{20: "80,5", 21: "80"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		date, _ := time.Parse("02/01/2006", r.URL.Query().Get("date_req"))
+		if date.Day() == 22 {
+			fmt.Fprint(w, `<ValCurs Date="22.10.2025"><Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>81</Value></Valute></ValCurs>`)
+			return
+		}
+		fmt.Fprintf(w, `<ValCurs Date="%s">
+			<Valute ID="R01235"><CharCode>USD</CharCode><Nominal>1</Nominal><Name>US Dollar</Name><Value>%s</Value></Valute>
+			<Valute ID="R01239"><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Euro</Name><Value>90</Value></Valute>`,
+			date.Format("02.01.2006"), usd[date.Day()])
+		if date.Day() == 20 {
+			fmt.Fprint(w, `<Valute ID="R01375"><CharCode>CNY</CharCode><Nominal>10</Nominal><Name>Yuan</Name><Value>112</Value></Valute>`)
+		}
+		fmt.Fprint(w, `</ValCurs>`)
+	}))
+	defer server.Close()
+
+	clock = clock.Add(24 * time.Hour)
+	checker := NewChecker(store, fetcher.NewClient(server.URL))
+	checker.now = now
+	result, err := checker.Run(ctx, oct(1), oct(31))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Checked != 3 || len(result.Changed) != 2 || !result.Changed[0].Equal(oct(20)) || !result.Changed[1].Equal(oct(22)) {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(result.Revisions) != 3 {
+		t.Fatalf("Expected 3 revisions, got %+v", result.Revisions)
+	}
+	if removed := result.Revisions[2]; !removed.Removed() || removed.Code() != "EUR" || removed.Old.Rate != 91 || !removed.Day.Equal(oct(22)) {
+		t.Errorf("Unexpected revision: %+v", removed)
+	}
+	if day, _ := store.Range(ctx, oct(22), oct(22)); len(day[oct(22)]) != 1 {
+		t.Errorf("Removed currency must not be returned: %+v", day)
+	}
+	fix := result.Revisions[0]
+	if fix.New.CharCode != "USD" || fix.Old.Rate != 80 || fix.New.Rate != 80.5 || !fix.Detected.Equal(clock) {
+		t.Errorf("Unexpected revision: %+v", fix)
+	}
+	if added := result.Revisions[1]; !added.Added() || added.New.CharCode != "CNY" || added.New.Rate != 11.2 {
+		t.Errorf("Unexpected revision: %+v", added)
+	}
+
+	// По состоянию на вчера — прежние значения
+	before, _ := store.RangeAsOf(ctx, oct(20), oct(20), clock.Add(-time.Hour))
+	if len(before[oct(20)]) != 2 || before[oct(20)][0].Rate != 80 {
+		t.Errorf("Unexpected as-of rates: %+v", before)
+	}
+	latest, _ := store.Range(ctx, oct(20), oct(20))
+	if len(latest[oct(20)]) != 3 || latest[oct(20)][0].Rate != 80.5 {
+		t.Errorf("Unexpected latest rates: %+v", latest)
+	}
+
+	// Повторная проверка исправлений не находит
+	clock = clock.Add(time.Hour)
+	result, err = checker.Run(ctx, oct(1), oct(31))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Changed) != 0 || len(result.Revisions) != 0 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,140 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/html/charset"
+
+	"task3/internal/fetcher"
+)
+
+const envelopeNS = "http://schemas.xmlsoap.org/soap/envelope/"
+
+// Fault — SOAP 1.1 fault, возвращается как ошибка Call.
+type Fault struct {
+	Code   string `xml:"faultcode"`
+	String string `xml:"faultstring"`
+	Detail string `xml:"detail"`
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("soap fault %s: %s", f.Code, f.String)
+}
+
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewClient(url string) *Client {
+	return &Client{
+		url: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+type requestEnvelope struct {
+	XMLName xml.Name `xml:"soap:Envelope"`
+	NS      string   `xml:"xmlns:soap,attr"`
+	Body    struct {
+		Content any
+	} `xml:"soap:Body"`
+}
+
+type responseEnvelope struct {
+	Body struct {
+		Fault   *Fault `xml:"Fault"`
+		Content []byte `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// Call отправляет request в теле конверта с заголовком SOAPAction и
+// раскладывает содержимое soap:Body ответа в response. Ответ перекодируется
+// в UTF-8 по charset из Content-Type или XML-декларации (ЦБ отдаёт windows-1251).
+func (c *Client) Call(ctx context.Context, action string, request, response any) error {
+	body, err := Envelope(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("SOAPAction", `"`+action+`"`)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %w", fetcher.ErrUpstreamUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	decoder, err := newDecoder(resp)
+	if err != nil {
+		return err
+	}
+
+	var env responseEnvelope
+	if err := decoder.Decode(&env); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return &fetcher.HTTPStatusError{StatusCode: resp.StatusCode, URL: c.url}
+		}
+		return err
+	}
+	if env.Body.Fault != nil {
+		return env.Body.Fault
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &fetcher.HTTPStatusError{StatusCode: resp.StatusCode, URL: c.url}
+	}
+
+	return xml.Unmarshal(env.Body.Content, response)
+}
+
+// Envelope заворачивает request в SOAP 1.1 конверт.
+func Envelope(request any) ([]byte, error) {
+	env := requestEnvelope{NS: envelopeNS}
+	env.Body.Content = request
+
+	body, err := xml.Marshal(env)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// newDecoder отдаёт приоритет charset из Content-Type, иначе полагается на
+// XML-декларацию документа.
+func newDecoder(resp *http.Response) (*xml.Decoder, error) {
+	_, params, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	label := params["charset"]
+	if label == "" {
+		decoder := xml.NewDecoder(resp.Body)
+		decoder.CharsetReader = charset.NewReaderLabel
+		return decoder, nil
+	}
+
+	reader, err := charset.NewReaderLabel(label, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		// тело уже перекодировано в UTF-8
+		return input, nil
+	}
+	return decoder, nil
+}
@@ -0,0 +1,142 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"task3/internal/fetcher"
+)
+
+type echoRequest struct {
+	XMLName xml.Name `xml:"Echo"`
+	NS      string   `xml:"xmlns,attr"`
+	Text    string   `xml:"text"`
+}
+
+type echoResponse struct {
+	Result string `xml:"EchoResult"`
+}
+
+func encode1251(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := charmap.Windows1251.NewEncoder().String(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(b)
+}
+
+func TestEnvelope(t *testing.T) {
+	body, err := Envelope(echoRequest{NS: "http://web.cbr.ru/", Text: "hi"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := xml.Header + `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><Echo xmlns="http://web.cbr.ru/"><text>hi</text></Echo></soap:Body></soap:Envelope>`
+	if string(body) != expected {
+		t.Errorf("Unexpected envelope:\n%s\nexpected:\n%s", body, expected)
+	}
+}
+
+func TestCall_Success1251(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		if r.Header.Get("SOAPAction") != `"http://web.cbr.ru/Echo"` {
+			t.Errorf("Unexpected SOAPAction: %s", r.Header.Get("SOAPAction"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "<text>привет</text>") {
+			t.Errorf("Unexpected request body: %s", body)
+		}
+
+		// Кодировка указана только в заголовке
+		w.Header().Set("Content-Type", "text/xml; charset=windows-1251")
+		w.Write(encode1251(t, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><EchoResponse xmlns="http://web.cbr.ru/"><EchoResult>Ключевая ставка</EchoResult></EchoResponse></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	var resp echoResponse
+	err := NewClient(server.URL).Call(context.Background(), "http://web.cbr.ru/Echo", echoRequest{NS: "http://web.cbr.ru/", Text: "привет"}, &resp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Result != "Ключевая ставка" {
+		t.Errorf("Expected decoded text, got %q", resp.Result)
+	}
+}
+
+func TestCall_XMLDeclarationCharset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write(encode1251(t, `<?xml version="1.0" encoding="windows-1251"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><EchoResponse><EchoResult>Доллар</EchoResult></EchoResponse></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	var resp echoResponse
+	if err := NewClient(server.URL).Call(context.Background(), "Echo", echoRequest{}, &resp); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Result != "Доллар" {
+		t.Errorf("Expected decoded text, got %q", resp.Result)
+	}
+}
+
+func TestCall_Fault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=windows-1251")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(encode1251(t, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>soap:Client</faultcode><faultstring>Неверный формат даты</faultstring><detail /></soap:Fault></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	var resp echoResponse
+	err := NewClient(server.URL).Call(context.Background(), "Echo", echoRequest{}, &resp)
+
+	var fault *Fault
+	if !errors.As(err, &fault) {
+		t.Fatalf("Expected *Fault, got %v", err)
+	}
+	if fault.Code != "soap:Client" || fault.String != "Неверный формат даты" {
+		t.Errorf("Unexpected fault: %+v", fault)
+	}
+}
+
+func TestCall_BadStatusWithoutEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("Service Unavailable"))
+	}))
+	defer server.Close()
+
+	var resp echoResponse
+	err := NewClient(server.URL).Call(context.Background(), "Echo", echoRequest{}, &resp)
+	var statusErr *fetcher.HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Expected *fetcher.HTTPStatusError with 503, got %v", err)
+	}
+	if !errors.Is(err, fetcher.ErrUpstreamUnavailable) {
+		t.Errorf("Expected 503 to count as upstream unavailable")
+	}
+}
+
+func TestCall_NetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var resp echoResponse
+	err := NewClient(url).Call(context.Background(), "Echo", echoRequest{}, &resp)
+	if !errors.Is(err, fetcher.ErrUpstreamUnavailable) {
+		t.Fatalf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
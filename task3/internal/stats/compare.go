@@ -0,0 +1,99 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Delta — валюта в базовом (Base) и текущем (Current) периодах; nil — за
+// период курсов нет. Изменения среднего и экстремумов относительные
+// (Current/Base - 1), волатильности — разность долей. Изменения определены,
+// только если валюта есть в обоих периодах (Both).
+type Delta struct {
+	Base       *Currency
+	Current    *Currency
+	Avg        float64
+	Max        float64
+	Min        float64
+	Volatility float64
+}
+
+func (d Delta) Both() bool {
+	return d.Base != nil && d.Current != nil
+}
+
+func (d Delta) Code() string {
+	if d.Current != nil {
+		return d.Current.Code()
+	}
+	return d.Base.Code()
+}
+
+func (d Delta) Name() string {
+	if d.Current != nil {
+		return d.Current.Name
+	}
+	return d.Base.Name
+}
+
+// Compare сопоставляет валюты двух периодов по коду ЦБ (или буквенному
+// коду); результат упорядочен по коду.
+func Compare(base, current []Currency) []Delta {
+	byKey := make(map[string]*Delta)
+	var keys []string
+	add := func(c *Currency, set func(*Delta)) {
+		key := Key(c.Last)
+		d, ok := byKey[key]
+		if !ok {
+			d = &Delta{}
+			byKey[key] = d
+			keys = append(keys, key)
+		}
+		set(d)
+	}
+	for i := range base {
+		c := &base[i]
+		add(c, func(d *Delta) { d.Base = c })
+	}
+	for i := range current {
+		c := &current[i]
+		add(c, func(d *Delta) { d.Current = c })
+	}
+
+	result := make([]Delta, 0, len(keys))
+	for _, key := range keys {
+		d := *byKey[key]
+		if d.Both() {
+			d.Avg = relative(d.Base.Avg, d.Current.Avg)
+			d.Max = relative(d.Base.Max.Rate, d.Current.Max.Rate)
+			d.Min = relative(d.Base.Min.Rate, d.Current.Min.Rate)
+			d.Volatility = d.Current.Volatility - d.Base.Volatility
+		}
+		result = append(result, d)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Code() < result[j].Code() })
+	return result
+}
+
+// Movers — до n валют, есть в обоих периодах, по убыванию модуля изменения
+// среднего курса.
+func Movers(deltas []Delta, n int) []Delta {
+	var result []Delta
+	for _, d := range deltas {
+		if d.Both() {
+			result = append(result, d)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return math.Abs(result[i].Avg) > math.Abs(result[j].Avg) })
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+func relative(base, current float64) float64 {
+	if base == 0 {
+		return 0
+	}
+	return current/base - 1
+}
@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func TestCompare(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+	usd := func(m time.Month, rates ...float64) []model.CurrencyRate {
+		var result []model.CurrencyRate
+		for i, r := range rates {
+			result = append(result, model.CurrencyRate{ID: "R01235", CharCode: "USD", Rate: r, Date: day(m, i+1)})
+		}
+		return result
+	}
+	eur := model.CurrencyRate{ID: "R01239", CharCode: "EUR", Rate: 95, Date: day(7, 1)}
+	cny := model.CurrencyRate{ID: "R01375", CharCode: "CNY", Rate: 11, Date: day(4, 1)}
+
+	base := ByCurrency(append(usd(4, 80, 82, 84), cny))
+	current := ByCurrency(append(usd(7, 84, 88, 86), eur))
+	deltas := Compare(base, current)
+	if len(deltas) != 3 {
+		t.Fatalf("Expected 3 deltas, got %+v", deltas)
+	}
+
+	// По коду: CNY только в базовом, EUR только в текущем
+	if deltas[0].Code() != "CNY" || deltas[0].Current != nil || deltas[0].Both() {
+		t.Errorf("Unexpected CNY delta: %+v", deltas[0])
+	}
+	if deltas[1].Code() != "EUR" || deltas[1].Base != nil || deltas[1].Avg != 0 {
+		t.Errorf("Unexpected EUR delta: %+v", deltas[1])
+	}
+	d := deltas[2]
+	if d.Code() != "USD" || !d.Both() {
+		t.Fatalf("Unexpected USD delta: %+v", d)
+	}
+	if math.Abs(d.Avg-(86.0/82-1)) > 1e-12 || math.Abs(d.Max-(88.0/84-1)) > 1e-12 || math.Abs(d.Min-(84.0/80-1)) > 1e-12 {
+		t.Errorf("Unexpected changes: %+v", d)
+	}
+	if math.Abs(d.Volatility-(d.Current.Volatility-d.Base.Volatility)) > 1e-12 {
+		t.Errorf("Unexpected volatility change: %v", d.Volatility)
+	}
+}
+
+func TestMovers(t *testing.T) {
+	deltas := []Delta{
+		{Base: &Currency{}, Current: &Currency{CharCode: "USD"}, Avg: 0.01},
+		{Base: &Currency{}, Current: &Currency{CharCode: "EUR"}, Avg: -0.05},
+		{Current: &Currency{CharCode: "CNY"}},
+		{Base: &Currency{}, Current: &Currency{CharCode: "GBP"}, Avg: 0.03},
+	}
+	got := Movers(deltas, 2)
+	if len(got) != 2 || got[0].Code() != "EUR" || got[1].Code() != "GBP" {
+		t.Errorf("Unexpected movers: %+v", got)
+	}
+}
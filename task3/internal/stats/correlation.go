@@ -0,0 +1,209 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"task3/internal/model"
+)
+
+type CorrelationMethod string
+
+const (
+	Pearson  CorrelationMethod = "pearson"
+	Spearman CorrelationMethod = "spearman"
+)
+
+// minObservations — меньше общих доходностей корреляция не считается.
+const minObservations = 3
+
+// Returns — дневные логарифмические доходности валют на общей сетке дат.
+// Сетка — даты, на которые официальный курс есть хотя бы у одной валюты.
+// Доходность на дату k определена, только если у валюты есть курс и на k, и
+// на предыдущую дату сетки: пропуск не растягивает доходность на несколько
+// дней, а делает её неизвестной (NaN). Восстановленные политикой пропусков
+// курсы не участвуют.
+type Returns struct {
+	Dates  []time.Time
+	Codes  []string
+	Values [][]float64
+}
+
+func AlignReturns(currencies []Currency) Returns {
+	known := make(map[time.Time]bool)
+	rates := make([]map[time.Time]float64, len(currencies))
+	for i, c := range currencies {
+		rates[i] = make(map[time.Time]float64, len(c.Points))
+		for _, p := range c.Points {
+			if p.Filled || p.Rate <= 0 {
+				continue
+			}
+			rates[i][p.Date] = p.Rate
+			known[p.Date] = true
+		}
+	}
+
+	r := Returns{Dates: make([]time.Time, 0, len(known))}
+	for d := range known {
+		r.Dates = append(r.Dates, d)
+	}
+	sort.Slice(r.Dates, func(i, j int) bool { return r.Dates[i].Before(r.Dates[j]) })
+
+	for i, c := range currencies {
+		r.Codes = append(r.Codes, c.Code())
+		values := make([]float64, len(r.Dates))
+		for k := range values {
+			values[k] = math.NaN()
+			if k == 0 {
+				continue
+			}
+			prev, okPrev := rates[i][r.Dates[k-1]]
+			cur, ok := rates[i][r.Dates[k]]
+			if ok && okPrev {
+				values[k] = math.Log(cur / prev)
+			}
+		}
+		r.Values = append(r.Values, values)
+	}
+	return r
+}
+
+// CorrelationMatrix — попарные корреляции доходностей. Для каждой пары
+// берутся даты, где известны обе доходности (N); при N < 3 или постоянном
+// ряде значение — NaN.
+type CorrelationMatrix struct {
+	Method CorrelationMethod
+	Codes  []string
+	R      [][]float64
+	N      [][]int
+}
+
+func (r Returns) Correlation(method CorrelationMethod) CorrelationMatrix {
+	n := len(r.Codes)
+	m := CorrelationMatrix{Method: method, Codes: r.Codes, R: make([][]float64, n), N: make([][]int, n)}
+	for i := 0; i < n; i++ {
+		m.R[i] = make([]float64, n)
+		m.N[i] = make([]int, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			x, y := pairwise(r.Values[i], r.Values[j], 0, len(r.Dates))
+			v := correlation(method, x, y)
+			if i == j && !math.IsNaN(v) {
+				v = 1
+			}
+			m.R[i][j], m.R[j][i] = v, v
+			m.N[i][j], m.N[j][i] = len(x), len(x)
+		}
+	}
+	return m
+}
+
+// Rolling — корреляция Пирсона доходностей валют a и b в скользящем окне из
+// window дат сетки. Точка ставится на последнюю дату окна, если в нём не
+// меньше половины общих доходностей (и не меньше трёх).
+func (r Returns) Rolling(a, b string, window int) ([]model.Point, error) {
+	i, j := r.index(a), r.index(b)
+	if i < 0 {
+		return nil, fmt.Errorf("no series for %s", a)
+	}
+	if j < 0 {
+		return nil, fmt.Errorf("no series for %s", b)
+	}
+	if window < minObservations {
+		return nil, fmt.Errorf("rolling window must be at least %d, got %d", minObservations, window)
+	}
+
+	need := max(minObservations, window/2)
+	var points []model.Point
+	for k := window; k <= len(r.Dates); k++ {
+		x, y := pairwise(r.Values[i], r.Values[j], k-window, k)
+		if len(x) < need {
+			continue
+		}
+		if v := pearson(x, y); !math.IsNaN(v) {
+			points = append(points, model.Point{Date: r.Dates[k-1], Value: v})
+		}
+	}
+	return points, nil
+}
+
+func (r Returns) index(code string) int {
+	for i, c := range r.Codes {
+		if strings.EqualFold(c, code) {
+			return i
+		}
+	}
+	return -1
+}
+
+// pairwise отбирает из [from, to) даты, где известны обе доходности.
+func pairwise(a, b []float64, from, to int) ([]float64, []float64) {
+	var x, y []float64
+	for k := from; k < to; k++ {
+		if math.IsNaN(a[k]) || math.IsNaN(b[k]) {
+			continue
+		}
+		x = append(x, a[k])
+		y = append(y, b[k])
+	}
+	return x, y
+}
+
+func correlation(method CorrelationMethod, x, y []float64) float64 {
+	if method == Spearman {
+		return pearson(ranks(x), ranks(y))
+	}
+	return pearson(x, y)
+}
+
+func pearson(x, y []float64) float64 {
+	if len(x) < minObservations {
+		return math.NaN()
+	}
+	var mx, my float64
+	for i := range x {
+		mx += x[i]
+		my += y[i]
+	}
+	mx /= float64(len(x))
+	my /= float64(len(y))
+
+	var cov, vx, vy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(vx*vy)
+}
+
+// ranks — ранги значений; одинаковым значениям — средний ранг.
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+
+	result := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			result[order[k]] = rank
+		}
+		i = j + 1
+	}
+	return result
+}
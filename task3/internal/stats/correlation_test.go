@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func series(code string, rates ...float64) Currency {
+	c := Currency{CharCode: code}
+	for i, r := range rates {
+		if r == 0 {
+			// Нет курса на эту дату
+			continue
+		}
+		c.Points = append(c.Points, model.CurrencyRate{CharCode: code, Rate: r, Date: time.Date(2025, 10, i+1, 0, 0, 0, 0, time.UTC)})
+	}
+	return c
+}
+
+func TestAlignReturns_MissingDates(t *testing.T) {
+	// У EUR нет курса на 3-е: доходности на 3-е и 4-е неизвестны, а не
+	// растянуты на два дня
+	r := AlignReturns([]Currency{series("USD", 80, 81, 82, 83), series("EUR", 90, 91, 0, 93)})
+	if len(r.Dates) != 4 {
+		t.Fatalf("Expected 4 dates, got %v", r.Dates)
+	}
+	eur := r.Values[1]
+	if !math.IsNaN(eur[0]) || math.IsNaN(eur[1]) || !math.IsNaN(eur[2]) || !math.IsNaN(eur[3]) {
+		t.Errorf("Unexpected EUR returns: %v", eur)
+	}
+	if got := r.Values[0][1]; math.Abs(got-math.Log(81.0/80)) > 1e-12 {
+		t.Errorf("Unexpected USD return: %v", got)
+	}
+}
+
+func TestReturns_Correlation(t *testing.T) {
+	// CNY движется как USD, JPY — против
+	r := AlignReturns([]Currency{
+		series("USD", 80, 81, 80.5, 82, 81, 83),
+		series("CNY", 11, 11.2, 11.1, 11.4, 11.2, 11.5),
+		series("JPY", 0.6, 0.59, 0.595, 0.58, 0.59, 0.57),
+	})
+
+	for _, method := range []CorrelationMethod{Pearson, Spearman} {
+		m := r.Correlation(method)
+		if m.R[0][0] != 1 || m.N[0][1] != 5 {
+			t.Errorf("%s: unexpected diagonal or N: %v %v", method, m.R[0][0], m.N)
+		}
+		if m.R[0][1] < 0.9 || m.R[0][1] != m.R[1][0] {
+			t.Errorf("%s: USD/CNY must be strongly positive: %v", method, m.R)
+		}
+		if m.R[0][2] > -0.9 {
+			t.Errorf("%s: USD/JPY must be strongly negative: %v", method, m.R[0][2])
+		}
+	}
+}
+
+func TestReturns_CorrelationNotEnoughData(t *testing.T) {
+	r := AlignReturns([]Currency{series("USD", 80, 81, 82), series("EUR", 90, 91, 92)})
+	if m := r.Correlation(Pearson); !math.IsNaN(m.R[0][1]) || m.N[0][1] != 2 {
+		t.Errorf("Expected NaN for 2 observations: %v %v", m.R, m.N)
+	}
+}
+
+func TestRanks(t *testing.T) {
+	got := ranks([]float64{10, 30, 20, 30})
+	want := []float64{1, 3.5, 2, 3.5}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ranks = %v, expected %v", got, want)
+		}
+	}
+}
+
+func TestReturns_Rolling(t *testing.T) {
+	r := AlignReturns([]Currency{
+		series("USD", 80, 81, 80.5, 82, 81, 83, 82),
+		series("EUR", 90, 91, 90.5, 92, 91, 93, 92),
+	})
+	points, err := r.Rolling("usd", "EUR", 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Первое окно (даты 1–4) содержит три доходности
+	if len(points) != 4 || !points[0].Date.Equal(r.Dates[3]) {
+		t.Fatalf("Unexpected points: %+v", points)
+	}
+	for _, p := range points {
+		if p.Value < 0.9 {
+			t.Errorf("Expected strong correlation, got %+v", p)
+		}
+	}
+
+	if _, err := r.Rolling("USD", "GBP", 4); err == nil {
+		t.Error("Expected error for unknown currency")
+	}
+	if _, err := r.Rolling("USD", "EUR", 2); err == nil {
+		t.Error("Expected error for too small window")
+	}
+}
@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"task3/internal/model"
+)
+
+// Currency — статистика одной валюты за период.
+type Currency struct {
+	ID       string
+	CharCode string
+	Name     string
+	Stats
+	First model.CurrencyRate
+	Last  model.CurrencyRate
+	// Change — относительное изменение курса за период: Last/First - 1.
+	Change float64
+	// Volatility — стандартное отклонение дневных изменений курса (доля).
+	Volatility float64
+	// Points — курсы валюты по возрастанию дат.
+	Points []model.CurrencyRate
+	// Filled — сколько из них восстановлено политикой пропусков.
+	Filled int
+	// Moves — просадка, рост, крупнейшие изменения и серии; nil, если
+	// анализ не запрошен.
+	Moves *Moves
+}
+
+// Key идентифицирует серию валюты: код ЦБ, если он есть, иначе буквенный
+// код или название (ЕЦБ, драгметаллы).
+func Key(r model.CurrencyRate) string {
+	switch {
+	case r.ID != "":
+		return r.ID
+	case r.CharCode != "":
+		return r.CharCode
+	default:
+		return r.Name
+	}
+}
+
+// ByCurrency считает статистику по каждой валюте; результат упорядочен по
+// буквенному коду (или названию, если кода нет).
+func ByCurrency(rates []model.CurrencyRate) []Currency {
+	series := make(map[string][]model.CurrencyRate)
+	for _, r := range rates {
+		key := Key(r)
+		series[key] = append(series[key], r)
+	}
+
+	result := make([]Currency, 0, len(series))
+	for _, points := range series {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Date.Before(points[j].Date)
+		})
+		s, err := Compute(points)
+		if err != nil {
+			continue
+		}
+		first, last := points[0], points[len(points)-1]
+		c := Currency{
+			ID:       last.ID,
+			CharCode: last.CharCode,
+			Name:     last.Name,
+			Stats:    s,
+			First:    first,
+			Last:     last,
+			Points:   points,
+		}
+		if first.Rate != 0 {
+			c.Change = last.Rate/first.Rate - 1
+		}
+		c.Volatility = Volatility(points)
+		for _, p := range points {
+			if p.Filled {
+				c.Filled++
+			}
+		}
+		result = append(result, c)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code() < result[j].Code()
+	})
+	return result
+}
+
+// Code — короткое обозначение валюты для таблиц.
+func (c Currency) Code() string {
+	if c.CharCode != "" {
+		return c.CharCode
+	}
+	return c.Name
+}
+
+// Volatility — выборочное стандартное отклонение относительных изменений
+// между соседними курсами ряда. Для рядов короче трёх точек возвращает 0.
+func Volatility(points []model.CurrencyRate) float64 {
+	var returns []float64
+	for i := 1; i < len(points); i++ {
+		if points[i-1].Rate == 0 {
+			continue
+		}
+		returns = append(returns, points[i].Rate/points[i-1].Rate-1)
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var sum float64
+	for _, r := range returns {
+		sum += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(sum / float64(len(returns)-1))
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func TestByCurrency(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	// Порядок вперемешку, как из map[time.Time][]CurrencyRate
+	rates := []model.CurrencyRate{
+		{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: 92, Date: day(22)},
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 81, Date: day(21)},
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 80, Date: day(20)},
+		{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: 95, Date: day(20)},
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: 84, Date: day(22)},
+		{Name: "Gold", Rate: 10000, Date: day(20)},
+	}
+
+	result := ByCurrency(rates)
+	if len(result) != 3 {
+		t.Fatalf("Expected 3 currencies, got %d", len(result))
+	}
+
+	// EUR, Gold (по названию), USD
+	if result[0].Code() != "EUR" || result[1].Code() != "Gold" || result[2].Code() != "USD" {
+		t.Fatalf("Unexpected order: %s, %s, %s", result[0].Code(), result[1].Code(), result[2].Code())
+	}
+
+	usd := result[2]
+	if usd.Count != 3 || usd.Min.Rate != 80 || usd.Max.Rate != 84 || usd.Avg != 81.66666666666667 {
+		t.Errorf("Unexpected USD stats: %+v", usd.Stats)
+	}
+	if !usd.First.Date.Equal(day(20)) || !usd.Last.Date.Equal(day(22)) {
+		t.Errorf("Unexpected USD first/last: %v %v", usd.First.Date, usd.Last.Date)
+	}
+	for i := 1; i < len(usd.Points); i++ {
+		if !usd.Points[i-1].Date.Before(usd.Points[i].Date) {
+			t.Errorf("Points are not sorted: %+v", usd.Points)
+		}
+	}
+	if math.Abs(usd.Change-0.05) > 1e-12 {
+		t.Errorf("Expected USD change 5%%, got %.6f", usd.Change)
+	}
+	if math.Abs(result[0].Change-(92.0/95-1)) > 1e-12 {
+		t.Errorf("Unexpected EUR change: %.6f", result[0].Change)
+	}
+}
+
+func TestKey(t *testing.T) {
+	if got := Key(model.CurrencyRate{ID: "R01235", CharCode: "USD", Name: "US Dollar"}); got != "R01235" {
+		t.Errorf("Expected ID, got %q", got)
+	}
+	if got := Key(model.CurrencyRate{CharCode: "USD", Name: "USD"}); got != "USD" {
+		t.Errorf("Expected CharCode, got %q", got)
+	}
+	if got := Key(model.CurrencyRate{Name: "Gold"}); got != "Gold" {
+		t.Errorf("Expected Name, got %q", got)
+	}
+}
+
+func TestVolatility(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	rates := func(values ...float64) []model.CurrencyRate {
+		var result []model.CurrencyRate
+		for i, v := range values {
+			result = append(result, model.CurrencyRate{Rate: v, Date: day(i + 1)})
+		}
+		return result
+	}
+
+	// Постоянный рост на 10% — изменения одинаковы, разброса нет
+	if v := Volatility(rates(100, 110, 121)); math.Abs(v) > 1e-12 {
+		t.Errorf("Expected zero volatility, got %.6f", v)
+	}
+	// Изменения +10% и -10%: среднее 0, выборочное отклонение sqrt(0.02)
+	if v := Volatility(rates(100, 110, 99)); math.Abs(v-math.Sqrt(0.02)) > 1e-12 {
+		t.Errorf("Unexpected volatility: %.6f", v)
+	}
+	if v := Volatility(rates(100, 110)); v != 0 {
+		t.Errorf("Expected 0 for a single change, got %.6f", v)
+	}
+}
@@ -0,0 +1,60 @@
+package stats
+
+import "task3/internal/model"
+
+// Drawdown — наибольшее падение ряда от предшествующего максимума (Peak) до
+// следующего за ним минимума (Trough). Depth — падение в долях, 0 — ряд
+// не опускался ниже достигнутого максимума.
+type Drawdown struct {
+	Depth  float64
+	Peak   model.Point
+	Trough model.Point
+}
+
+// MaxDrawdown находит максимальную просадку ряда, упорядоченного по датам.
+// Точки с неположительным значением пропускаются.
+func MaxDrawdown(points []model.Point) Drawdown {
+	var result Drawdown
+	var peak model.Point
+	for _, p := range points {
+		if p.Value <= 0 {
+			continue
+		}
+		if p.Value > peak.Value {
+			peak = p
+			continue
+		}
+		if depth := 1 - p.Value/peak.Value; depth > result.Depth {
+			result = Drawdown{Depth: depth, Peak: peak, Trough: p}
+		}
+	}
+	return result
+}
+
+// RunUp — наибольший рост ряда от предшествующего минимума (Trough) до
+// следующего за ним максимума (Peak). Rise — рост в долях.
+type RunUp struct {
+	Rise   float64
+	Trough model.Point
+	Peak   model.Point
+}
+
+// MaxRunUp находит максимальный рост ряда, упорядоченного по датам.
+// Точки с неположительным значением пропускаются.
+func MaxRunUp(points []model.Point) RunUp {
+	var result RunUp
+	var trough model.Point
+	for _, p := range points {
+		if p.Value <= 0 {
+			continue
+		}
+		if trough.Value == 0 || p.Value < trough.Value {
+			trough = p
+			continue
+		}
+		if rise := p.Value/trough.Value - 1; rise > result.Rise {
+			result = RunUp{Rise: rise, Trough: trough, Peak: p}
+		}
+	}
+	return result
+}
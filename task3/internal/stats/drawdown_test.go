@@ -0,0 +1,26 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func TestMaxDrawdown(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	var points []model.Point
+	// Первое падение 100→90, второе глубже: 120→96
+	for i, v := range []float64{100, 90, 120, 110, 96, 130} {
+		points = append(points, model.Point{Date: day(i + 1), Value: v})
+	}
+	d := MaxDrawdown(points)
+	if math.Abs(d.Depth-0.2) > 1e-12 || d.Peak.Value != 120 || !d.Trough.Date.Equal(day(5)) {
+		t.Errorf("Unexpected drawdown: %+v", d)
+	}
+
+	if d := MaxDrawdown(points[:1]); d.Depth != 0 || !d.Peak.Date.IsZero() {
+		t.Errorf("Single point must have no drawdown: %+v", d)
+	}
+}
@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"sort"
+
+	"task3/internal/model"
+)
+
+// Move — изменение между соседними официальными курсами (дневное; через
+// выходные — от последнего курса перед ними). Change — в долях: To/From - 1.
+type Move struct {
+	From   model.CurrencyRate
+	To     model.CurrencyRate
+	Change float64
+}
+
+// Streak — серия дневных изменений одного знака подряд: Length изменений
+// от курса From до курса To, Change — суммарное изменение в долях.
+type Streak struct {
+	Length int
+	From   model.CurrencyRate
+	To     model.CurrencyRate
+	Change float64
+}
+
+// Moves — просадка и рост курса с датами, крупнейшие дневные изменения и
+// самые длинные серии роста и падения.
+type Moves struct {
+	Drawdown Drawdown
+	RunUp    RunUp
+	// Gains и Losses — крупнейшие дневные рост и падение, по убыванию
+	// модуля изменения.
+	Gains  []Move
+	Losses []Move
+	Up     Streak
+	Down   Streak
+}
+
+// ComputeMoves анализирует ряд курсов, упорядоченный по датам; top —
+// сколько крупнейших дневных изменений каждого знака оставить.
+// Восстановленные политикой пропусков курсы не участвуют. При равной длине
+// серий берётся более ранняя.
+func ComputeMoves(points []model.CurrencyRate, top int) Moves {
+	var official []model.CurrencyRate
+	var values []model.Point
+	for _, p := range points {
+		if p.Filled || p.Rate <= 0 {
+			continue
+		}
+		official = append(official, p)
+		values = append(values, model.Point{Date: p.Date, Value: p.Rate})
+	}
+
+	m := Moves{Drawdown: MaxDrawdown(values), RunUp: MaxRunUp(values)}
+	var up, down Streak
+	for i := 1; i < len(official); i++ {
+		move := Move{From: official[i-1], To: official[i], Change: official[i].Rate/official[i-1].Rate - 1}
+		switch {
+		case move.Change > 0:
+			m.Gains = append(m.Gains, move)
+			up = extend(up, move)
+			down = Streak{}
+		case move.Change < 0:
+			m.Losses = append(m.Losses, move)
+			down = extend(down, move)
+			up = Streak{}
+		default:
+			up, down = Streak{}, Streak{}
+		}
+		if up.Length > m.Up.Length {
+			m.Up = up
+		}
+		if down.Length > m.Down.Length {
+			m.Down = down
+		}
+	}
+
+	sort.SliceStable(m.Gains, func(i, j int) bool { return m.Gains[i].Change > m.Gains[j].Change })
+	sort.SliceStable(m.Losses, func(i, j int) bool { return m.Losses[i].Change < m.Losses[j].Change })
+	if len(m.Gains) > top {
+		m.Gains = m.Gains[:top]
+	}
+	if len(m.Losses) > top {
+		m.Losses = m.Losses[:top]
+	}
+	return m
+}
+
+// extend продолжает серию изменением move.
+func extend(s Streak, move Move) Streak {
+	if s.Length == 0 {
+		s.From = move.From
+	}
+	s.Length++
+	s.To = move.To
+	s.Change = s.To.Rate/s.From.Rate - 1
+	return s
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func TestComputeMoves(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+	var points []model.CurrencyRate
+	// Рост 100→110 за три дня, падение до 88, восстановленный курс 200 не в счёт
+	for i, r := range []float64{100, 102, 105, 110, 99, 88, 88, 90} {
+		points = append(points, model.CurrencyRate{CharCode: "USD", Rate: r, Date: day(i + 1)})
+	}
+	points = append(points, model.CurrencyRate{CharCode: "USD", Rate: 200, Date: day(9), Filled: true})
+
+	m := ComputeMoves(points, 2)
+	if math.Abs(m.Drawdown.Depth-0.2) > 1e-12 || !m.Drawdown.Peak.Date.Equal(day(4)) || !m.Drawdown.Trough.Date.Equal(day(6)) {
+		t.Errorf("Unexpected drawdown: %+v", m.Drawdown)
+	}
+	if math.Abs(m.RunUp.Rise-0.1) > 1e-12 || !m.RunUp.Trough.Date.Equal(day(1)) || m.RunUp.Peak.Value != 110 {
+		t.Errorf("Unexpected run-up: %+v", m.RunUp)
+	}
+
+	if len(m.Gains) != 2 || m.Gains[0].To.Rate != 110 || m.Gains[1].To.Rate != 105 {
+		t.Errorf("Unexpected gains: %+v", m.Gains)
+	}
+	if len(m.Losses) != 2 || m.Losses[0].To.Rate != 88 || m.Losses[1].To.Rate != 99 {
+		t.Errorf("Unexpected losses: %+v", m.Losses)
+	}
+
+	if m.Up.Length != 3 || m.Up.From.Rate != 100 || m.Up.To.Rate != 110 || math.Abs(m.Up.Change-0.1) > 1e-12 {
+		t.Errorf("Unexpected up streak: %+v", m.Up)
+	}
+	// Неизменный курс 88→88 обрывает серию
+	if m.Down.Length != 2 || !m.Down.From.Date.Equal(day(4)) || !m.Down.To.Date.Equal(day(6)) {
+		t.Errorf("Unexpected down streak: %+v", m.Down)
+	}
+}
+
+func TestComputeMoves_Short(t *testing.T) {
+	m := ComputeMoves([]model.CurrencyRate{{Rate: 80}}, 3)
+	if m.Drawdown.Depth != 0 || m.RunUp.Rise != 0 || m.Gains != nil || m.Losses != nil || m.Up.Length != 0 || m.Down.Length != 0 {
+		t.Errorf("Single rate must have no moves: %+v", m)
+	}
+}
@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"errors"
+
+	"task3/internal/model"
+)
+
+var ErrNoData = errors.New("no rate data found to calculate statistics")
+
+type Stats struct {
+	Max   model.CurrencyRate
+	Min   model.CurrencyRate
+	Avg   float64
+	Count int
+}
+
+func Compute(rates []model.CurrencyRate) (Stats, error) {
+	var s Stats
+	var total float64
+
+	for _, r := range rates {
+		if s.Count == 0 {
+			s.Min = r
+			s.Max = r
+		}
+		if r.Rate < s.Min.Rate {
+			s.Min = r
+		}
+		if r.Rate > s.Max.Rate {
+			s.Max = r
+		}
+		total += r.Rate
+		s.Count++
+	}
+
+	if s.Count == 0 {
+		return Stats{}, ErrNoData
+	}
+
+	s.Avg = total / float64(s.Count)
+	return s, nil
+}
@@ -0,0 +1,36 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func TestCompute(t *testing.T) {
+	day := time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)
+	rates := []model.CurrencyRate{
+		{Name: "USD", Rate: 80, Date: day},
+		{Name: "EUR", Rate: 90, Date: day},
+		{Name: "CNY", Rate: 10, Date: day},
+	}
+
+	s, err := Compute(rates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.Max.Name != "EUR" || s.Min.Name != "CNY" {
+		t.Errorf("Unexpected extremes: max=%+v min=%+v", s.Max, s.Min)
+	}
+	if s.Avg != 60 || s.Count != 3 {
+		t.Errorf("Expected avg 60 over 3 rates, got %.4f over %d", s.Avg, s.Count)
+	}
+}
+
+func TestCompute_Empty(t *testing.T) {
+	_, err := Compute(nil)
+	if !errors.Is(err, ErrNoData) {
+		t.Fatalf("Expected ErrNoData, got %v", err)
+	}
+}
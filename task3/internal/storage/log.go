@@ -0,0 +1,374 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"task3/internal/model"
+	"task3/internal/stats"
+)
+
+const dayFormat = "2006-01-02"
+
+// record — строка журнала: курс одной валюты на день запроса и время, когда
+// он записан. В журналах до учёта исправлений времени нет — такие курсы
+// считаются известными всегда. Removed — валюта пропала из дня; курс в
+// такой строке — последний известный.
+type record struct {
+	Day      string    `json:"day"`
+	ID       string    `json:"id,omitempty"`
+	CharCode string    `json:"code,omitempty"`
+	Name     string    `json:"name"`
+	Rate     float64   `json:"rate"`
+	Date     string    `json:"date"`
+	At       time.Time `json:"at,omitzero"`
+	Removed  bool      `json:"removed,omitempty"`
+}
+
+func newRecord(d time.Time, r model.CurrencyRate, at time.Time) record {
+	return record{
+		Day:      d.Format(dayFormat),
+		ID:       r.ID,
+		CharCode: r.CharCode,
+		Name:     r.Name,
+		Rate:     r.Rate,
+		Date:     r.Date.Format(dayFormat),
+		At:       at,
+	}
+}
+
+func (rec record) parse() (time.Time, model.CurrencyRate, error) {
+	d, err := time.Parse(dayFormat, rec.Day)
+	if err != nil {
+		return time.Time{}, model.CurrencyRate{}, err
+	}
+	date, err := time.Parse(dayFormat, rec.Date)
+	if err != nil {
+		return time.Time{}, model.CurrencyRate{}, err
+	}
+	return d, model.CurrencyRate{ID: rec.ID, CharCode: rec.CharCode, Name: rec.Name, Rate: rec.Rate, Date: date}, nil
+}
+
+// version — значение курса и время, с которого оно известно; removed —
+// с этого времени валюты в дне нет.
+type version struct {
+	rate    model.CurrencyRate
+	at      time.Time
+	removed bool
+}
+
+// day — версии курсов одного дня; keys — валюты в порядке первой записи.
+type day struct {
+	keys     []string
+	versions map[string][]version
+}
+
+// LogStore — хранилище в одном файле: журнал JSON-строк, в который только
+// дописывают, и индекс в памяти, собранный при открытии. Новое значение
+// курса не стирает прежнее: журнал хранит историю исправлений.
+type LogStore struct {
+	mu   sync.Mutex
+	file *os.File
+	days map[time.Time]*day
+	now  func() time.Time
+}
+
+type Option func(*LogStore)
+
+// WithClock подменяет часы, по которым записывается время курсов.
+func WithClock(now func() time.Time) Option {
+	return func(s *LogStore) {
+		s.now = now
+	}
+}
+
+// Open открывает журнал, создавая его при необходимости. Недописанная
+// последняя строка (процесс прервали во время записи) отрезается.
+func Open(path string, opts ...Option) (*LogStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	s := &LogStore{file: f, days: make(map[time.Time]*day), now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := s.load(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *LogStore) load() error {
+	r := bufio.NewReader(s.file)
+	var offset int64
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				return s.truncate(offset)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			// Битая строка допустима только последней
+			if _, peekErr := r.Peek(1); errors.Is(peekErr, io.EOF) {
+				return s.truncate(offset)
+			}
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		d, rate, err := rec.parse()
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		s.set(d, rate, rec.At, rec.Removed)
+		offset += int64(len(line))
+	}
+	_, err := s.file.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (s *LogStore) truncate(offset int64) error {
+	if err := s.file.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := s.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+// set добавляет версию курса. Другие название или код при том же значении
+// — не исправление: последняя версия обновляется на месте. Удаление валюты,
+// которой в дне нет, ничего не меняет.
+func (s *LogStore) set(d time.Time, r model.CurrencyRate, at time.Time, removed bool) {
+	dd, ok := s.days[d]
+	if !ok {
+		if removed {
+			return
+		}
+		dd = &day{versions: make(map[string][]version)}
+		s.days[d] = dd
+	}
+	k := stats.Key(r)
+	versions, ok := dd.versions[k]
+	n := len(versions)
+	switch {
+	case removed && (n == 0 || versions[n-1].removed):
+		return
+	case !removed && n > 0 && !versions[n-1].removed && sameValue(versions[n-1].rate, r):
+		versions[n-1].rate = r
+		return
+	}
+	if !ok {
+		dd.keys = append(dd.keys, k)
+	}
+	dd.versions[k] = append(versions, version{rate: r, at: at, removed: removed})
+}
+
+// latest возвращает последнюю версию курса валюты r на день d.
+func (s *LogStore) latest(d time.Time, r model.CurrencyRate) (model.CurrencyRate, bool) {
+	dd, ok := s.days[d]
+	if !ok {
+		return model.CurrencyRate{}, false
+	}
+	versions := dd.versions[stats.Key(r)]
+	if len(versions) == 0 || versions[len(versions)-1].removed {
+		return model.CurrencyRate{}, false
+	}
+	return versions[len(versions)-1].rate, true
+}
+
+func (s *LogStore) Put(ctx context.Context, date time.Time, rates []model.CurrencyRate) error {
+	return s.write(ctx, date, rates, false)
+}
+
+// Remove отмечает, что валют rates больше нет в дне date: источник
+// переопубликовал день без них. Прежние значения остаются в истории.
+func (s *LogStore) Remove(ctx context.Context, date time.Time, rates []model.CurrencyRate) error {
+	return s.write(ctx, date, rates, true)
+}
+
+// write дописывает в журнал курсы, которые меняют день: новые значения или,
+// при removed, удаление валют, которые в дне есть.
+func (s *LogStore) write(ctx context.Context, date time.Time, rates []model.CurrencyRate, removed bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d := Day(date)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at := s.now().UTC()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	var changed []model.CurrencyRate
+	for _, r := range rates {
+		r.Date = Day(r.Date)
+		old, ok := s.latest(d, r)
+		if removed {
+			if !ok {
+				continue
+			}
+			r = old
+		} else if ok && same(old, r) {
+			continue
+		}
+		rec := newRecord(d, r, at)
+		rec.Removed = removed
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+		changed = append(changed, r)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	// Недописанный пакет откатывается, чтобы следующая запись не легла
+	// после обрывка строки
+	offset, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		s.truncate(offset)
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	for _, r := range changed {
+		s.set(d, r, at, removed)
+	}
+	return nil
+}
+
+// sameValue — курс не исправлялся: то же значение на ту же дату.
+func sameValue(a, b model.CurrencyRate) bool {
+	return a.Rate == b.Rate && a.Date.Equal(b.Date)
+}
+
+// same — запись ничего не добавит к журналу. Название не входит в версию:
+// тот же день на другом языке не переписывает журнал.
+func same(a, b model.CurrencyRate) bool {
+	return sameValue(a, b) && a.ID == b.ID && a.CharCode == b.CharCode
+}
+
+func (s *LogStore) Range(ctx context.Context, from, to time.Time) (map[time.Time][]model.CurrencyRate, error) {
+	return s.rangeAsOf(ctx, from, to, time.Time{})
+}
+
+func (s *LogStore) RangeAsOf(ctx context.Context, from, to, asOf time.Time) (map[time.Time][]model.CurrencyRate, error) {
+	if asOf.IsZero() {
+		return nil, fmt.Errorf("as-of time is not set")
+	}
+	return s.rangeAsOf(ctx, from, to, asOf)
+}
+
+// rangeAsOf — нулевой asOf значит «последние значения».
+func (s *LogStore) rangeAsOf(ctx context.Context, from, to, asOf time.Time) (map[time.Time][]model.CurrencyRate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	from, to = Day(from), Day(to)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[time.Time][]model.CurrencyRate)
+	for d, dd := range s.days {
+		if d.Before(from) || d.After(to) {
+			continue
+		}
+		var rates []model.CurrencyRate
+		for _, k := range dd.keys {
+			if v, ok := versionAsOf(dd.versions[k], asOf); ok && !v.removed {
+				rates = append(rates, v.rate)
+			}
+		}
+		if len(rates) > 0 {
+			result[d] = rates
+		}
+	}
+	return result, nil
+}
+
+func versionAsOf(versions []version, asOf time.Time) (version, bool) {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if asOf.IsZero() || !versions[i].at.After(asOf) {
+			return versions[i], true
+		}
+	}
+	return version{}, false
+}
+
+// Revisions собирает исправления из истории версий: смену значения курса,
+// валюты, дописанные в день позже первой записи этого дня, и удалённые из
+// дня.
+func (s *LogStore) Revisions(ctx context.Context, from, to time.Time) ([]model.Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	from, to = Day(from), Day(to)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var revisions []model.Revision
+	for d, dd := range s.days {
+		if d.Before(from) || d.After(to) {
+			continue
+		}
+		first := dd.versions[dd.keys[0]][0].at
+		for _, k := range dd.keys {
+			if at := dd.versions[k][0].at; at.Before(first) {
+				first = at
+			}
+		}
+		for _, k := range dd.keys {
+			versions := dd.versions[k]
+			if versions[0].at.After(first) {
+				revisions = append(revisions, model.Revision{Day: d, New: versions[0].rate, Detected: versions[0].at})
+			}
+			for i := 1; i < len(versions); i++ {
+				rev := model.Revision{Day: d, Old: versions[i-1].rate, New: versions[i].rate, Detected: versions[i].at}
+				switch {
+				case versions[i].removed:
+					rev.New = model.CurrencyRate{}
+				case versions[i-1].removed:
+					rev.Old = model.CurrencyRate{}
+				}
+				revisions = append(revisions, rev)
+			}
+		}
+	}
+	sort.SliceStable(revisions, func(i, j int) bool {
+		if !revisions[i].Day.Equal(revisions[j].Day) {
+			return revisions[i].Day.Before(revisions[j].Day)
+		}
+		if !revisions[i].Detected.Equal(revisions[j].Detected) {
+			return revisions[i].Detected.Before(revisions[j].Detected)
+		}
+		return stats.Key(revisions[i].Rate()) < stats.Key(revisions[j].Rate())
+	})
+	return revisions, nil
+}
+
+func (s *LogStore) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task3/internal/model"
+)
+
+func oct(d int) time.Time {
+	return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC)
+}
+
+func rates(date time.Time, usd, eur float64) []model.CurrencyRate {
+	return []model.CurrencyRate{
+		{ID: "R01235", CharCode: "USD", Name: "US Dollar", Rate: usd, Date: date},
+		{ID: "R01239", CharCode: "EUR", Name: "Euro", Rate: eur, Date: date},
+	}
+}
+
+func open(t *testing.T, path string, opts ...Option) *LogStore {
+	t.Helper()
+	s, err := Open(path, opts...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func lines(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "\n")
+}
+
+func TestLogStore_PutRange(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	s := open(t, path)
+
+	if err := s.Put(ctx, oct(20), rates(oct(20), 80, 90)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Воскресенье: ЦБ отдаёт курсы субботы, день запроса хранится отдельно
+	if err := s.Put(ctx, oct(19).Add(15*time.Hour), rates(oct(18), 79, 89)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := s.Range(ctx, oct(19), oct(19))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 1 || len(got[oct(19)]) != 2 || !got[oct(19)][0].Date.Equal(oct(18)) {
+		t.Errorf("Unexpected range: %+v", got)
+	}
+
+	got, _ = s.Range(ctx, oct(1), oct(31))
+	if len(got) != 2 || got[oct(20)][1].Rate != 90 {
+		t.Errorf("Unexpected range: %+v", got)
+	}
+}
+
+func TestLogStore_IdempotentUpsert(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	s := open(t, path)
+
+	s.Put(ctx, oct(20), rates(oct(20), 80, 90))
+	s.Put(ctx, oct(20), rates(oct(20), 80, 90))
+	if n := lines(t, path); n != 2 {
+		t.Errorf("Repeated put must not append, got %d lines", n)
+	}
+
+	// Изменился только евро — дописывается одна строка
+	s.Put(ctx, oct(20), rates(oct(20), 80, 91))
+	if n := lines(t, path); n != 3 {
+		t.Errorf("Expected 3 lines, got %d", n)
+	}
+	got, _ := s.Range(ctx, oct(20), oct(20))
+	if len(got[oct(20)]) != 2 || got[oct(20)][1].Rate != 91 {
+		t.Errorf("Expected updated EUR, got %+v", got)
+	}
+
+	// После переоткрытия индекс тот же
+	s.Close()
+	s = open(t, path)
+	got, _ = s.Range(ctx, oct(20), oct(20))
+	if len(got[oct(20)]) != 2 || got[oct(20)][1].Rate != 91 || got[oct(20)][0].CharCode != "USD" {
+		t.Errorf("Unexpected rates after reopen: %+v", got)
+	}
+}
+
+func TestLogStore_TornTail(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	s := open(t, path)
+	s.Put(ctx, oct(20), rates(oct(20), 80, 90))
+	s.Close()
+
+	// Процесс прервали посреди записи строки
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"day":"2025-10-21","id":"R01`)
+	f.Close()
+
+	s = open(t, path)
+	if err := s.Put(ctx, oct(21), rates(oct(21), 81, 91)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	s.Close()
+
+	s = open(t, path)
+	got, _ := s.Range(ctx, oct(1), oct(31))
+	if len(got) != 2 || len(got[oct(21)]) != 2 {
+		t.Errorf("Unexpected rates: %+v", got)
+	}
+	if n := lines(t, path); n != 4 {
+		t.Errorf("Torn line must be cut, got %d lines", n)
+	}
+}
+
+func TestLogStore_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	os.WriteFile(path, []byte("not json\n{\"day\":\"2025-10-20\",\"name\":\"x\",\"rate\":1,\"date\":\"2025-10-20\"}\n"), 0o644)
+
+	if _, err := Open(path); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("Expected error for corrupted line, got %v", err)
+	}
+}
+
+func TestLogStore_History(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	clock := time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+	s := open(t, path, WithClock(func() time.Time { return clock }))
+
+	s.Put(ctx, oct(20), rates(oct(20), 80, 90))
+	first := clock
+	clock = clock.Add(48 * time.Hour)
+	s.Put(ctx, oct(20), rates(oct(20), 80.5, 90))
+	// Другое название — не исправление и не повод дописывать журнал
+	before, _ := os.ReadFile(path)
+	renamed := rates(oct(20), 80.5, 90)
+	renamed[1].Name = "Евро"
+	clock = clock.Add(time.Hour)
+	s.Put(ctx, oct(20), renamed)
+	if after, _ := os.ReadFile(path); len(after) != len(before) {
+		t.Errorf("Renamed rates must not be written:\n%s", after[len(before):])
+	}
+
+	// История переживает переоткрытие
+	s.Close()
+	s = open(t, path)
+
+	revisions, err := s.Revisions(ctx, oct(1), oct(31))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Old.Rate != 80 || revisions[0].New.Rate != 80.5 || !revisions[0].Detected.Equal(first.Add(48*time.Hour)) {
+		t.Errorf("Unexpected revisions: %+v", revisions)
+	}
+
+	asOf, _ := s.RangeAsOf(ctx, oct(20), oct(20), first.Add(time.Hour))
+	if got := asOf[oct(20)]; len(got) != 2 || got[0].Rate != 80 {
+		t.Errorf("Unexpected as-of rates: %+v", asOf)
+	}
+	// До первой записи день неизвестен
+	if asOf, _ := s.RangeAsOf(ctx, oct(20), oct(20), first.Add(-time.Hour)); len(asOf) != 0 {
+		t.Errorf("Expected no rates before first write, got %+v", asOf)
+	}
+	latest, _ := s.Range(ctx, oct(20), oct(20))
+	if got := latest[oct(20)]; got[0].Rate != 80.5 || got[1].Name != "Euro" {
+		t.Errorf("Unexpected latest rates: %+v", got)
+	}
+}
+
+func TestLogStore_Remove(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rates.jsonl")
+	clock := time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+	s := open(t, path, WithClock(func() time.Time { return clock }))
+
+	s.Put(ctx, oct(20), rates(oct(20), 80, 90))
+	clock = clock.Add(time.Hour)
+	eur := rates(oct(20), 80, 90)[1:]
+	s.Remove(ctx, oct(20), eur)
+	// Повторное удаление и удаление из пустого дня журнал не меняют
+	n := lines(t, path)
+	s.Remove(ctx, oct(20), eur)
+	s.Remove(ctx, oct(21), eur)
+	if lines(t, path) != n {
+		t.Errorf("Repeated removal must not be written")
+	}
+
+	// Удаление переживает переоткрытие
+	s.Close()
+	s = open(t, path, WithClock(func() time.Time { return clock }))
+	got, _ := s.Range(ctx, oct(20), oct(20))
+	if day := got[oct(20)]; len(day) != 1 || day[0].CharCode != "USD" {
+		t.Errorf("Unexpected rates after removal: %+v", day)
+	}
+	// До удаления евро был
+	if asOf, _ := s.RangeAsOf(ctx, oct(20), oct(20), clock.Add(-30*time.Minute)); len(asOf[oct(20)]) != 2 {
+		t.Errorf("Unexpected as-of rates: %+v", asOf)
+	}
+
+	// Валюта вернулась в день
+	clock = clock.Add(time.Hour)
+	s.Put(ctx, oct(20), rates(oct(20), 80, 90))
+	revisions, _ := s.Revisions(ctx, oct(20), oct(20))
+	if len(revisions) != 2 || !revisions[0].Removed() || revisions[0].Old.Rate != 90 || !revisions[1].Added() || revisions[1].Code() != "EUR" {
+		t.Errorf("Unexpected revisions: %+v", revisions)
+	}
+}
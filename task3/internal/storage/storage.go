@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"task3/internal/model"
+)
+
+// Store хранит историю курсов по ключу (дата, код валюты). Дата — день
+// запроса к источнику: на выходные ЦБ отдаёт курсы последнего рабочего дня,
+// их собственная дата остаётся в CurrencyRate.Date.
+type Store interface {
+	// Put записывает курсы, опубликованные на date. Повторная запись тех же
+	// значений ничего не меняет; новые значения заменяют прежние.
+	Put(ctx context.Context, date time.Time, rates []model.CurrencyRate) error
+	// Range возвращает сохранённые курсы за from..to включительно по дням
+	// запроса; дней без данных в результате нет.
+	Range(ctx context.Context, from, to time.Time) (map[time.Time][]model.CurrencyRate, error)
+}
+
+// History — хранилище, которое помнит прежние значения курсов и время, когда
+// каждое значение стало известно.
+type History interface {
+	Store
+	// RangeAsOf — как Range, но по состоянию знаний на asOf: значения,
+	// записанные позже, не видны.
+	RangeAsOf(ctx context.Context, from, to, asOf time.Time) (map[time.Time][]model.CurrencyRate, error)
+	// Remove отмечает, что валют rates больше нет в дне date.
+	Remove(ctx context.Context, date time.Time, rates []model.CurrencyRate) error
+	// Revisions возвращает исправления курсов за from..to по дням запроса.
+	Revisions(ctx context.Context, from, to time.Time) ([]model.Revision, error)
+}
+
+// Day отбрасывает время и зону: ключи хранилища — полночь UTC.
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
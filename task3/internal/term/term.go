@@ -0,0 +1,31 @@
+package term
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultWidth — ширина вывода, когда stdout не терминал и COLUMNS не задана.
+const DefaultWidth = 80
+
+// Detect определяет ширину вывода и можно ли использовать ANSI-цвета. Для
+// терминала ширина берётся из ioctl, иначе — из COLUMNS или DefaultWidth,
+// а цвет выключается, чтобы escape-коды не попадали в файлы и конвейеры.
+// NO_COLOR (https://no-color.org) выключает цвет всегда.
+func Detect(f *os.File) (width int, color bool) {
+	width, isTerminal := size(f.Fd())
+	if !isTerminal {
+		width = DefaultWidth
+		if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+			width = cols
+		}
+	}
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return width, isTerminal && !noColor
+}
+
+// IsTerminal сообщает, подключён ли f к терминалу.
+func IsTerminal(f *os.File) bool {
+	_, ok := size(f.Fd())
+	return ok
+}
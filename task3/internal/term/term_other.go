@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd)
+
+package term
+
+// size на остальных платформах не определяет терминал: вывод идёт без цвета
+// и с шириной по умолчанию.
+func size(fd uintptr) (int, bool) {
+	return 0, false
+}
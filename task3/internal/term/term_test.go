@@ -0,0 +1,39 @@
+package term
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect_NotTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("COLUMNS", "")
+	width, color := Detect(f)
+	if width != DefaultWidth || color {
+		t.Errorf("Expected fallback %d without color, got %d, %v", DefaultWidth, width, color)
+	}
+
+	// COLUMNS задаёт ширину, когда вывод перенаправлен
+	t.Setenv("COLUMNS", "132")
+	if width, _ := Detect(f); width != 132 {
+		t.Errorf("Expected width from COLUMNS, got %d", width)
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Error("Regular file must not be a terminal")
+	}
+}
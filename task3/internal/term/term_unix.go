@@ -0,0 +1,23 @@
+//go:build linux || darwin || freebsd
+
+package term
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	Row, Col, Xpixel, Ypixel uint16
+}
+
+// size запрашивает размер окна терминала; ошибка ioctl значит, что fd — не
+// терминал.
+func size(fd uintptr) (int, bool) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 || ws.Col == 0 {
+		return 0, false
+	}
+	return int(ws.Col), true
+}
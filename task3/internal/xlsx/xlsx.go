@@ -0,0 +1,307 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type Style int
+
+// Индексы стилей совпадают с порядком cellXfs в styles.xml.
+const (
+	StyleDefault Style = iota
+	StyleDate
+	StyleDecimal
+	StylePercent
+	StyleHeader
+	// StyleFilled — курс курсивом серым: значение восстановлено, а не
+	// опубликовано.
+	StyleFilled
+)
+
+// Cell — значение ячейки: string, float64, int, time.Time или nil (пустая).
+type Cell struct {
+	Value any
+	Style Style
+}
+
+func Text(s string) Cell     { return Cell{Value: s} }
+func Header(s string) Cell   { return Cell{Value: s, Style: StyleHeader} }
+func Number(v float64) Cell  { return Cell{Value: v} }
+func Decimal(v float64) Cell { return Cell{Value: v, Style: StyleDecimal} }
+func Percent(v float64) Cell { return Cell{Value: v, Style: StylePercent} }
+func Integer(v int) Cell     { return Cell{Value: v} }
+func Date(t time.Time) Cell  { return Cell{Value: t, Style: StyleDate} }
+func Filled(v float64) Cell  { return Cell{Value: v, Style: StyleFilled} }
+
+type Sheet struct {
+	Name string
+	Rows [][]Cell
+	// FreezeRows и FreezeCols закрепляют верхние строки и левые столбцы.
+	FreezeRows int
+	FreezeCols int
+	// Widths — ширина столбцов в символах; если не задана, считается по
+	// содержимому.
+	Widths []float64
+}
+
+// Workbook — минимальная книга Office Open XML (SpreadsheetML): числа, строки
+// и даты, закреплённые строки и ширина столбцов, без внешних зависимостей.
+type Workbook struct {
+	Sheets []Sheet
+}
+
+// Write записывает книгу в w как zip-архив .xlsx.
+func (wb *Workbook) Write(w io.Writer) error {
+	if len(wb.Sheets) == 0 {
+		return fmt.Errorf("workbook has no sheets")
+	}
+
+	z := zip.NewWriter(w)
+	parts := []struct {
+		name string
+		data string
+	}{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", wb.workbook()},
+		{"xl/_rels/workbook.xml.rels", wb.workbookRels()},
+		{"xl/styles.xml", styles},
+	}
+	for i, s := range wb.Sheets {
+		parts = append(parts, struct {
+			name string
+			data string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), s.xml()})
+	}
+
+	for _, p := range parts {
+		f, err := z.Create(p.name)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", p.name, err)
+		}
+		if _, err := io.WriteString(f, p.data); err != nil {
+			return fmt.Errorf("failed to write %s: %w", p.name, err)
+		}
+	}
+	return z.Close()
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles: 164 — дата, 165 — курс с четырьмя знаками, 10 — встроенный 0.00%.
+const styles = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy\-mm\-dd"/><numFmt numFmtId="165" formatCode="0.0000"/></numFmts>` +
+	`<fonts count="3"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><i/><sz val="11"/><color rgb="FF808080"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="6">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="10" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="165" fontId="2" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+func (wb *Workbook) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range wb.Sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (wb *Workbook) workbook() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range wb.Sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheetName(s.Name, i)), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (wb *Workbook) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range wb.Sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.Sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// sheetName приводит имя листа к ограничениям Excel: до 31 символа, без
+// []:*?/\.
+func sheetName(name string, index int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		return fmt.Sprintf("Sheet%d", index+1)
+	}
+	if utf8.RuneCountInString(name) > 31 {
+		name = string([]rune(name)[:31])
+	}
+	return name
+}
+
+func (s Sheet) xml() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	if s.FreezeRows > 0 || s.FreezeCols > 0 {
+		pane := "bottomRight"
+		switch {
+		case s.FreezeCols == 0:
+			pane = "bottomLeft"
+		case s.FreezeRows == 0:
+			pane = "topRight"
+		}
+		b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane`)
+		if s.FreezeCols > 0 {
+			fmt.Fprintf(&b, ` xSplit="%d"`, s.FreezeCols)
+		}
+		if s.FreezeRows > 0 {
+			fmt.Fprintf(&b, ` ySplit="%d"`, s.FreezeRows)
+		}
+		fmt.Fprintf(&b, ` topLeftCell="%s" activePane="%s" state="frozen"/></sheetView></sheetViews>`,
+			ref(s.FreezeRows, s.FreezeCols), pane)
+	}
+
+	if widths := s.widths(); len(widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, w := range widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%s" customWidth="1"/>`, i+1, i+1, strconv.FormatFloat(w, 'f', -1, 64))
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	for r, row := range s.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			writeCell(&b, ref(r, c), cell)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+func writeCell(b *strings.Builder, ref string, c Cell) {
+	style := ""
+	if c.Style != StyleDefault {
+		style = fmt.Sprintf(` s="%d"`, c.Style)
+	}
+	switch v := c.Value.(type) {
+	case nil:
+		// Пустая ячейка не пишется
+	case string:
+		fmt.Fprintf(b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, style, escape(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return
+		}
+		fmt.Fprintf(b, `<c r="%s"%s><v>%s</v></c>`, ref, style, strconv.FormatFloat(v, 'g', -1, 64))
+	case int:
+		fmt.Fprintf(b, `<c r="%s"%s><v>%d</v></c>`, ref, style, v)
+	case time.Time:
+		fmt.Fprintf(b, `<c r="%s"%s><v>%s</v></c>`, ref, style, strconv.FormatFloat(serial(v), 'g', -1, 64))
+	default:
+		fmt.Fprintf(b, `<c r="%s"%s t="inlineStr"><is><t>%s</t></is></c>`, ref, style, escape(fmt.Sprint(v)))
+	}
+}
+
+// widths возвращает заданную ширину столбцов или оценивает её по самому
+// длинному значению в столбце.
+func (s Sheet) widths() []float64 {
+	if s.Widths != nil {
+		return s.Widths
+	}
+	var widths []float64
+	for _, row := range s.Rows {
+		for c, cell := range row {
+			for len(widths) <= c {
+				widths = append(widths, minWidth)
+			}
+			widths[c] = max(widths[c], cellWidth(cell))
+		}
+	}
+	return widths
+}
+
+// minWidth — ширина столбца по умолчанию; нулевая ширина скрыла бы столбец.
+const minWidth = 8
+
+func cellWidth(c Cell) float64 {
+	const padding = 2
+	switch v := c.Value.(type) {
+	case nil:
+		return 0
+	case string:
+		return float64(utf8.RuneCountInString(v)) + padding
+	case time.Time:
+		return 10 + padding
+	default:
+		return 12
+	}
+}
+
+// epoch — нулевой день дат Excel (система 1900 с учётом ошибки 29.02.1900).
+var epoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// serial переводит дату в число дней Excel; время суток — дробная часть.
+func serial(t time.Time) float64 {
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	return t.Sub(epoch).Hours() / 24
+}
+
+// ref — адрес ячейки по номерам строки и столбца с нуля: (0, 0) → "A1".
+func ref(row, col int) string {
+	return column(col) + strconv.Itoa(row+1)
+}
+
+func column(col int) string {
+	var name []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = append([]byte{byte('A' + (col-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+)
+
+// worksheet — часть схемы SpreadsheetML, нужная для проверки результата.
+type worksheet struct {
+	Pane struct {
+		XSplit      int    `xml:"xSplit,attr"`
+		YSplit      int    `xml:"ySplit,attr"`
+		TopLeftCell string `xml:"topLeftCell,attr"`
+		State       string `xml:"state,attr"`
+	} `xml:"sheetViews>sheetView>pane"`
+	Cols []struct {
+		Min   int     `xml:"min,attr"`
+		Width float64 `xml:"width,attr"`
+	} `xml:"cols>col"`
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			S      int    `xml:"s,attr"`
+			T      string `xml:"t,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readPart(t *testing.T, r *zip.Reader, name string) []byte {
+	t.Helper()
+	f, err := r.Open(name)
+	if err != nil {
+		t.Fatalf("Missing part %s: %v", name, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestWorkbook_Write(t *testing.T) {
+	wb := &Workbook{Sheets: []Sheet{{
+		Name: "Курсы",
+		Rows: [][]Cell{
+			{Header("Дата"), Header("USD & EUR, руб.")},
+			{Date(time.Date(2025, 10, 21, 0, 0, 0, 0, time.UTC)), Decimal(81.5)},
+			{Date(time.Date(2025, 10, 22, 0, 0, 0, 0, time.UTC)), {}, Percent(-0.02), Integer(3)},
+		},
+		FreezeRows: 1,
+		FreezeCols: 1,
+	}, {
+		Name: "a/b",
+		Rows: [][]Cell{{Text("x")}},
+	}}}
+
+	var buf bytes.Buffer
+	if err := wb.Write(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Result is not a zip: %v", err)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet2.xml"} {
+		readPart(t, r, name)
+	}
+
+	var book struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(readPart(t, r, "xl/workbook.xml"), &book); err != nil {
+		t.Fatal(err)
+	}
+	if len(book.Sheets) != 2 || book.Sheets[0].Name != "Курсы" || book.Sheets[1].Name != "a_b" {
+		t.Errorf("Unexpected sheets: %+v", book.Sheets)
+	}
+
+	var ws worksheet
+	if err := xml.Unmarshal(readPart(t, r, "xl/worksheets/sheet1.xml"), &ws); err != nil {
+		t.Fatal(err)
+	}
+	if ws.Pane.State != "frozen" || ws.Pane.XSplit != 1 || ws.Pane.YSplit != 1 || ws.Pane.TopLeftCell != "B2" {
+		t.Errorf("Unexpected pane: %+v", ws.Pane)
+	}
+	// Ширина по самому длинному значению: заголовок из 15 символов + отступ
+	if len(ws.Cols) != 4 || ws.Cols[1].Width != 17 || ws.Cols[0].Width != 12 {
+		t.Errorf("Unexpected column widths: %+v", ws.Cols)
+	}
+
+	header := ws.Rows[0].Cells[1]
+	if header.T != "inlineStr" || header.Inline != "USD & EUR, руб." || header.S != int(StyleHeader) {
+		t.Errorf("Unexpected header cell: %+v", header)
+	}
+	date := ws.Rows[1].Cells[0]
+	// 21.10.2025 — день 45951 от 30.12.1899
+	if date.R != "A2" || date.T != "" || date.V != "45951" || date.S != int(StyleDate) {
+		t.Errorf("Unexpected date cell: %+v", date)
+	}
+	rate := ws.Rows[1].Cells[1]
+	if rate.T != "" || rate.V != "81.5" || rate.S != int(StyleDecimal) {
+		t.Errorf("Unexpected number cell: %+v", rate)
+	}
+	// Пустая ячейка пропущена, адреса следующих не сдвигаются
+	row := ws.Rows[2].Cells
+	if len(row) != 3 || row[1].R != "C3" || row[1].V != "-0.02" || row[1].S != int(StylePercent) || row[2].V != "3" {
+		t.Errorf("Unexpected row: %+v", row)
+	}
+}
+
+func TestWorkbook_Empty(t *testing.T) {
+	if err := (&Workbook{}).Write(io.Discard); err == nil {
+		t.Error("Expected error for workbook without sheets, got nil")
+	}
+}
+
+func TestColumn(t *testing.T) {
+	for col, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := column(col); got != expected {
+			t.Errorf("column(%d) = %s, expected %s", col, got, expected)
+		}
+	}
+}